@@ -0,0 +1,154 @@
+package jobj
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Provider names a model provider's structured output or tool calling dialect of JSON Schema.
+type Provider string
+
+const (
+	ProviderOpenAI       Provider = "openai"        // OpenAI function calling without strict mode
+	ProviderOpenAIStrict Provider = "openai-strict" // OpenAI structured outputs with "strict": true
+	ProviderAnthropic    Provider = "anthropic"     // Anthropic tool use input schemas
+	ProviderGemini       Provider = "gemini"        // Gemini function declarations (OpenAPI schema subset)
+)
+
+// capabilities lists the keywords emitted by this package that each provider honours. Keywords
+// missing from a provider's set are rejected or silently ignored by that provider.
+var capabilities = map[Provider]map[string]bool{
+	ProviderOpenAI: keywordSet(
+		"type", "properties", "required", "additionalProperties", "description", "title",
+		"anyOf", "const", "enum", "items", "format", "contentEncoding", "default",
+		"examples", "minItems", "maxItems", "uniqueItems", "minLength", "maxLength", "minimum",
+		"maximum", "$ref", "definitions", "oneOf", "deprecated",
+	),
+	ProviderOpenAIStrict: keywordSet(
+		"type", "properties", "required", "additionalProperties", "description", "title",
+		"anyOf", "const", "enum", "items", "format", "minItems", "maxItems", "minimum", "maximum",
+		"$ref", "definitions",
+	),
+	ProviderAnthropic: keywordSet(
+		"type", "properties", "required", "additionalProperties", "description", "title",
+		"anyOf", "const", "enum", "items", "format", "contentEncoding", "default",
+		"examples", "minItems", "maxItems", "uniqueItems", "minLength", "maxLength", "minimum",
+		"maximum", "$ref", "definitions", "oneOf", "deprecated",
+	),
+	ProviderGemini: keywordSet(
+		"type", "properties", "required", "description", "title", "anyOf", "enum", "items", "format",
+		"minItems", "maxItems", "minLength", "maxLength", "minimum", "maximum",
+	),
+}
+
+func keywordSet(keywords ...string) map[string]bool {
+	set := make(map[string]bool, len(keywords))
+	for _, keyword := range keywords {
+		set[keyword] = true
+	}
+	return set
+}
+
+// Providers returns the providers in the capability table, sorted by name.
+func Providers() []Provider {
+	providers := make([]Provider, 0, len(capabilities))
+	for provider := range capabilities {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i] < providers[j] })
+	return providers
+}
+
+// Capabilities returns the JSON Schema keywords provider supports, sorted, or nil for an unknown provider.
+func Capabilities(provider Provider) []string {
+	set, ok := capabilities[provider]
+	if !ok {
+		return nil
+	}
+	keywords := make([]string, 0, len(set))
+	for keyword := range set {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	return keywords
+}
+
+// Supports reports whether provider honours keyword.
+func Supports(provider Provider, keyword string) bool {
+	return capabilities[provider][keyword]
+}
+
+// CapabilityWarning reports a keyword in a schema that the target provider does not support.
+type CapabilityWarning struct {
+	Provider Provider
+	Keyword  string
+	Pointer  string // JSON Pointer to the schema object using the keyword
+}
+
+func (w CapabilityWarning) Error() string {
+	return fmt.Sprintf("%s: keyword %q at %q is not supported", w.Provider, w.Keyword, w.Pointer)
+}
+
+// CheckCapabilities returns a warning for every use of a keyword provider would reject or silently
+// ignore, so applications can catch schema features the target model will not see before deploying.
+// It checks the schema definition, the part sent to the model as tool parameters or response format,
+// and the shared definitions it references, reported under "/definitions/<name>". It returns an
+// error for an unknown provider.
+func (r *Schema) CheckCapabilities(provider Provider) ([]CapabilityWarning, error) {
+	supported, ok := capabilities[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+
+	var document struct {
+		Definitions map[string]interface{} `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(r.GetSchemaString()), &document); err != nil {
+		return nil, fmt.Errorf("failed to encode schema %q: %w", r.Name, err)
+	}
+
+	var warnings []CapabilityWarning
+	collectUnsupported(document.Definitions[r.Name], "", provider, supported, &warnings)
+	for name, definition := range document.Definitions {
+		if name != r.Name {
+			collectUnsupported(definition, "/definitions/"+escapePointerToken(name), provider, supported, &warnings)
+		}
+	}
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Pointer != warnings[j].Pointer {
+			return warnings[i].Pointer < warnings[j].Pointer
+		}
+		return warnings[i].Keyword < warnings[j].Keyword
+	})
+	return warnings, nil
+}
+
+func collectUnsupported(node interface{}, pointer string, provider Provider, supported map[string]bool, warnings *[]CapabilityWarning) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for keyword, child := range value {
+			if !supported[keyword] {
+				*warnings = append(*warnings, CapabilityWarning{Provider: provider, Keyword: keyword, Pointer: pointer})
+			}
+
+			childPointer := pointer + "/" + escapePointerToken(keyword)
+			switch keyword {
+			case "properties":
+				if properties, ok := child.(map[string]interface{}); ok {
+					for name, property := range properties {
+						collectUnsupported(property, childPointer+"/"+escapePointerToken(name), provider, supported, warnings)
+					}
+				}
+			case "const", "enum", "required", "$ref":
+				// Values, not schemas
+			default:
+				collectUnsupported(child, childPointer, provider, supported, warnings)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			collectUnsupported(item, fmt.Sprintf("%s/%d", pointer, i), provider, supported, warnings)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package jobj
+
+import (
+	"testing"
+)
+
+func TestCapabilityTable(t *testing.T) {
+	if !Supports(ProviderOpenAIStrict, "anyOf") {
+		t.Error("Expected OpenAI strict mode to support anyOf")
+	}
+	if Supports(ProviderOpenAIStrict, "contentEncoding") {
+		t.Error("Expected OpenAI strict mode not to support contentEncoding")
+	}
+	if Supports("unknown", "type") {
+		t.Error("Expected unknown provider to support nothing")
+	}
+	if Capabilities("unknown") != nil {
+		t.Error("Expected nil capabilities for unknown provider")
+	}
+	if len(Providers()) != len(capabilities) {
+		t.Errorf("Expected %d providers, got %v", len(capabilities), Providers())
+	}
+}
+
+func TestCheckCapabilities(t *testing.T) {
+	s := &Schema{
+		Name: "Upload",
+		Fields: []*Field{
+			Text("name").Required(),
+			Bytes("content"),
+			AnyOf("kind", []ConstDescription{{Const: "image"}, {Const: "text"}}),
+		},
+	}
+
+	warnings, err := s.CheckCapabilities(ProviderOpenAIStrict)
+	if err != nil {
+		t.Fatalf("CheckCapabilities failed: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Keyword != "contentEncoding" || warnings[0].Pointer != "/properties/content" {
+		t.Errorf("Expected a contentEncoding warning, got %v", warnings)
+	}
+
+	warnings, err = s.CheckCapabilities(ProviderAnthropic)
+	if err != nil || len(warnings) != 0 {
+		t.Errorf("Expected no warnings for Anthropic, got %v %v", warnings, err)
+	}
+
+	warnings, err = s.CheckCapabilities(ProviderGemini)
+	if err != nil {
+		t.Fatalf("CheckCapabilities failed: %v", err)
+	}
+	keywords := make(map[string]bool)
+	for _, warning := range warnings {
+		keywords[warning.Keyword] = true
+	}
+	for _, want := range []string{"additionalProperties", "const", "contentEncoding"} {
+		if !keywords[want] {
+			t.Errorf("Expected a %s warning for Gemini, got %v", want, warnings)
+		}
+	}
+
+	if _, err := s.CheckCapabilities("unknown"); err == nil {
+		t.Error("Expected error for unknown provider")
+	}
+}
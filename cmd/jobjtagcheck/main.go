@@ -0,0 +1,15 @@
+// Command jobjtagcheck runs the tagcheck analyzer, which reports missing and misspelled struct
+// tags on types used with funcschema. Run it on its own or through go vet:
+//
+//	jobjtagcheck ./...
+//	go vet -vettool=$(which jobjtagcheck) ./...
+package main
+
+import (
+	"github.com/mhpenta/jobj/funcschema/tagcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(tagcheck.Analyzer)
+}
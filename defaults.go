@@ -0,0 +1,196 @@
+package jobj
+
+// addAnnotations adds the keywords shared by every kind of field, title, default and examples, the
+// length, range and array cardinality keywords, the items description and nullability to their
+// rendered properties. Primitive fields render as
+// map[string]string, so annotated ones are converted to map[string]interface{} to carry values of
+// any JSON type.
+func addAnnotations(fields []*Field, properties map[string]interface{}) {
+	for _, field := range fields {
+		if !field.hasAnnotations() {
+			continue
+		}
+		var props map[string]interface{}
+		switch rendered := properties[field.ValueName].(type) {
+		case map[string]interface{}:
+			props = rendered
+		case map[string]string:
+			props = make(map[string]interface{}, len(rendered)+1)
+			for key, value := range rendered {
+				props[key] = value
+			}
+		default:
+			continue
+		}
+		if field.ValueTitle != "" {
+			props["title"] = field.ValueTitle
+		}
+		if field.ValueDefault != nil {
+			props["default"] = field.ValueDefault
+		}
+		if len(field.ValueExamples) > 0 {
+			props["examples"] = field.ValueExamples
+		}
+		if field.ValueDeprecated {
+			props["deprecated"] = true
+		}
+		addCardinality(field, props)
+		addBounds(field, props)
+		addItemsDescription(field, props)
+		addNullable(field, props)
+		properties[field.ValueName] = props
+	}
+}
+
+// hasAnnotations reports whether field sets any keyword added by addAnnotations.
+func (f *Field) hasAnnotations() bool {
+	return f.ValueTitle != "" || f.ValueDefault != nil || len(f.ValueExamples) > 0 || f.hasCardinality() || f.hasBounds() ||
+		f.ValueItemsDescription != "" || f.ValueNullable || f.ValueDeprecated
+}
+
+// addNullable adds "null" to the type of a nullable field's properties, or a {"type": "null"}
+// option to its anyOf. Fields rendered as a $ref or without a type already accept null or are
+// left as they are.
+func addNullable(field *Field, props map[string]interface{}) {
+	if !field.ValueNullable {
+		return
+	}
+	switch valueType := props["type"].(type) {
+	case string:
+		props["type"] = []string{valueType, "null"}
+		return
+	case DataType:
+		props["type"] = []string{string(valueType), "null"}
+		return
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		switch options := props[keyword].(type) {
+		case []map[string]interface{}:
+			props[keyword] = append(options, map[string]interface{}{"type": "null"})
+		case []interface{}:
+			props[keyword] = append(options, map[string]interface{}{"type": "null"})
+		}
+	}
+}
+
+// hasBounds reports whether field is a string with minLength or maxLength set, or a number with
+// minimum or maximum set.
+func (f *Field) hasBounds() bool {
+	switch f.ValueType {
+	case TypeString:
+		return f.ValueMinLength != nil || f.ValueMaxLength != nil
+	case TypeNumber, TypeInteger:
+		return f.ValueMinimum != nil || f.ValueMaximum != nil
+	}
+	return false
+}
+
+// addBounds adds the length keywords of a string field, or the range keywords of a number field,
+// to its rendered schema.
+func addBounds(field *Field, props map[string]interface{}) {
+	if !field.hasBounds() {
+		return
+	}
+	if field.ValueType == TypeString {
+		if field.ValueMinLength != nil {
+			props["minLength"] = *field.ValueMinLength
+		}
+		if field.ValueMaxLength != nil {
+			props["maxLength"] = *field.ValueMaxLength
+		}
+		return
+	}
+	if field.ValueMinimum != nil {
+		props["minimum"] = *field.ValueMinimum
+	}
+	if field.ValueMaximum != nil {
+		props["maximum"] = *field.ValueMaximum
+	}
+}
+
+// addItemsDescription sets the description of the rendered items schema of an array field.
+func addItemsDescription(field *Field, props map[string]interface{}) {
+	if field.ValueType != TypeArray || field.ValueItemsDescription == "" {
+		return
+	}
+	switch items := props["items"].(type) {
+	case map[string]interface{}:
+		items["description"] = field.ValueItemsDescription
+	case map[string]string:
+		items["description"] = field.ValueItemsDescription
+	}
+}
+
+// hasCardinality reports whether field is an array with minItems, maxItems or uniqueItems set.
+func (f *Field) hasCardinality() bool {
+	return f.ValueType == TypeArray && (f.ValueMinItems != nil || f.ValueMaxItems != nil || f.ValueUniqueItems)
+}
+
+// addCardinality adds the array cardinality keywords of field to its rendered schema.
+func addCardinality(field *Field, props map[string]interface{}) {
+	if !field.hasCardinality() {
+		return
+	}
+	if field.ValueMinItems != nil {
+		props["minItems"] = *field.ValueMinItems
+	}
+	if field.ValueMaxItems != nil {
+		props["maxItems"] = *field.ValueMaxItems
+	}
+	if field.ValueUniqueItems {
+		props["uniqueItems"] = true
+	}
+}
+
+// FillDefaults sets every field with a Default that is missing from document, a JSON value
+// decoded into an interface{}, to its default. Objects are filled at any depth: nested objects,
+// the objects of arrays and the struct values of maps. Fields present with a null value are left
+// as they are. Use it on model output before decoding, so an omitted field takes its documented
+// default rather than its Go zero value; see also safeunmarshal.ToWithDefaults.
+func (r *Schema) FillDefaults(document interface{}) {
+	if r.RootField != nil {
+		fillFieldDefaults(r.RootField, document)
+		return
+	}
+	if object, ok := document.(map[string]interface{}); ok {
+		fillDefaults(r.Fields, object)
+	}
+}
+
+func fillDefaults(fields []*Field, object map[string]interface{}) {
+	for _, field := range fields {
+		value, ok := object[field.ValueName]
+		if !ok {
+			if field.ValueDefault != nil {
+				object[field.ValueName] = field.ValueDefault
+			}
+			continue
+		}
+		fillFieldDefaults(field, value)
+	}
+}
+
+// fillFieldDefaults fills the defaults of the objects nested in value, which field describes.
+func fillFieldDefaults(field *Field, value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if !field.AdditionalProperties {
+			fillDefaults(field.SubFields, value)
+			return
+		}
+		if field.AdditionalPropertiesField == nil {
+			return
+		}
+		for _, item := range value {
+			fillFieldDefaults(field.AdditionalPropertiesField, item)
+		}
+	case []interface{}:
+		for _, item := range value {
+			if field.ArrayItemField != nil {
+				fillFieldDefaults(field.ArrayItemField, item)
+			} else if object, ok := item.(map[string]interface{}); ok {
+				fillDefaults(field.SubFields, object)
+			}
+		}
+	}
+}
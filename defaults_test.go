@@ -0,0 +1,107 @@
+package jobj
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultKeyword(t *testing.T) {
+	schema := &Schema{
+		Name: "Search",
+		Fields: []*Field{
+			Text("query").Required(),
+			Int("limit").Desc("Maximum results").Default(10),
+			Object("filter", []*Field{Text("language").Default("en")}),
+			Array("sorts", []*Field{Bool("descending").Default(true)}),
+			Text("mode"),
+		},
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(schema.GetSchemaString()), &document); err != nil {
+		t.Fatalf("GetSchemaString is not valid JSON: %v", err)
+	}
+	properties := document["definitions"].(map[string]interface{})["Search"].(map[string]interface{})["properties"].(map[string]interface{})
+	if limit := properties["limit"].(map[string]interface{}); limit["default"] != float64(10) || limit["description"] != "Maximum results" {
+		t.Errorf("Unexpected limit schema: %v", limit)
+	}
+	filter := properties["filter"].(map[string]interface{})["properties"].(map[string]interface{})
+	if filter["language"].(map[string]interface{})["default"] != "en" {
+		t.Errorf("Expected the nested default, got %v", filter)
+	}
+	sorts := properties["sorts"].(map[string]interface{})["items"].(map[string]interface{})["properties"].(map[string]interface{})
+	if sorts["descending"].(map[string]interface{})["default"] != true {
+		t.Errorf("Expected the array item default, got %v", sorts)
+	}
+	if _, ok := schema.FieldsJson()["mode"].(map[string]string); !ok {
+		t.Errorf("Expected fields without a default to render as before")
+	}
+
+	if outline := schema.ToPromptText(); !strings.Contains(outline, "- limit (integer, default 10): Maximum results") {
+		t.Errorf("Expected the default in the outline, got:\n%s", outline)
+	}
+}
+
+func TestFillDefaults(t *testing.T) {
+	schema := &Schema{
+		Name: "Search",
+		Fields: []*Field{
+			Text("query").Required(),
+			Int("limit").Default(10),
+			Text("mode").Default("fast"),
+			Array("sorts", []*Field{Text("field"), Bool("descending").Default(true)}),
+		},
+	}
+
+	var document interface{}
+	if err := json.Unmarshal([]byte(`{"query": "jobj", "mode": null, "sorts": [{"field": "date"}, {"field": "title", "descending": false}]}`), &document); err != nil {
+		t.Fatal(err)
+	}
+	schema.FillDefaults(document)
+
+	expected := map[string]interface{}{
+		"query": "jobj",
+		"limit": 10,
+		"mode":  nil,
+		"sorts": []interface{}{
+			map[string]interface{}{"field": "date", "descending": true},
+			map[string]interface{}{"field": "title", "descending": false},
+		},
+	}
+	if !reflect.DeepEqual(document, expected) {
+		t.Errorf("FillDefaults = %v, want %v", document, expected)
+	}
+}
+
+func TestExamplesKeyword(t *testing.T) {
+	schema := &Schema{
+		Name: "Search",
+		Fields: []*Field{
+			Text("query").Desc("Search query").Examples("jobj schema", "golang json").Required(),
+			Int("limit").Examples(5),
+			Object("filter", []*Field{Text("language").Examples("en")}),
+		},
+	}
+
+	properties := schema.FieldsJson()
+	query := properties["query"].(map[string]interface{})
+	if !reflect.DeepEqual(query["examples"], []interface{}{"jobj schema", "golang json"}) || query["description"] != "Search query" {
+		t.Errorf("Unexpected query schema: %v", query)
+	}
+	if compact := schema.GetCompactSchemaString(CompactOptions{}); !strings.Contains(compact, `"examples":["en"]`) {
+		t.Errorf("Expected nested examples in %s", compact)
+	}
+
+	example := schema.Example().(map[string]interface{})
+	if example["query"] != "jobj schema" || example["limit"] != 5 {
+		t.Errorf("Expected Example to use the first example, got %v", example)
+	}
+
+	outline := schema.ToPromptText()
+	if !strings.Contains(outline, `- query (string, required): Search query; e.g. "jobj schema", "golang json"`) ||
+		!strings.Contains(outline, "- limit (integer): e.g. 5") {
+		t.Errorf("Expected examples in the outline, got:\n%s", outline)
+	}
+}
@@ -0,0 +1,139 @@
+package jobj
+
+import (
+	"encoding/json"
+)
+
+// Definition names the shape of an object field, or of the items of an array of objects, so a
+// shape used by several fields can be written once. GetSchemaString and GetCompactSchemaString
+// emit a shape shared by two or more fields under "definitions" and reference it from each field
+// with "$ref" instead of repeating it, which keeps prompts short when e.g. an Address struct
+// appears as both a billing and a shipping address. funcschema names nested structs after their
+// Go type. Shapes used once, and names given to fields of different shapes, stay inline.
+func (vb *Field) Definition(name string) *Field {
+	vb.ValueDefinition = name
+	return vb
+}
+
+// referenceProperties returns the schema of a field that references a shared definition.
+func referenceProperties(field *Field) map[string]interface{} {
+	props := map[string]interface{}{"$ref": field.ValueRef}
+	if field.ValueDescription != "" {
+		props["description"] = field.ValueDescription
+	}
+	return props
+}
+
+// ShareDefinitions returns a copy of the schema in which the fields whose Definition is shared,
+// as described by Definition, reference it, along with the JSON schema of every shared
+// definition by name. References are the definition's name prefixed by definitionsPath, e.g.
+// "#/definitions/". A definition named like the schema is never shared, since the schema's own
+// definition takes that name, and the definitions are finished like the schema's own; see
+// FinishDocument. The schema is returned as is when nothing is shared.
+func (r *Schema) ShareDefinitions(definitionsPath string) (*Schema, map[string]interface{}) {
+	shapes := make(map[string]map[string]bool)
+	uses := make(map[string]int)
+	first := make(map[string][]*Field)
+	var collect func(field *Field)
+	collect = func(field *Field) {
+		if field == nil {
+			return
+		}
+		if subFields, ok := definitionShape(field); ok {
+			if shapes[field.ValueDefinition] == nil {
+				shapes[field.ValueDefinition] = make(map[string]bool)
+				first[field.ValueDefinition] = subFields
+			}
+			shapes[field.ValueDefinition][shapeKey(subFields)] = true
+			uses[field.ValueDefinition]++
+		}
+		for _, subField := range field.SubFields {
+			collect(subField)
+		}
+		collect(field.ArrayItemField)
+		collect(field.AdditionalPropertiesField)
+	}
+	for _, field := range r.Fields {
+		collect(field)
+	}
+	collect(r.RootField)
+
+	shared := make(map[string]bool)
+	for name, count := range uses {
+		if count > 1 && len(shapes[name]) == 1 && name != r.Name {
+			shared[name] = true
+		}
+	}
+	if len(shared) == 0 {
+		return r, nil
+	}
+
+	var rewrite func(field *Field) *Field
+	rewriteAll := func(fields []*Field) []*Field {
+		if fields == nil {
+			return nil
+		}
+		rewritten := make([]*Field, len(fields))
+		for i, field := range fields {
+			rewritten[i] = rewrite(field)
+		}
+		return rewritten
+	}
+	rewrite = func(field *Field) *Field {
+		if field == nil {
+			return nil
+		}
+		copied := *field
+		if _, ok := definitionShape(field); ok && shared[field.ValueDefinition] {
+			reference := definitionsPath + field.ValueDefinition
+			if field.ValueType == TypeArray {
+				copied.SubFields = nil
+				copied.ArrayItemField = &Field{ValueType: TypeObject, ValueRef: reference}
+				return &copied
+			}
+			copied.SubFields = nil
+			copied.ValueRef = reference
+			return &copied
+		}
+		copied.SubFields = rewriteAll(field.SubFields)
+		copied.ArrayItemField = rewrite(field.ArrayItemField)
+		copied.AdditionalPropertiesField = rewrite(field.AdditionalPropertiesField)
+		return &copied
+	}
+
+	schema := *r
+	schema.Fields = rewriteAll(r.Fields)
+	schema.RootField = rewrite(r.RootField)
+
+	definitions := make(map[string]interface{}, len(shared))
+	for name := range shared {
+		definition := fieldProperties(Object(name, rewriteAll(first[name]))).(map[string]interface{})
+		delete(definition, "description")
+		r.FinishDocument(definition)
+		definitions[name] = definition
+	}
+	return &schema, definitions
+}
+
+// definitionShape returns the fields describing the shape a field's Definition names.
+func definitionShape(field *Field) ([]*Field, bool) {
+	if field.ValueDefinition == "" || field.ValueAnyOf != nil || field.SubFields == nil {
+		return nil, false
+	}
+	switch {
+	case field.ValueType == TypeObject && !field.AdditionalProperties:
+		return field.SubFields, true
+	case field.ValueType == TypeArray && field.ArrayItemType == "" && field.ArrayItemField == nil:
+		return field.SubFields, true
+	}
+	return nil, false
+}
+
+// shapeKey identifies an object shape by its rendered schema.
+func shapeKey(subFields []*Field) string {
+	encoded, err := json.Marshal(fieldProperties(Object("", subFields)))
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
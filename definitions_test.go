@@ -0,0 +1,139 @@
+package jobj
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func address() []*Field {
+	return []*Field{
+		Text("street").Desc("Street and number").Required(),
+		Text("city").Desc("City").Required(),
+	}
+}
+
+func TestShareDefinitions(t *testing.T) {
+	schema := &Schema{
+		Name: "Order",
+		Fields: []*Field{
+			Object("billing", address()).Definition("Address").Desc("Billing address").Required(),
+			Object("shipping", address()).Definition("Address").Desc("Shipping address"),
+			Array("stops", address()).Definition("Address").Desc("Intermediate stops"),
+			Object("origin", []*Field{Float("lat"), Float("lng")}).Definition("Point"),
+			Object("left", []*Field{Text("a")}).Definition("Side"),
+			Object("right", []*Field{Text("b")}).Definition("Side"),
+		},
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(schema.GetSchemaString()), &document); err != nil {
+		t.Fatalf("GetSchemaString is not valid JSON: %v", err)
+	}
+	definitions := document["definitions"].(map[string]interface{})
+	if len(definitions) != 2 {
+		t.Fatalf("Expected the Order and Address definitions, got %v", definitions)
+	}
+
+	shared := definitions["Address"].(map[string]interface{})
+	if _, ok := shared["description"]; ok {
+		t.Errorf("Expected the shared definition to carry no field description, got %v", shared)
+	}
+	if properties := shared["properties"].(map[string]interface{}); len(properties) != 2 {
+		t.Errorf("Unexpected Address properties: %v", properties)
+	}
+
+	properties := definitions["Order"].(map[string]interface{})["properties"].(map[string]interface{})
+	billing := properties["billing"].(map[string]interface{})
+	if billing["$ref"] != "#/definitions/Address" || billing["description"] != "Billing address" {
+		t.Errorf("Expected billing to reference Address, got %v", billing)
+	}
+	stops := properties["stops"].(map[string]interface{})
+	if stops["type"] != "array" || stops["items"].(map[string]interface{})["$ref"] != "#/definitions/Address" {
+		t.Errorf("Expected stops to be an array of Address references, got %v", stops)
+	}
+	if _, ok := properties["origin"].(map[string]interface{})["properties"]; !ok {
+		t.Errorf("Expected a definition used once to stay inline")
+	}
+	if _, ok := properties["left"].(map[string]interface{})["properties"]; !ok {
+		t.Errorf("Expected a name shared by different shapes to stay inline")
+	}
+
+	required := definitions["Order"].(map[string]interface{})["required"].([]interface{})
+	if len(required) != 1 || required[0] != "billing" {
+		t.Errorf("Expected required to be unchanged, got %v", required)
+	}
+
+	compact := schema.GetCompactSchemaString(CompactOptions{StripDescriptions: true})
+	if strings.Count(compact, `"street":{`) != 1 || strings.Contains(compact, "Billing address") {
+		t.Errorf("Expected the compact document to share Address and strip descriptions, got %s", compact)
+	}
+
+	// The schema itself is not modified and still describes every field inline
+	if schema.Fields[0].ValueRef != "" || len(schema.Fields[0].SubFields) != 2 {
+		t.Errorf("Expected ShareDefinitions to leave the schema unchanged")
+	}
+	if problems, err := schema.ValidateJSON([]byte(`{"billing": {"street": "1 Main St"}}`)); err != nil || len(problems) != 1 {
+		t.Errorf("Expected validation against the inline fields, got %v, %v", problems, err)
+	}
+}
+
+func TestShareDefinitionsNothingShared(t *testing.T) {
+	schema := &Schema{
+		Name:   "Order",
+		Fields: []*Field{Object("billing", address()).Definition("Address")},
+	}
+	if shared, definitions := schema.ShareDefinitions(definitionsPath); shared != schema || definitions != nil {
+		t.Errorf("Expected the schema to be returned as is")
+	}
+}
+
+func TestShareDefinitionsFinished(t *testing.T) {
+	optional := func() []*Field {
+		return []*Field{Text("note"), Text("label")}
+	}
+	schema := &Schema{
+		Name:          "Order",
+		RequiredOrder: SortedOrder,
+		Fields: []*Field{
+			Object("billing", optional()).Definition("Address"),
+			Object("shipping", optional()).Definition("Address"),
+			Object("from", []*Field{Text("zone").Required(), Text("city").Required()}).Definition("Place"),
+			Object("to", []*Field{Text("zone").Required(), Text("city").Required()}).Definition("Place"),
+		},
+	}
+
+	var document struct {
+		Definitions map[string]map[string]interface{} `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(schema.GetSchemaString()), &document); err != nil {
+		t.Fatalf("GetSchemaString is not valid JSON: %v", err)
+	}
+	if required, ok := document.Definitions["Address"]["required"]; ok {
+		t.Errorf("Expected required to be omitted from Address, got %v", required)
+	}
+	place := document.Definitions["Place"]["required"].([]interface{})
+	if len(place) != 2 || place[0] != "city" || place[1] != "zone" {
+		t.Errorf("Expected Place required in sorted order, got %v", place)
+	}
+
+	for _, provider := range []Provider{ProviderOpenAI, ProviderOpenAIStrict, ProviderAnthropic} {
+		warnings, err := schema.CheckCapabilities(provider)
+		if err != nil {
+			t.Fatalf("CheckCapabilities returned error: %v", err)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("Expected no %s warnings for shared definitions, got %v", provider, warnings)
+		}
+	}
+
+	schema.Fields[0].SubFields[0].Examples("c/o")
+	schema.Fields[1].SubFields[0].Examples("c/o")
+	warnings, err := schema.CheckCapabilities(ProviderOpenAIStrict)
+	if err != nil {
+		t.Fatalf("CheckCapabilities returned error: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Pointer != "/definitions/Address/properties/note" || warnings[0].Keyword != "examples" {
+		t.Errorf("Expected an examples warning inside the Address definition, got %v", warnings)
+	}
+}
@@ -0,0 +1,191 @@
+package jobj
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ChangeKind says how a value differs between two instances compared by Diff.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"    // Present only in the second instance
+	ChangeRemoved  ChangeKind = "removed"  // Present only in the first instance
+	ChangeModified ChangeKind = "modified" // Present in both with different values
+)
+
+// Change is one difference between two instances of a schema. Pointer is an RFC 6901 JSON
+// Pointer to the value, Before and After hold the decoded values, with numbers as json.Number;
+// Before is nil for added values and After for removed ones.
+type Change struct {
+	Pointer string      `json:"pointer"`
+	Kind    ChangeKind  `json:"kind"`
+	Before  interface{} `json:"before"`
+	After   interface{} `json:"after"`
+}
+
+func (c Change) String() string {
+	pointer := c.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s: added %s", pointer, promptValue(c.After))
+	case ChangeRemoved:
+		return fmt.Sprintf("%s: removed %s", pointer, promptValue(c.Before))
+	}
+	return fmt.Sprintf("%s: %s -> %s", pointer, promptValue(c.Before), promptValue(c.After))
+}
+
+// Diff compares two JSON instances of the schema field by field and returns their differences,
+// for evaluating prompt or model changes against the same inputs. The schema guides the
+// comparison: objects are compared by property in declaration order, with undeclared
+// properties following in name order; maps by key; arrays item by item, reporting items past the
+// end of the shorter array as added or removed; and numbers by value, so 1 and 1.0 are equal. A
+// value whose type differs between the instances is reported as modified as a whole. Both
+// instances are expected to have been validated; values that do not match the schema are still
+// compared, as plain JSON. A nil slice means the instances are equal. The error return is only
+// used when either instance is not well-formed JSON.
+func (r *Schema) Diff(before, after []byte) ([]Change, error) {
+	beforeValue, err := decodeInstance(before)
+	if err != nil {
+		return nil, fmt.Errorf("first instance: %w", err)
+	}
+	afterValue, err := decodeInstance(after)
+	if err != nil {
+		return nil, fmt.Errorf("second instance: %w", err)
+	}
+
+	var changes []Change
+	if r.RootField != nil {
+		diffValue(r.RootField, beforeValue, afterValue, "", &changes)
+	} else {
+		diffValue(Object("", r.Fields), beforeValue, afterValue, "", &changes)
+	}
+	return changes, nil
+}
+
+func decodeInstance(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return value, nil
+}
+
+// diffValue compares two values described by field; field may be nil for undeclared values.
+func diffValue(field *Field, before, after interface{}, pointer string, changes *[]Change) {
+	switch beforeValue := before.(type) {
+	case map[string]interface{}:
+		afterValue, ok := after.(map[string]interface{})
+		if !ok {
+			break
+		}
+		var fields []*Field
+		var values *Field
+		if field != nil && field.ValueAnyOf == nil {
+			if field.AdditionalProperties {
+				values = field.AdditionalPropertiesField
+				if values == nil && field.AdditionalPropertiesType != "" {
+					values = &Field{ValueType: field.AdditionalPropertiesType}
+				}
+			} else {
+				fields = field.SubFields
+			}
+		}
+		diffObject(fields, values, beforeValue, afterValue, pointer, changes)
+		return
+	case []interface{}:
+		afterValue, ok := after.([]interface{})
+		if !ok {
+			break
+		}
+		var items *Field
+		if field != nil && field.ValueAnyOf == nil {
+			switch {
+			case field.ArrayItemField != nil:
+				items = field.ArrayItemField
+			case field.ArrayItemType != "":
+				items = &Field{ValueType: field.ArrayItemType}
+			case field.SubFields != nil:
+				items = Object("", field.SubFields)
+			}
+		}
+		for i := 0; i < len(beforeValue) || i < len(afterValue); i++ {
+			itemPointer := pointer + "/" + strconv.Itoa(i)
+			switch {
+			case i >= len(afterValue):
+				*changes = append(*changes, Change{Pointer: itemPointer, Kind: ChangeRemoved, Before: beforeValue[i]})
+			case i >= len(beforeValue):
+				*changes = append(*changes, Change{Pointer: itemPointer, Kind: ChangeAdded, After: afterValue[i]})
+			default:
+				diffValue(items, beforeValue[i], afterValue[i], itemPointer, changes)
+			}
+		}
+		return
+	case json.Number:
+		if afterValue, ok := after.(json.Number); ok && numbersEqual(beforeValue, afterValue) {
+			return
+		}
+	default:
+		if jsonEqual(before, after) {
+			return
+		}
+	}
+	*changes = append(*changes, Change{Pointer: pointer, Kind: ChangeModified, Before: before, After: after})
+}
+
+// diffObject compares two objects with the declared fields, whose other properties are
+// described by values, or by nothing if values is nil.
+func diffObject(fields []*Field, values *Field, before, after map[string]interface{}, pointer string, changes *[]Change) {
+	declared := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		declared[field.ValueName] = true
+		diffProperty(field, field.ValueName, before, after, pointer, changes)
+	}
+
+	var others []string
+	for name := range before {
+		if !declared[name] {
+			others = append(others, name)
+		}
+	}
+	for name := range after {
+		if _, inBefore := before[name]; !declared[name] && !inBefore {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	for _, name := range others {
+		diffProperty(values, name, before, after, pointer, changes)
+	}
+}
+
+func diffProperty(field *Field, name string, before, after map[string]interface{}, pointer string, changes *[]Change) {
+	propertyPointer := pointer + "/" + escapePointerToken(name)
+	beforeValue, inBefore := before[name]
+	afterValue, inAfter := after[name]
+	switch {
+	case inBefore && inAfter:
+		diffValue(field, beforeValue, afterValue, propertyPointer, changes)
+	case inBefore:
+		*changes = append(*changes, Change{Pointer: propertyPointer, Kind: ChangeRemoved, Before: beforeValue})
+	case inAfter:
+		*changes = append(*changes, Change{Pointer: propertyPointer, Kind: ChangeAdded, After: afterValue})
+	}
+}
+
+func numbersEqual(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+	x, errX := a.Float64()
+	y, errY := b.Float64()
+	return errX == nil && errY == nil && x == y
+}
@@ -0,0 +1,66 @@
+package jobj
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	schema := &Schema{
+		Name: "Filing",
+		Fields: []*Field{
+			Text("company").Required(),
+			Float("revenue"),
+			ArrayOf("tags", TypeString),
+			Array("officers", []*Field{Text("name"), Text("title")}),
+			Map("ratios", TypeNumber),
+		},
+	}
+
+	before := `{"company": "Acme", "revenue": 1.0, "tags": ["tech", "small"],
+		"officers": [{"name": "Ann", "title": "CEO"}], "ratios": {"pe": 12, "pb": 2}, "note": "x"}`
+	after := `{"company": "Acme Inc", "revenue": 1, "tags": ["tech"],
+		"officers": [{"name": "Ann", "title": "CFO"}, {"name": "Bob"}], "ratios": {"pe": 12, "ps": 3}}`
+
+	changes, err := schema.Diff([]byte(before), []byte(after))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	expected := []Change{
+		{Pointer: "/company", Kind: ChangeModified, Before: "Acme", After: "Acme Inc"},
+		{Pointer: "/tags/1", Kind: ChangeRemoved, Before: "small"},
+		{Pointer: "/officers/0/title", Kind: ChangeModified, Before: "CEO", After: "CFO"},
+		{Pointer: "/officers/1", Kind: ChangeAdded, After: map[string]interface{}{"name": "Bob"}},
+		{Pointer: "/ratios/pb", Kind: ChangeRemoved, Before: json.Number("2")},
+		{Pointer: "/ratios/ps", Kind: ChangeAdded, After: json.Number("3")},
+		{Pointer: "/note", Kind: ChangeRemoved, Before: "x"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Diff =\n%v\nwant\n%v", changes, expected)
+	}
+	if got := changes[0].String(); got != `/company: "Acme" -> "Acme Inc"` {
+		t.Errorf("Unexpected String: %s", got)
+	}
+
+	changes, err = schema.Diff([]byte(after), []byte(after))
+	if err != nil || changes != nil {
+		t.Errorf("Expected no changes between equal instances, got %v, %v", changes, err)
+	}
+
+	if _, err := schema.Diff([]byte(`{`), []byte(after)); err == nil {
+		t.Errorf("Expected an error for malformed JSON")
+	}
+}
+
+func TestDiffRootAndTypeChange(t *testing.T) {
+	schema := &Schema{Name: "Scores", RootField: ArrayOf("", TypeNumber)}
+	changes, err := schema.Diff([]byte(`[1, 2]`), []byte(`[1, "two"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{{Pointer: "/1", Kind: ChangeModified, Before: json.Number("2"), After: "two"}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Diff = %v, want %v", changes, expected)
+	}
+}
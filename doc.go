@@ -17,5 +17,10 @@
 //	}
 //
 // The package supports basic types, arrays, objects and enums. External schema
-// references are not supported. XML support is limited to basic type mapping.
+// references are not supported. GetXMLSchemaString renders the same fields as an XML Schema
+// for prompts that ask for XML output.
+//
+// The jobj and funcschema packages build for WebAssembly (GOARCH=wasm) and with TinyGo, so
+// schemas can be generated in the browser. Under TinyGo, errors that cannot be returned are
+// printed with println instead of logged with log/slog.
 package jobj
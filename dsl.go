@@ -0,0 +1,190 @@
+package jobj
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseDSL parses a compact text description of a schema, so extraction schemas can be authored
+// and stored as config by people who don't write Go. Each line declares one field:
+//
+//	headline: string! "The exact headline from the press release"
+//	confidence: number "Confidence in the headline extracted"
+//	tags: []string "Keywords from the headline"
+//	sentiment: enum(positive, neutral, negative)! "Overall tone"
+//	source: object {
+//	    name: string!
+//	    published: date
+//	}
+//	quotes: []object! "Quotes in the press release" {
+//	    speaker: string!
+//	    text: string!
+//	}
+//
+// A trailing "!" marks the field as required and the optional quoted string is its description.
+// Supported types are string, number, integer (or int), boolean (or bool), date, datetime, email,
+// uri, uuid, enum(...), object, and arrays of any of these written with a "[]" prefix. Object and
+// []object fields open a block with "{" that is closed by a line containing only "}". Blank lines
+// and lines starting with "#" are ignored.
+func ParseDSL(name string, src string) (*Schema, error) {
+	// stack holds the field lists being filled; the bottom entry is the schema root
+	stack := [][]*Field{{}}
+	owners := []*Field{nil}
+
+	for i, line := range strings.Split(src, "\n") {
+		lineNumber := i + 1
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if line == "}" {
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("line %d: unexpected \"}\"", lineNumber)
+			}
+			owners[len(owners)-1].SubFields = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			owners = owners[:len(owners)-1]
+			continue
+		}
+
+		field, opensBlock, err := parseDSLLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		top := len(stack) - 1
+		stack[top] = append(stack[top], field)
+		if opensBlock {
+			stack = append(stack, []*Field{})
+			owners = append(owners, field)
+		}
+	}
+
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("unclosed block for field %q", owners[len(owners)-1].ValueName)
+	}
+
+	return &Schema{
+		Name:   name,
+		Fields: stack[0],
+	}, nil
+}
+
+func parseDSLLine(line string) (*Field, bool, error) {
+	colon := strings.Index(line, ":")
+	if colon <= 0 {
+		return nil, false, fmt.Errorf("expected \"name: type\", got %q", line)
+	}
+	name := strings.TrimSpace(line[:colon])
+	rest := strings.TrimSpace(line[colon+1:])
+
+	// Type token; enum(...) may contain spaces so it is read up to the closing parenthesis
+	var typeToken string
+	if strings.HasPrefix(rest, "enum(") || strings.HasPrefix(rest, "[]enum(") {
+		end := strings.Index(rest, ")")
+		if end == -1 {
+			return nil, false, fmt.Errorf("field %q: unterminated enum", name)
+		}
+		typeToken, rest = rest[:end+1], rest[end+1:]
+	} else {
+		end := strings.IndexAny(rest, " \t!{\"")
+		if end == -1 {
+			end = len(rest)
+		}
+		typeToken, rest = rest[:end], rest[end:]
+	}
+
+	required := strings.HasPrefix(rest, "!")
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, "!"))
+
+	var description string
+	if strings.HasPrefix(rest, "\"") {
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return nil, false, fmt.Errorf("field %q: invalid description: %w", name, err)
+		}
+		description, _ = strconv.Unquote(quoted)
+		rest = strings.TrimSpace(rest[len(quoted):])
+	}
+
+	opensBlock := rest == "{"
+	if rest != "" && !opensBlock {
+		return nil, false, fmt.Errorf("field %q: unexpected %q", name, rest)
+	}
+
+	field, err := dslField(name, typeToken)
+	if err != nil {
+		return nil, false, err
+	}
+
+	isObject := field.ValueType == TypeObject || (field.ValueType == TypeArray && field.ArrayItemType == "")
+	if isObject != opensBlock {
+		if isObject {
+			return nil, false, fmt.Errorf("field %q: %s must be followed by \"{\"", name, typeToken)
+		}
+		return nil, false, fmt.Errorf("field %q: only object fields open a block", name)
+	}
+
+	field.Desc(description)
+	if required {
+		field.Required()
+	}
+	return field, opensBlock, nil
+}
+
+func dslField(name string, typeToken string) (*Field, error) {
+	if strings.HasPrefix(typeToken, "[]") {
+		itemToken := strings.TrimPrefix(typeToken, "[]")
+		if itemToken == "object" {
+			return Array(name, nil), nil
+		}
+		item, err := dslField(name, itemToken)
+		if err != nil {
+			return nil, err
+		}
+		if item.ValueAnyOf != nil || item.ValueType == TypeObject || item.ValueType == TypeArray {
+			return nil, fmt.Errorf("field %q: unsupported array item type %q", name, itemToken)
+		}
+		return ArrayOf(name, item.ValueType), nil
+	}
+
+	if strings.HasPrefix(typeToken, "enum(") && strings.HasSuffix(typeToken, ")") {
+		values := strings.Split(typeToken[len("enum("):len(typeToken)-1], ",")
+		enums := make([]ConstDescription, 0, len(values))
+		for _, value := range values {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return nil, fmt.Errorf("field %q: empty enum value", name)
+			}
+			enums = append(enums, ConstDescription{Const: value})
+		}
+		return AnyOf(name, enums), nil
+	}
+
+	switch typeToken {
+	case "string":
+		return Text(name), nil
+	case "number":
+		return Float(name), nil
+	case "integer", "int":
+		return Int(name), nil
+	case "boolean", "bool":
+		return Bool(name), nil
+	case "date":
+		return Date(name), nil
+	case "datetime":
+		return DateTime(name), nil
+	case "email":
+		return Email(name), nil
+	case "uri":
+		return URI(name), nil
+	case "uuid":
+		return UUID(name), nil
+	case "object":
+		return Object(name, nil), nil
+	default:
+		return nil, fmt.Errorf("field %q: unknown type %q", name, typeToken)
+	}
+}
@@ -0,0 +1,82 @@
+package jobj
+
+import (
+	"testing"
+)
+
+func TestParseDSL(t *testing.T) {
+	src := `
+# Press release extraction
+headline: string! "The exact headline"
+confidence: number
+tags: []string "Keywords"
+sentiment: enum(positive, neutral, negative)! "Overall tone"
+source: object {
+    name: string!
+    published: date
+}
+quotes: []object! "Quotes in the press release" {
+    speaker: string!
+    count: int
+}
+`
+
+	s, err := ParseDSL("PressRelease", src)
+	if err != nil {
+		t.Fatalf("ParseDSL failed: %v", err)
+	}
+
+	if s.Name != "PressRelease" || len(s.Fields) != 6 {
+		t.Fatalf("Unexpected schema: %+v", s)
+	}
+
+	headline := s.Fields[0]
+	if headline.ValueType != TypeString || !headline.ValueRequired || headline.ValueDescription != "The exact headline" {
+		t.Errorf("Unexpected headline field: %+v", headline)
+	}
+	if s.Fields[1].ValueType != TypeNumber || s.Fields[1].ValueRequired {
+		t.Errorf("Unexpected confidence field: %+v", s.Fields[1])
+	}
+	if s.Fields[2].ValueType != TypeArray || s.Fields[2].ArrayItemType != TypeString {
+		t.Errorf("Unexpected tags field: %+v", s.Fields[2])
+	}
+
+	sentiment := s.Fields[3]
+	if len(sentiment.ValueAnyOf) != 3 || sentiment.ValueAnyOf[1].Const != "neutral" || !sentiment.ValueRequired {
+		t.Errorf("Unexpected sentiment field: %+v", sentiment)
+	}
+
+	source := s.Fields[4]
+	if source.ValueType != TypeObject || len(source.SubFields) != 2 || !source.SubFields[0].ValueRequired {
+		t.Errorf("Unexpected source field: %+v", source)
+	}
+
+	quotes := s.Fields[5]
+	if quotes.ValueType != TypeArray || len(quotes.SubFields) != 2 || quotes.SubFields[1].ValueType != TypeInteger {
+		t.Errorf("Unexpected quotes field: %+v", quotes)
+	}
+}
+
+func TestParseDSLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"Missing type", "headline"},
+		{"Unknown type", "headline: text"},
+		{"Object without block", "source: object"},
+		{"Block on primitive", "headline: string {"},
+		{"Unclosed block", "source: object {\nname: string"},
+		{"Unexpected close", "}"},
+		{"Trailing garbage", `headline: string "desc" extra`},
+		{"Unterminated enum", "kind: enum(a, b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseDSL("Broken", tt.src); err == nil {
+				t.Errorf("Expected error for %q", tt.src)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package jobj
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// JsonDuration is a custom duration type that can be unmarshalled from JSON duration strings.
+// It embeds time.Duration and is the Go counterpart of fields created with Duration; funcschema
+// maps JsonDuration struct fields to Duration fields.
+type JsonDuration struct {
+	time.Duration
+}
+
+// UnmarshalJSON is the custom unmarshaling method for JsonDuration.
+// It accepts ISO 8601 durations such as "PT1H30M" or "P2DT3H", which is what the "duration"
+// format asks models to produce, and falls back to Go duration strings such as "1h30m".
+//
+// Parameters:
+//   - data: The JSON data to be unmarshalled, represented as a byte slice.
+//
+// Returns:
+//   - error: An error if the JSON data is invalid or the string is not a recognised duration.
+//     Returns nil if the unmarshaling is successful.
+func (d *JsonDuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON data: %v", err)
+	}
+	duration, err := parseISO8601Duration(s)
+	if err != nil {
+		duration, err = time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration format: %q", s)
+		}
+	}
+	d.Duration = duration
+	return nil
+}
+
+// iso8601DurationRe is compiled on first use rather than at package initialization, which keeps
+// start-up cheap for WebAssembly builds that never parse durations.
+var iso8601DurationRe = sync.OnceValue(func() *regexp.Regexp {
+	return regexp.MustCompile(
+		`^(-)?P(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`,
+	)
+})
+
+// parseISO8601Duration parses the week, day and time components of an ISO 8601 duration. Years
+// and months are rejected because their length depends on the calendar.
+func parseISO8601Duration(s string) (time.Duration, error) {
+	matches := iso8601DurationRe().FindStringSubmatch(s)
+	if matches == nil || s == "P" || s == "-P" || s[len(s)-1] == 'T' {
+		return 0, fmt.Errorf("invalid ISO 8601 duration: %q", s)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if matches[i+2] == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(matches[i+2], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration: %q", s)
+		}
+		total += time.Duration(value * float64(unit))
+	}
+
+	if matches[1] == "-" {
+		total = -total
+	}
+	return total, nil
+}
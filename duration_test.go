@@ -0,0 +1,47 @@
+package jobj
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJsonDurationUnmarshal(t *testing.T) {
+	tests := []struct {
+		name      string
+		jsonInput string
+		want      time.Duration
+		wantErr   bool
+	}{
+		{"ISO 8601 time", `"PT1H30M"`, 90 * time.Minute, false},
+		{"ISO 8601 days", `"P2DT3H"`, 51 * time.Hour, false},
+		{"ISO 8601 weeks", `"P1W"`, 7 * 24 * time.Hour, false},
+		{"ISO 8601 fractional seconds", `"PT1.5S"`, 1500 * time.Millisecond, false},
+		{"ISO 8601 negative", `"-PT10M"`, -10 * time.Minute, false},
+		{"Go duration", `"1h30m"`, 90 * time.Minute, false},
+		{"Years rejected", `"P1Y"`, 0, true},
+		{"Empty designator", `"PT"`, 0, true},
+		{"Bare P", `"P"`, 0, true},
+		{"Not a string", `90`, 0, true},
+		{"Garbage", `"soon"`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d JsonDuration
+			err := d.UnmarshalJSON([]byte(tt.jsonInput))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && d.Duration != tt.want {
+				t.Errorf("UnmarshalJSON() = %v, want %v", d.Duration, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationField(t *testing.T) {
+	f := Duration("timeout").Required()
+	if f.ValueType != TypeString || f.ValueFormat != "duration" || !f.ValueRequired {
+		t.Errorf("Unexpected duration field: %+v", f)
+	}
+}
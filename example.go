@@ -0,0 +1,153 @@
+package jobj
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// exampleStrings are placeholder values for string formats, chosen so they pass ValidateJSON.
+var exampleStrings = map[string]string{
+	"date-time": "2024-01-15T09:30:00Z",
+	"date":      "2024-01-15",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"uuid":      "123e4567-e89b-12d3-a456-426614174000",
+	"duration":  "PT1H30M",
+}
+
+// Example returns a sample instance of the schema, decoded into generic JSON values, for showing
+// models the expected shape. Every field is included: fields with Examples use the first one,
+// strings use the field's Value when set or a placeholder matching their format, enums use their
+// first option, arrays hold one item and maps one "key" entry.
+func (r *Schema) Example() interface{} {
+	if r.RootField != nil {
+		return exampleValue(r.RootField)
+	}
+	return exampleObject(r.Fields)
+}
+
+// ExampleJSON returns Example encoded as indented JSON.
+func (r *Schema) ExampleJSON() (string, error) {
+	example, err := json.MarshalIndent(r.Example(), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(example), nil
+}
+
+func exampleObject(fields []*Field) map[string]interface{} {
+	object := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		object[field.ValueName] = exampleValue(field)
+	}
+	return object
+}
+
+func exampleValue(field *Field) interface{} {
+	if len(field.ValueExamples) > 0 {
+		return field.ValueExamples[0]
+	}
+	if field.ValueAnyOf != nil {
+		if len(field.ValueAnyOf) == 0 {
+			return nil
+		}
+		return field.ValueAnyOf[0].Const
+	}
+	if len(field.ValueVariants) > 0 {
+		return exampleValue(field.ValueVariants[0])
+	}
+
+	switch field.ValueType {
+	case TypeObject:
+		if !field.AdditionalProperties {
+			return exampleObject(field.SubFields)
+		}
+		switch {
+		case field.AdditionalPropertiesField != nil && field.AdditionalPropertiesField.SubFields != nil:
+			return map[string]interface{}{"key": exampleObject(field.AdditionalPropertiesField.SubFields)}
+		case field.AdditionalPropertiesType != "":
+			return map[string]interface{}{"key": exampleValue(&Field{ValueType: field.AdditionalPropertiesType})}
+		}
+		return map[string]interface{}{"key": "value"}
+	case TypeArray:
+		switch {
+		case field.ArrayItemField != nil:
+			return []interface{}{exampleValue(field.ArrayItemField)}
+		case field.ArrayItemType != "":
+			return []interface{}{exampleValue(&Field{ValueType: field.ArrayItemType})}
+		}
+		return []interface{}{exampleObject(field.SubFields)}
+	case TypeNumber:
+		return 1.5
+	case TypeInteger:
+		return 1
+	case TypeBoolean:
+		return true
+	case TypeString:
+		switch {
+		case field.Value != "":
+			return field.Value
+		case field.ValueContentEncoding == "base64":
+			return "ZXhhbXBsZQ=="
+		case exampleStrings[field.ValueFormat] != "":
+			return exampleStrings[field.ValueFormat]
+		}
+		return "string"
+	}
+	return nil
+}
+
+// ToXMLExample returns a sample XML instance of the schema, for prompts that ask for XML answers;
+// many models follow an example more closely than an XSD. It matches GetXMLSchemaString: the root
+// element is named after the schema, array items are "item" elements, map entries use "key" as
+// the element name and fields marked with AsXMLAttribute are attributes. Values are chosen as in
+// Example, and null enum options are skipped.
+func (r *Schema) ToXMLExample() string {
+	root := r.RootField
+	if root == nil {
+		root = Object(r.Name, r.Fields)
+	}
+
+	var b strings.Builder
+	writeXMLExample(&b, root.renamed(r.Name), 0)
+	return b.String()
+}
+
+func writeXMLExample(b *strings.Builder, field *Field, depth int) {
+	indent := strings.Repeat("  ", depth)
+	name := xmlEscape(field.ValueName)
+
+	if xsdSimpleType(field) != "" {
+		b.WriteString(indent + "<" + name + ">" + xmlEscape(xmlExampleText(field)) + "</" + name + ">\n")
+		return
+	}
+
+	attributes, children := xmlContent(field)
+	b.WriteString(indent + "<" + name)
+	for _, attribute := range attributes {
+		b.WriteString(" " + xmlEscape(attribute.ValueName) + `="` + xmlEscape(xmlExampleText(attribute)) + `"`)
+	}
+	if len(children) == 0 {
+		b.WriteString("/>\n")
+		return
+	}
+	b.WriteString(">\n")
+	for _, child := range children {
+		writeXMLExample(b, child, depth+1)
+	}
+	b.WriteString(indent + "</" + name + ">\n")
+}
+
+// xmlExampleText returns the example value of a field with simple content as element text.
+func xmlExampleText(field *Field) string {
+	if field.ValueAnyOf != nil {
+		for _, option := range field.ValueAnyOf {
+			if option.Const != nil {
+				return fmt.Sprint(option.Const)
+			}
+		}
+		return ""
+	}
+	return fmt.Sprint(exampleValue(field))
+}
@@ -23,9 +23,11 @@ func TestHeadlinesResponse_CreateFields(t *testing.T) {
 
 	correctSchema := `{
   "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "HeadlinesResponse",
   "definitions": {
     "HeadlinesResponse": {
       "additionalProperties": false,
+      "description": "HeadlinesResponse is the requested json response schema from the press release headline extractor",
       "properties": {
         "confidence": {
           "description": "Confidence in the headlines extracted",
@@ -13,9 +13,11 @@ func TestNewTranscriptCorrectionsResponse(t *testing.T) {
 
 	correct := `{
   "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "TranscriptCorrectionsResponse",
   "definitions": {
     "TranscriptCorrectionsResponse": {
       "additionalProperties": false,
+      "description": "TranscriptCorrectionsResponse is the requested json response schema for our transcription corrector, which looks at transcript paragraphs for errors and suggests corrections.",
       "properties": {
         "corrections": {
           "additionalProperties": false,
@@ -15,18 +15,42 @@ type Field struct {
 	ValueName                 string
 	ValueType                 DataType
 	ValueDescription          string
+	ValueTitle                string // Emitted as the "title" keyword; see Title
+	ValueFormat               string // For string formats such as date-time, email, uri or uuid
+	ValueContentEncoding      string // For strings carrying encoded binary data, e.g. base64
 	Value                     string
 	ValueRequired             bool
+	ValueDeprecated           bool          // Marks the field for removal; see Collector.CleanupReport
+	ValueNullable             bool          // Emits the type with "null" added; see Nullable
+	ValueXMLAttribute         bool          // Emit as an xs:attribute in GetXMLSchemaString; see AsXMLAttribute
+	ValueDefinition           string        // Names the object shape for sharing; see Definition
+	ValueRef                  string        // Emitted as "$ref" in place of the field's shape; set by ShareDefinitions
+	ValueDefault              interface{}   // Emitted as the "default" keyword; see Default
+	ValueExamples             []interface{} // Emitted as the "examples" keyword; see Examples
+	ValueMinItems             *int          // Emitted as "minItems" on arrays; see MinItems
+	ValueMaxItems             *int          // Emitted as "maxItems" on arrays; see MaxItems
+	ValueUniqueItems          bool          // Emitted as "uniqueItems" on arrays; see UniqueItems
+	ValueItemsDescription     string        // Emitted as the description of an array's items; see ItemsDesc
+	ValueMinLength            *int          // Emitted as "minLength" on strings; see MinLength
+	ValueMaxLength            *int          // Emitted as "maxLength" on strings; see MaxLength
+	ValueMinimum              *float64      // Emitted as "minimum" on numbers and integers; see Minimum
+	ValueMaximum              *float64      // Emitted as "maximum" on numbers and integers; see Maximum
 	ValueAnyOf                []ConstDescription
+	ValueVariants             []*Field // Emitted as "anyOf" of the variants' schemas; see Variants
+	ValueDiscriminator        string   // Emits the variants as "oneOf" with a "discriminator"; see Discriminator
 	SubFields                 []*Field
 	AdditionalProperties      bool     // Default false for all, explicitly false for array
 	ArrayItemType             DataType // For arrays of primitives (when SubFields is nil/empty)
+	ArrayItemField            *Field   // For arrays whose items are described by a full Field (e.g., [][]float64)
 	AdditionalPropertiesType  DataType // For maps (when AdditionalProperties is true and this is set)
-	AdditionalPropertiesField *Field   // For maps with complex value types (e.g., map[string]Struct)
+	AdditionalPropertiesField *Field   // For maps with complex value types (e.g., map[string]Struct); see MapOf
 }
 
+// ConstDescription is a single allowed value of an AnyOf field. Const may be a string, an integer,
+// a float or a boolean; it is emitted with its JSON type, so 2 becomes {"const": 2} rather than
+// {"const": "2"}.
 type ConstDescription struct {
-	Const       string
+	Const       interface{}
 	Description string
 }
 
@@ -72,24 +96,10 @@ func Float(name string) *Field {
 
 func Date(name string) *Field {
 	/*
-	   JSON Schema spec represents dates as strings with a "format" attribute.
-	   The proper schema would be: {"type": "string", "format": "date"}
-
-	   We don't implement format here for simplicity, keeping the Field struct
-	   minimal while covering the most common use cases for JSON Schema generation.
-
-	   Future enhancement: Add a ValueFormat field to the Field struct and update
-	   the schema generation to include format when specified:
-
-	   type Field struct {
-	       // existing fields...
-	       ValueFormat string // For date, date-time, email, etc.
-	   }
-
-	   Then schema generation would include:
-	   if field.ValueFormat != "" {
-	       fieldProps["format"] = field.ValueFormat
-	   }
+	   JSON Schema spec represents dates as strings with a "format" attribute, but Date
+	   deliberately leaves ValueFormat empty: it is paired with JsonDateTime, which accepts
+	   many layouts beyond the strict "date" format. Use DateTime, or Format("date"), when
+	   the format keyword should be emitted.
 	*/
 	vb := &Field{
 		ValueRequired: false,
@@ -100,6 +110,40 @@ func Date(name string) *Field {
 	return vb
 }
 
+// DateTime creates a string field with the "date-time" format (RFC 3339)
+func DateTime(name string) *Field {
+	return Text(name).Format("date-time")
+}
+
+// Email creates a string field with the "email" format
+func Email(name string) *Field {
+	return Text(name).Format("email")
+}
+
+// URI creates a string field with the "uri" format
+func URI(name string) *Field {
+	return Text(name).Format("uri")
+}
+
+// UUID creates a string field with the "uuid" format
+func UUID(name string) *Field {
+	return Text(name).Format("uuid")
+}
+
+// Duration creates a string field with the "duration" format (ISO 8601, e.g. "PT1H30M").
+// Decode values into JsonDuration.
+func Duration(name string) *Field {
+	return Text(name).Format("duration")
+}
+
+// Bytes creates a string field carrying base64-encoded binary content, matching how
+// encoding/json encodes []byte
+func Bytes(name string) *Field {
+	vb := Text(name)
+	vb.ValueContentEncoding = "base64"
+	return vb
+}
+
 func Array(name string, fields []*Field) *Field {
 	vb := &Field{
 		ValueRequired:        false,
@@ -126,6 +170,56 @@ func ArrayOf(name string, itemType DataType) *Field {
 	return vb
 }
 
+// ArrayOfField creates an array field whose items are described by items, which allows nested
+// arrays such as [][]float64: ArrayOfField("matrix", ArrayOf("", TypeNumber))
+func ArrayOfField(name string, items *Field) *Field {
+	vb := &Field{
+		ValueRequired:        false,
+		ValueType:            TypeArray,
+		ValueName:            name,
+		ValueAnyOf:           nil,
+		SubFields:            nil,
+		ArrayItemField:       items,
+		AdditionalProperties: false,
+	}
+	return vb
+}
+
+// Any creates a field accepting any JSON value, emitted without a "type" keyword. It describes
+// values whose shape is not known up front, e.g. the items of []any: ArrayOfField("values", Any(""))
+func Any(name string) *Field {
+	vb := &Field{
+		ValueRequired: false,
+		ValueName:     name,
+		ValueAnyOf:    nil,
+	}
+	return vb
+}
+
+// Variants creates a field whose value matches at least one of variants, emitted as an "anyOf"
+// of their schemas. Unlike AnyOf, which lists constants, each variant is a full field, so arrays
+// mixing kinds of items can be described:
+// ArrayOfField("cells", Variants("", Float(""), Text(""), Object("", noteFields)))
+// The variants' names are ignored.
+func Variants(name string, variants ...*Field) *Field {
+	vb := &Field{
+		ValueRequired: false,
+		ValueName:     name,
+		ValueAnyOf:    nil,
+		ValueVariants: variants,
+	}
+	return vb
+}
+
+// Discriminator marks the variants of a Variants field as object schemas told apart by the value
+// of property, which every variant declares as a required const. The variants are then emitted as
+// "oneOf" with an OpenAPI discriminator object, {"propertyName": property}, instead of "anyOf".
+// Providers without oneOf support receive anyOf; see Provider.Apply.
+func (vb *Field) Discriminator(property string) *Field {
+	vb.ValueDiscriminator = property
+	return vb
+}
+
 func Int(name string) *Field {
 	vb := &Field{
 		ValueRequired: false,
@@ -148,6 +242,38 @@ func Object(name string, fields []*Field) *Field {
 	return vb
 }
 
+// Map creates an object field whose values all share a primitive type (e.g., map[string]int)
+func Map(name string, valueType DataType) *Field {
+	vb := &Field{
+		ValueRequired:            false,
+		ValueType:                TypeObject,
+		ValueName:                name,
+		ValueAnyOf:               nil,
+		AdditionalProperties:     true,
+		AdditionalPropertiesType: valueType,
+	}
+	return vb
+}
+
+// MapOf creates an object field whose values are described by valueField (e.g., map[string]Struct).
+// An object valueField with nil SubFields allows values of any type. A valueField of a primitive
+// type describes values with keywords beyond their type, e.g. Int("").Minimum(0) for
+// map[string]uint, and sets AdditionalPropertiesType as Map does.
+func MapOf(name string, valueField *Field) *Field {
+	vb := &Field{
+		ValueRequired:             false,
+		ValueType:                 TypeObject,
+		ValueName:                 name,
+		ValueAnyOf:                nil,
+		AdditionalProperties:      true,
+		AdditionalPropertiesField: valueField,
+	}
+	if valueField != nil && valueField.ValueType != TypeObject && valueField.ValueType != "" {
+		vb.AdditionalPropertiesType = valueField.ValueType
+	}
+	return vb
+}
+
 func (vb *Field) Type(valueType DataType) *Field {
 	vb.ValueType = valueType
 	return vb
@@ -163,11 +289,122 @@ func (vb *Field) Desc(valueDescription string) *Field {
 	return vb
 }
 
+// Format sets the JSON Schema format keyword, e.g. "date-time" or "email"
+func (vb *Field) Format(format string) *Field {
+	vb.ValueFormat = format
+	return vb
+}
+
 func (vb *Field) Required() *Field {
 	vb.ValueRequired = true
 	return vb
 }
 
+// Title sets the JSON Schema title keyword: a short name for the field, shown by schema viewers
+// and form generators, where the description explains it.
+func (vb *Field) Title(title string) *Field {
+	vb.ValueTitle = title
+	return vb
+}
+
+// Default sets the JSON Schema default keyword: the value a consumer should assume when the field
+// is omitted. The value is emitted with its JSON type, so Default(10) becomes {"default": 10}. It
+// is documentation for the model, which may leave the field out, and is not applied by
+// ValidateJSON; Schema.FillDefaults backfills omitted fields with it.
+func (vb *Field) Default(value interface{}) *Field {
+	vb.ValueDefault = value
+	return vb
+}
+
+// Examples sets the JSON Schema examples keyword: sample values of the field, emitted with their
+// JSON types. Concrete examples in tool schemas noticeably improve the arguments models produce.
+// Example and ToXMLExample use the first one as the field's sample value.
+func (vb *Field) Examples(values ...interface{}) *Field {
+	vb.ValueExamples = values
+	return vb
+}
+
+// ItemsDesc sets the description of the items schema of an array field, describing one item
+// rather than the array as a whole, e.g. Desc("Officers of the company") with
+// ItemsDesc("An officer as listed in the filing").
+func (vb *Field) ItemsDesc(description string) *Field {
+	vb.ValueItemsDescription = description
+	return vb
+}
+
+// MinItems sets the JSON Schema minItems keyword of an array field, the fewest items it may hold,
+// so that asks such as "return 3 to 5 bullet points" are part of the schema rather than only the
+// prompt. ValidateJSON enforces it.
+func (vb *Field) MinItems(n int) *Field {
+	vb.ValueMinItems = &n
+	return vb
+}
+
+// MaxItems sets the JSON Schema maxItems keyword of an array field, the most items it may hold.
+// ValidateJSON enforces it.
+func (vb *Field) MaxItems(n int) *Field {
+	vb.ValueMaxItems = &n
+	return vb
+}
+
+// UniqueItems sets the JSON Schema uniqueItems keyword of an array field: no two of its items may
+// be equal. ValidateJSON enforces it, comparing numbers by value.
+func (vb *Field) UniqueItems() *Field {
+	vb.ValueUniqueItems = true
+	return vb
+}
+
+// MinLength sets the JSON Schema minLength keyword of a string field, the fewest characters it
+// may hold. ValidateJSON enforces it, counting Unicode code points.
+func (vb *Field) MinLength(n int) *Field {
+	vb.ValueMinLength = &n
+	return vb
+}
+
+// MaxLength sets the JSON Schema maxLength keyword of a string field, the most characters it may
+// hold. ValidateJSON enforces it, counting Unicode code points.
+func (vb *Field) MaxLength(n int) *Field {
+	vb.ValueMaxLength = &n
+	return vb
+}
+
+// Minimum sets the JSON Schema minimum keyword of a number or integer field, the smallest value
+// it may take. ValidateJSON enforces it.
+func (vb *Field) Minimum(value float64) *Field {
+	vb.ValueMinimum = &value
+	return vb
+}
+
+// Maximum sets the JSON Schema maximum keyword of a number or integer field, the largest value it
+// may take. ValidateJSON enforces it.
+func (vb *Field) Maximum(value float64) *Field {
+	vb.ValueMaximum = &value
+	return vb
+}
+
+// Deprecated marks the field as scheduled for removal
+func (vb *Field) Deprecated() *Field {
+	vb.ValueDeprecated = true
+	return vb
+}
+
+// Nullable allows the field to be null as well as a value of its type, emitted as a type list
+// such as ["string", "null"], or an extra {"type": "null"} option for anyOf fields. Unlike leaving
+// a field optional, this is what strict OpenAI structured outputs and several validators need to
+// accept a missing value. ValidateJSON accepts null for it.
+func (vb *Field) Nullable() *Field {
+	vb.ValueNullable = true
+	return vb
+}
+
+// AsXMLAttribute emits the field as an attribute of its parent element in GetXMLSchemaString,
+// e.g. <quote id="3">, instead of as a child element. It only applies to fields with simple
+// content; objects, arrays and maps stay elements. JSON output is unaffected.
+func (vb *Field) AsXMLAttribute() *Field {
+	vb.ValueXMLAttribute = true
+	return vb
+}
+
 func (vb *Field) Optional() *Field {
 	vb.ValueRequired = false
 	return vb
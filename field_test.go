@@ -1,5 +1,10 @@
 package jobj
 
+import (
+	"strings"
+	"testing"
+)
+
 // KeyFactResponseV2 is the requested json response schema from the key fact extractor
 type KeyFactResponseV2Ex struct {
 	Facts []struct {
@@ -15,3 +20,465 @@ type KeyFactV2 struct {
 	Fact     string `json:"fact"`
 	FactType string `json:"fact_type"`
 }
+
+func TestMapBuilders(t *testing.T) {
+	s := &Schema{
+		Name: "MapTest",
+		Fields: []*Field{
+			Map("counts", TypeInteger).Desc("Counts by key").Required(),
+			MapOf("people", Object("", []*Field{Text("name").Required()})),
+			MapOf("metadata", &Field{ValueType: TypeObject}),
+		},
+	}
+
+	props := s.FieldsJson()
+
+	counts := props["counts"].(map[string]interface{})
+	if counts["additionalProperties"].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("Expected integer map values, got %v", counts["additionalProperties"])
+	}
+
+	people := props["people"].(map[string]interface{})
+	valueSchema := people["additionalProperties"].(map[string]interface{})
+	if _, ok := valueSchema["properties"].(map[string]interface{})["name"]; !ok {
+		t.Errorf("Expected struct map values with a name property, got %v", valueSchema)
+	}
+
+	metadata := props["metadata"].(map[string]interface{})
+	if metadata["additionalProperties"] != true {
+		t.Errorf("Expected any-valued map, got %v", metadata["additionalProperties"])
+	}
+
+	if !strings.Contains(s.GetSchemaString(), `"counts"`) {
+		t.Error("Expected map field in schema string")
+	}
+}
+
+func TestFormatBuilders(t *testing.T) {
+	tests := []struct {
+		field  *Field
+		format string
+	}{
+		{DateTime("created_at"), "date-time"},
+		{Email("email"), "email"},
+		{URI("homepage"), "uri"},
+		{UUID("id"), "uuid"},
+		{Text("day").Format("date"), "date"},
+	}
+
+	for _, tt := range tests {
+		if tt.field.ValueType != TypeString || tt.field.ValueFormat != tt.format {
+			t.Errorf("%s: expected string with format %q, got %s/%q", tt.field.ValueName, tt.format, tt.field.ValueType, tt.field.ValueFormat)
+		}
+	}
+
+	s := &Schema{
+		Name: "FormatTest",
+		Fields: []*Field{
+			Email("email").Required(),
+			Text("name"),
+			Object("contact", []*Field{URI("homepage")}),
+		},
+	}
+
+	props := s.FieldsJson()
+	if props["email"].(map[string]string)["format"] != "email" {
+		t.Errorf("Expected email format, got %v", props["email"])
+	}
+	if _, ok := props["name"].(map[string]string)["format"]; ok {
+		t.Error("Expected no format on plain text field")
+	}
+	contact := props["contact"].(map[string]interface{})["properties"].(map[string]interface{})
+	if contact["homepage"].(map[string]string)["format"] != "uri" {
+		t.Errorf("Expected uri format on nested field, got %v", contact["homepage"])
+	}
+}
+
+func TestBytesField(t *testing.T) {
+	s := &Schema{
+		Name:   "BytesTest",
+		Fields: []*Field{Bytes("payload").Desc("Raw payload").Required()},
+	}
+
+	payload := s.FieldsJson()["payload"].(map[string]string)
+	if payload["type"] != "string" || payload["contentEncoding"] != "base64" {
+		t.Errorf("Expected base64 string, got %v", payload)
+	}
+
+	errs, err := s.ValidateJSON([]byte(`{"payload":"aGVsbG8="}`))
+	if err != nil || len(errs) != 0 {
+		t.Errorf("Expected valid base64 payload, got %v, %v", errs, err)
+	}
+	errs, err = s.ValidateJSON([]byte(`{"payload":"not base64!"}`))
+	if err != nil || len(errs) != 1 {
+		t.Errorf("Expected one validation error for invalid base64, got %v, %v", errs, err)
+	}
+}
+
+func TestArrayOfField(t *testing.T) {
+	s := &Schema{
+		Name: "MatrixTest",
+		Fields: []*Field{
+			ArrayOfField("matrix", ArrayOf("", TypeNumber)).Desc("Embedding matrix").Required(),
+			Object("meta", []*Field{ArrayOfField("grid", ArrayOf("", TypeInteger))}),
+		},
+	}
+
+	matrix := s.FieldsJson()["matrix"].(map[string]interface{})
+	items := matrix["items"].(map[string]interface{})
+	if items["type"] != "array" || items["items"].(map[string]interface{})["type"] != "number" {
+		t.Errorf("Expected array of number arrays, got %v", matrix)
+	}
+
+	errs, err := s.ValidateJSON([]byte(`{"matrix":[[1.5,2],[3,"x"]],"meta":{"grid":[[1]]}}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 1 || errs[0].Pointer != "/matrix/1/1" {
+		t.Errorf("Expected a single error at /matrix/1/1, got %v", errs)
+	}
+
+	strictItems := s.Strict()["properties"].(map[string]interface{})["matrix"].(map[string]interface{})["items"].(map[string]interface{})
+	if strictItems["type"] != "array" {
+		t.Errorf("Expected strict items to be an array, got %v", strictItems)
+	}
+
+	patched, err := s.MergePatch([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("MergePatch failed: %v", err)
+	}
+	if patched.GetSchemaString() != s.GetSchemaString() {
+		t.Errorf("Expected nested arrays to survive a patch round trip.\nwant: %s\ngot:  %s", s.GetSchemaString(), patched.GetSchemaString())
+	}
+}
+
+func TestHeterogeneousArrays(t *testing.T) {
+	s := &Schema{
+		Name: "SheetRow",
+		Fields: []*Field{
+			ArrayOfField("values", Any("")).Desc("Raw values").Required(),
+			ArrayOfField("cells", Variants("",
+				Float(""),
+				Text(""),
+				Object("", []*Field{Text("note").Required()}),
+			)).Desc("Cell values"),
+		},
+	}
+
+	values := s.FieldsJson()["values"].(map[string]interface{})
+	if items := values["items"].(map[string]string); len(items) != 1 || items["description"] != "" {
+		t.Errorf("Expected untyped items, got %v", values["items"])
+	}
+	cells := s.FieldsJson()["cells"].(map[string]interface{})
+	variants := cells["items"].(map[string]interface{})["anyOf"].([]interface{})
+	if len(variants) != 3 || variants[2].(map[string]interface{})["type"] != "object" {
+		t.Errorf("Expected an anyOf of three item schemas, got %v", cells["items"])
+	}
+
+	errs, err := s.ValidateJSON([]byte(`{"values":[1,"a",null,{"b":[true]}],"cells":[1.5,"x",{"note":"y"},true,{}]}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 2 || errs[0].Pointer != "/cells/3" || errs[1].Pointer != "/cells/4" {
+		t.Errorf("Expected errors at /cells/3 and /cells/4, got %v", errs)
+	}
+	if len(errs) > 0 && errs[0].Expected != "number or string or object" {
+		t.Errorf("Expected the variants to be listed, got %q", errs[0].Expected)
+	}
+
+	if outline := s.ToPromptText(); !strings.Contains(outline, "cells (array of number or string or object)") ||
+		!strings.Contains(outline, "values (array of any, required)") {
+		t.Errorf("Expected the outline to describe the items, got:\n%s", outline)
+	}
+
+	strictCells := s.Strict()["properties"].(map[string]interface{})["cells"].(map[string]interface{})
+	if _, ok := strictCells["items"].(map[string]interface{})["anyOf"]; !ok {
+		t.Errorf("Expected strict items to keep the anyOf, got %v", strictCells)
+	}
+
+	patched, err := s.MergePatch([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("MergePatch failed: %v", err)
+	}
+	if patched.GetSchemaString() != s.GetSchemaString() {
+		t.Errorf("Expected heterogeneous arrays to survive a patch round trip.\nwant: %s\ngot:  %s", s.GetSchemaString(), patched.GetSchemaString())
+	}
+}
+
+func TestDiscriminatedVariants(t *testing.T) {
+	kind := func(value string) *Field {
+		field := Text("kind").Required()
+		field.ValueAnyOf = []ConstDescription{{Const: value}}
+		return field
+	}
+	s := &Schema{
+		Name: "Payment",
+		Fields: []*Field{
+			Variants("method",
+				Object("", []*Field{kind("card"), Text("number").Required()}),
+				Object("", []*Field{kind("transfer"), Text("iban").Required()}),
+			).Discriminator("kind").Desc("How to pay").Nullable().Required(),
+		},
+	}
+
+	method := s.FieldsJson()["method"].(map[string]interface{})
+	if _, ok := method["anyOf"]; ok {
+		t.Errorf("Expected no anyOf on a discriminated field, got %v", method)
+	}
+	if variants := method["oneOf"].([]interface{}); len(variants) != 3 {
+		t.Errorf("Expected two variants and null in oneOf, got %v", method["oneOf"])
+	}
+	if discriminator := method["discriminator"].(map[string]interface{}); discriminator["propertyName"] != "kind" {
+		t.Errorf("Expected the kind discriminator, got %v", method["discriminator"])
+	}
+
+	errs, err := s.ValidateJSON([]byte(`{"method":{"kind":"transfer","iban":"DE89"}}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected a valid transfer, got %v", errs)
+	}
+
+	strictMethod := s.Strict()["properties"].(map[string]interface{})["method"].(map[string]interface{})
+	if _, ok := strictMethod["anyOf"]; !ok {
+		t.Errorf("Expected strict mode to use anyOf, got %v", strictMethod)
+	}
+	_, report, err := ProviderOpenAIStrict.Apply(s)
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if len(report.Losses) != 1 || report.Losses[0].Keyword != "oneOf" {
+		t.Errorf("Expected the oneOf to be reported as lost in strict mode, got %v", report.Losses)
+	}
+
+	patched, err := s.MergePatch([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("MergePatch failed: %v", err)
+	}
+	if patched.GetSchemaString() != s.GetSchemaString() {
+		t.Errorf("Expected discriminated variants to survive a patch round trip.\nwant: %s\ngot:  %s", s.GetSchemaString(), patched.GetSchemaString())
+	}
+}
+
+func TestArrayCardinality(t *testing.T) {
+	s := &Schema{
+		Name: "Summary",
+		Fields: []*Field{
+			ArrayOf("bullets", TypeString).MinItems(3).MaxItems(5).UniqueItems().Desc("Key points").Required(),
+			Array("quotes", []*Field{Text("text")}).MaxItems(2),
+		},
+	}
+
+	bullets := s.FieldsJson()["bullets"].(map[string]interface{})
+	if bullets["minItems"] != 3 || bullets["maxItems"] != 5 || bullets["uniqueItems"] != true {
+		t.Errorf("Expected cardinality keywords, got %v", bullets)
+	}
+	if quotes := s.FieldsJson()["quotes"].(map[string]interface{}); quotes["maxItems"] != 2 {
+		t.Errorf("Expected maxItems on the array of objects, got %v", quotes)
+	}
+
+	errs, err := s.ValidateJSON([]byte(`{"bullets":["a","b","a"],"quotes":[{"text":"x"},{"text":"y"},{"text":"z"}]}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 2 || errs[0].Pointer != "/bullets/2" || errs[1].Pointer != "/quotes" {
+		t.Errorf("Expected a duplicate at /bullets/2 and too many items at /quotes, got %v", errs)
+	}
+
+	errs, err = s.ValidateJSON([]byte(`{"bullets":["a","b"]}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 1 || errs[0].Message != "expected at least 3 items, got 2" {
+		t.Errorf("Expected too few items, got %v", errs)
+	}
+
+	if outline := s.ToPromptText(); !strings.Contains(outline, "bullets (array of string, required, 3 to 5 items, unique)") {
+		t.Errorf("Expected the outline to state the item count, got:\n%s", outline)
+	}
+
+	patched, err := s.MergePatch([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("MergePatch failed: %v", err)
+	}
+	if patched.GetSchemaString() != s.GetSchemaString() {
+		t.Errorf("Expected cardinality to survive a patch round trip.\nwant: %s\ngot:  %s", s.GetSchemaString(), patched.GetSchemaString())
+	}
+}
+
+func TestNestedArrayItems(t *testing.T) {
+	s := &Schema{
+		Name: "Filing",
+		Fields: []*Field{
+			Array("officers", []*Field{
+				Text("name").Required(),
+				Array("roles", []*Field{
+					Text("title").Required(),
+					Array("terms", []*Field{Int("start").Required(), Int("end")}),
+				}).ItemsDesc("A role held by the officer"),
+			}).Desc("Officers of the company").ItemsDesc("An officer as listed in the filing").Required(),
+			ArrayOf("tags", TypeString).ItemsDesc("A lowercase tag"),
+		},
+	}
+
+	officers := s.FieldsJson()["officers"].(map[string]interface{})
+	officer := officers["items"].(map[string]interface{})
+	if officers["description"] != "Officers of the company" || officer["description"] != "An officer as listed in the filing" {
+		t.Errorf("Expected separate array and items descriptions, got %v", officers)
+	}
+
+	roles := officer["properties"].(map[string]interface{})["roles"].(map[string]interface{})
+	role := roles["items"].(map[string]interface{})
+	if role["description"] != "A role held by the officer" {
+		t.Errorf("Expected the nested items description, got %v", role)
+	}
+	if required := role["required"].([]string); len(required) != 1 || required[0] != "title" {
+		t.Errorf("Expected roles items to require title, got %v", role["required"])
+	}
+	term := role["properties"].(map[string]interface{})["terms"].(map[string]interface{})["items"].(map[string]interface{})
+	if required := term["required"].([]string); len(required) != 1 || required[0] != "start" {
+		t.Errorf("Expected terms items to require start, got %v", term["required"])
+	}
+
+	tags := s.FieldsJson()["tags"].(map[string]interface{})
+	if tags["items"].(map[string]interface{})["description"] != "A lowercase tag" {
+		t.Errorf("Expected the primitive items description, got %v", tags)
+	}
+
+	errs, err := s.ValidateJSON([]byte(`{"officers":[{"name":"a","roles":[{"title":"b","terms":[{"end":2}]}]}]}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 1 || errs[0].Pointer != "/officers/0/roles/0/terms/0/start" {
+		t.Errorf("Expected a missing start three arrays down, got %v", errs)
+	}
+
+	patched, err := s.MergePatch([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("MergePatch failed: %v", err)
+	}
+	if patched.GetSchemaString() != s.GetSchemaString() {
+		t.Errorf("Expected items descriptions to survive a patch round trip.\nwant: %s\ngot:  %s", s.GetSchemaString(), patched.GetSchemaString())
+	}
+}
+
+func TestLengthAndRange(t *testing.T) {
+	s := &Schema{
+		Name: "Search",
+		Fields: []*Field{
+			Text("query").MinLength(1).MaxLength(5).Required(),
+			Int("limit").Minimum(1).Maximum(50),
+			Float("score").Minimum(0.5),
+		},
+	}
+
+	query := s.FieldsJson()["query"].(map[string]interface{})
+	if query["minLength"] != 1 || query["maxLength"] != 5 {
+		t.Errorf("Expected length keywords, got %v", query)
+	}
+	if limit := s.FieldsJson()["limit"].(map[string]interface{}); limit["minimum"] != 1.0 || limit["maximum"] != 50.0 {
+		t.Errorf("Expected range keywords, got %v", limit)
+	}
+
+	errs, err := s.ValidateJSON([]byte(`{"query":"ünïcödé","limit":0,"score":0.5}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 2 || errs[0].Message != "expected at least 1, got 0" || errs[1].Message != "expected at most 5 characters, got 7" {
+		t.Errorf("Expected a long query and a low limit, got %v", errs)
+	}
+
+	errs, err = s.ValidateJSON([]byte(`{"query":"ünï","limit":50}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+
+	outline := s.ToPromptText()
+	if !strings.Contains(outline, "query (string, required, 1 to 5 characters)") || !strings.Contains(outline, "limit (integer, 1 to 50)") {
+		t.Errorf("Expected the outline to state the bounds, got:\n%s", outline)
+	}
+
+	patched, err := s.MergePatch([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("MergePatch failed: %v", err)
+	}
+	if patched.GetSchemaString() != s.GetSchemaString() {
+		t.Errorf("Expected bounds to survive a patch round trip.\nwant: %s\ngot:  %s", s.GetSchemaString(), patched.GetSchemaString())
+	}
+}
+
+func TestFieldTitle(t *testing.T) {
+	s := &Schema{
+		Name:   "Search",
+		Fields: []*Field{Text("query").Title("Query").Desc("Search query")},
+	}
+
+	if query := s.FieldsJson()["query"].(map[string]interface{}); query["title"] != "Query" {
+		t.Errorf("Expected title keyword, got %v", query)
+	}
+
+	// Titles survive patching, which round trips the schema through its JSON document
+	patched, err := s.MergePatch([]byte(`{"properties":{"query":{"description":"Query text"}}}`))
+	if err != nil {
+		t.Fatalf("MergePatch returned error: %v", err)
+	}
+	if query := patched.Fields[0]; query.ValueTitle != "Query" || query.ValueDescription != "Query text" {
+		t.Errorf("Expected title kept through patch, got %+v", query)
+	}
+}
+
+func TestFieldNullable(t *testing.T) {
+	s := &Schema{
+		Name: "Search",
+		Fields: []*Field{
+			Text("query").Required(),
+			Int("limit").Nullable(),
+			AnyOf("sort", []ConstDescription{{Const: "date"}, {Const: "relevance"}}).Nullable(),
+		},
+	}
+
+	properties := s.FieldsJson()
+	limit := properties["limit"].(map[string]interface{})
+	if types, ok := limit["type"].([]string); !ok || len(types) != 2 || types[0] != "integer" || types[1] != "null" {
+		t.Errorf("Expected nullable integer type, got %v", limit["type"])
+	}
+	sort := properties["sort"].(map[string]interface{})
+	if anyOf := sort["anyOf"].([]map[string]interface{}); len(anyOf) != 3 || anyOf[2]["type"] != "null" {
+		t.Errorf("Expected null option in anyOf, got %v", sort["anyOf"])
+	}
+
+	errs, err := s.ValidateJSON([]byte(`{"query":"go","limit":null,"sort":null}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected null to be valid for nullable fields, got %v", errs)
+	}
+	errs, _ = s.ValidateJSON([]byte(`{"query":null}`))
+	if len(errs) != 1 {
+		t.Errorf("Expected null to be invalid for other fields, got %v", errs)
+	}
+
+	// Strict mode keeps a required nullable field nullable
+	s.Fields[1].Required()
+	strictLimit := s.Strict()["properties"].(map[string]interface{})["limit"].(map[string]interface{})
+	if types, ok := strictLimit["type"].([]string); !ok || len(types) != 2 {
+		t.Errorf("Expected nullable type in strict mode, got %v", strictLimit["type"])
+	}
+
+	// Nullability survives patching, which round trips the schema through its JSON document
+	patched, err := s.MergePatch([]byte(`{"properties":{"query":{"description":"Query text"}}}`))
+	if err != nil {
+		t.Fatalf("MergePatch returned error: %v", err)
+	}
+	if !patched.Fields[1].ValueNullable || patched.Fields[1].ValueType != TypeInteger || !patched.Fields[2].ValueNullable {
+		t.Errorf("Expected nullable fields kept through patch, got %+v, %+v", patched.Fields[1], patched.Fields[2])
+	}
+	if patched.Fields[0].ValueNullable {
+		t.Errorf("Expected query to stay non-nullable")
+	}
+}
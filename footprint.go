@@ -0,0 +1,117 @@
+package jobj
+
+import (
+	"sort"
+	"unsafe"
+)
+
+// SchemaFootprint estimates the memory held by one schema.
+type SchemaFootprint struct {
+	Name    string `json:"name"`
+	Profile string `json:"profile,omitempty"` // Set for profile variants built by Registry.BuildProfile
+	Fields  int    `json:"fields"`            // Fields at every nesting level, including array items and map values
+	Bytes   int    `json:"bytes"`             // Estimated heap bytes: structs, strings, slices and enum values
+}
+
+// RegistryFootprint estimates the memory held by a Registry, for services hosting many
+// dynamically loaded schemas.
+type RegistryFootprint struct {
+	Schemas       []SchemaFootprint `json:"schemas"`        // Built schemas, largest first
+	Pending       int               `json:"pending"`        // Registered schemas not built yet
+	OverrideBytes int               `json:"override_bytes"` // Merge patches held for profiles
+	TotalBytes    int               `json:"total_bytes"`    // Sum of Schemas[].Bytes and OverrideBytes
+}
+
+// Footprint estimates the memory held by the schema. The estimate counts the Schema and Field
+// structs, string contents, slice backing arrays and enum values; it ignores allocator overhead,
+// so treat it as a lower bound useful for comparing schemas rather than an exact figure.
+// Fields shared between schemas are counted for each schema.
+func (r *Schema) Footprint() SchemaFootprint {
+	footprint := SchemaFootprint{Name: r.Name}
+	footprint.Bytes = int(unsafe.Sizeof(*r)) + len(r.Name) + len(r.Description) + len(r.SchemaID) + len(r.Version)
+	footprint.Bytes += cap(r.Fields) * int(unsafe.Sizeof((*Field)(nil)))
+	for _, field := range r.Fields {
+		addFieldFootprint(field, &footprint)
+	}
+	addFieldFootprint(r.RootField, &footprint)
+	addFieldFootprint(r.AdditionalPropertiesField, &footprint)
+	return footprint
+}
+
+func addFieldFootprint(field *Field, footprint *SchemaFootprint) {
+	if field == nil {
+		return
+	}
+	footprint.Fields++
+	footprint.Bytes += int(unsafe.Sizeof(*field)) + len(field.ValueName) + len(field.ValueType) +
+		len(field.ValueDescription) + len(field.ValueFormat) + len(field.ValueContentEncoding) + len(field.Value) +
+		len(field.ArrayItemType) + len(field.AdditionalPropertiesType)
+
+	footprint.Bytes += cap(field.ValueAnyOf) * int(unsafe.Sizeof(ConstDescription{}))
+	for _, option := range field.ValueAnyOf {
+		footprint.Bytes += len(option.Description)
+		switch value := option.Const.(type) {
+		case string:
+			footprint.Bytes += int(unsafe.Sizeof(value)) + len(value)
+		case nil:
+		default:
+			// Boxed numbers and booleans take at most a word.
+			footprint.Bytes += int(unsafe.Sizeof(uintptr(0)))
+		}
+	}
+
+	footprint.Bytes += cap(field.SubFields) * int(unsafe.Sizeof((*Field)(nil)))
+	for _, subField := range field.SubFields {
+		addFieldFootprint(subField, footprint)
+	}
+	addFieldFootprint(field.ArrayItemField, footprint)
+	addFieldFootprint(field.AdditionalPropertiesField, footprint)
+}
+
+// Footprint estimates the memory held by the registry's built schemas, including profile variants
+// resolved by BuildProfile, and by its profile overrides. Registered schemas that have not been
+// built hold no schema memory yet and are only counted in Pending. The registry does not cache
+// rendered schema strings or validators, so there is nothing else to count. See Schema.Footprint
+// for what the estimate covers.
+func (r *Registry) Footprint() RegistryFootprint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var footprint RegistryFootprint
+	for name := range r.creators {
+		if _, ok := r.built[name]; !ok {
+			footprint.Pending++
+		}
+	}
+	for name, schema := range r.built {
+		schemaFootprint := schema.Footprint()
+		schemaFootprint.Name = name
+		footprint.Schemas = append(footprint.Schemas, schemaFootprint)
+	}
+	for key, schema := range r.resolved {
+		schemaFootprint := schema.Footprint()
+		schemaFootprint.Name = key.name
+		schemaFootprint.Profile = key.profile
+		footprint.Schemas = append(footprint.Schemas, schemaFootprint)
+	}
+	for key, patch := range r.overrides {
+		footprint.OverrideBytes += cap(patch) + len(key.profile) + len(key.name)
+	}
+
+	sort.Slice(footprint.Schemas, func(i, j int) bool {
+		a, b := footprint.Schemas[i], footprint.Schemas[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Profile < b.Profile
+	})
+
+	footprint.TotalBytes = footprint.OverrideBytes
+	for _, schemaFootprint := range footprint.Schemas {
+		footprint.TotalBytes += schemaFootprint.Bytes
+	}
+	return footprint
+}
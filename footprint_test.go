@@ -0,0 +1,77 @@
+package jobj
+
+import (
+	"testing"
+)
+
+func TestSchemaFootprint(t *testing.T) {
+	small := &Schema{Name: "Small", Fields: []*Field{Text("a")}}
+	large := &Schema{
+		Name:        "Large",
+		Description: "A larger schema with nested fields",
+		Fields: []*Field{
+			Text("a").Desc("A long description of the first field"),
+			Array("items", []*Field{Int("id"), Text("label")}),
+			ArrayOfField("matrix", ArrayOf("", TypeNumber)),
+			MapOf("lookup", Object("", []*Field{Bool("ok")})),
+			AnyOf("level", []ConstDescription{{Const: "low"}, {Const: 2}}),
+		},
+	}
+
+	smallFootprint := small.Footprint()
+	largeFootprint := large.Footprint()
+	if smallFootprint.Fields != 1 {
+		t.Errorf("Expected 1 field, got %d", smallFootprint.Fields)
+	}
+	// a, items, id, label, matrix, its items, lookup, its values, ok, level
+	if largeFootprint.Fields != 10 {
+		t.Errorf("Expected 10 fields, got %d", largeFootprint.Fields)
+	}
+	if smallFootprint.Bytes <= 0 || largeFootprint.Bytes <= smallFootprint.Bytes {
+		t.Errorf("Expected the larger schema to report more bytes: %d vs %d", largeFootprint.Bytes, smallFootprint.Bytes)
+	}
+}
+
+func TestRegistryFootprint(t *testing.T) {
+	registry := NewRegistry()
+	_ = registry.Register("Counting", &countingSchema{})
+	_ = registry.Register("Pending", &countingSchema{})
+	_ = registry.RegisterSchema(&Schema{Name: "Headlines", Fields: []*Field{Text("headline").Required()}})
+	_ = registry.Override("staging", "Headlines", []byte(`{"properties": {"summary": {"type": "string"}}}`))
+
+	if _, err := registry.Build("Counting"); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if _, err := registry.BuildProfile("staging", "Headlines"); err != nil {
+		t.Fatalf("BuildProfile failed: %v", err)
+	}
+
+	footprint := registry.Footprint()
+	if footprint.Pending != 1 {
+		t.Errorf("Expected 1 pending schema, got %d", footprint.Pending)
+	}
+	if len(footprint.Schemas) != 3 {
+		t.Fatalf("Expected Counting, Headlines and its staging variant, got %+v", footprint.Schemas)
+	}
+
+	total := footprint.OverrideBytes
+	profiles := 0
+	for i, schema := range footprint.Schemas {
+		total += schema.Bytes
+		if i > 0 && schema.Bytes > footprint.Schemas[i-1].Bytes {
+			t.Errorf("Expected schemas largest first, got %+v", footprint.Schemas)
+		}
+		if schema.Profile == "staging" {
+			profiles++
+			if schema.Name != "Headlines" || schema.Fields != 2 {
+				t.Errorf("Unexpected staging footprint %+v", schema)
+			}
+		}
+	}
+	if profiles != 1 {
+		t.Errorf("Expected one profile variant, got %d", profiles)
+	}
+	if footprint.OverrideBytes == 0 || footprint.TotalBytes != total {
+		t.Errorf("Expected total %d including overrides, got %+v", total, footprint)
+	}
+}
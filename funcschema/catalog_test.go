@@ -0,0 +1,179 @@
+package funcschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mhpenta/jobj"
+)
+
+// catalogSize is the number of tools in the benchmark catalog, the size of our largest agent
+// deployments.
+const catalogSize = 500
+
+// catalogTool builds a distinct handler type for tool i, with nested parameters and results:
+// an object, an array of objects, a map of objects and primitives with descriptions, formats
+// and bounds. Types are built with reflection so every tool has its own types, as in a real
+// catalog.
+func catalogTool(i int) interface{} {
+	tag := func(name, desc, extra string) reflect.StructTag {
+		return reflect.StructTag(fmt.Sprintf(`json:"%s" desc:"%s"%s`, name, desc, extra))
+	}
+	address := reflect.StructOf([]reflect.StructField{
+		{Name: "Street", Type: reflect.TypeOf(""), Tag: tag("street", "Street and number", ` required:"true"`)},
+		{Name: "City", Type: reflect.TypeOf(""), Tag: tag("city", "City", ` required:"true"`)},
+		{Name: fmt.Sprintf("Postcode%d", i), Type: reflect.TypeOf(""), Tag: tag("postcode", "Postal code", "")},
+	})
+	officer := reflect.StructOf([]reflect.StructField{
+		{Name: "Name", Type: reflect.TypeOf(""), Tag: tag("name", "Full name", ` required:"true"`)},
+		{Name: "Title", Type: reflect.TypeOf(""), Tag: tag("title", "Job title", ` example:"CEO"`)},
+		{Name: "Since", Type: reflect.TypeOf(0), Tag: tag("since", "Year appointed", ` default:"2020"`)},
+		{Name: "Address", Type: address, Tag: tag("address", "Home address", "")},
+	})
+	params := reflect.StructOf([]reflect.StructField{
+		{Name: "Query", Type: reflect.TypeOf(""), Tag: tag("query", fmt.Sprintf("Query for tool %d", i), ` required:"true"`)},
+		{Name: "Limit", Type: reflect.TypeOf(0), Tag: tag("limit", "Maximum results", ` default:"10"`)},
+		{Name: "Email", Type: reflect.TypeOf(""), Tag: tag("email", "Contact address", ` format:"email"`)},
+		{Name: "Filters", Type: reflect.TypeOf(map[string]string{}), Tag: tag("filters", "Filters by field", "")},
+		{Name: "Location", Type: address, Tag: tag("location", "Search location", "")},
+	})
+	result := reflect.StructOf([]reflect.StructField{
+		{Name: "Company", Type: reflect.TypeOf(""), Tag: tag("company", "Company name", ` required:"true"`)},
+		{Name: "Officers", Type: reflect.SliceOf(officer), Tag: tag("officers", "Officers", ` minItems:"1"`)},
+		{Name: "Subsidiaries", Type: reflect.MapOf(reflect.TypeOf(""), address), Tag: tag("subsidiaries", "Subsidiaries by name", "")},
+		{Name: "Scores", Type: reflect.TypeOf([][]float64{}), Tag: tag("scores", "Score matrix", "")},
+		{Name: fmt.Sprintf("Tool%d", i), Type: reflect.TypeOf(0), Tag: tag("tool", "Tool number", "")},
+	})
+
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	funcType := reflect.FuncOf([]reflect.Type{contextType, params}, []reflect.Type{result, errorType}, false)
+	return reflect.MakeFunc(funcType, func(args []reflect.Value) []reflect.Value {
+		return []reflect.Value{reflect.Zero(result), reflect.Zero(errorType)}
+	}).Interface()
+}
+
+func catalog(b *testing.B) []interface{} {
+	b.Helper()
+	tools := make([]interface{}, catalogSize)
+	for i := range tools {
+		tools[i] = catalogTool(i)
+	}
+	return tools
+}
+
+// catalogSchemas generates the input and output schemas of every tool, without caching.
+func catalogSchemas(b *testing.B, tools []interface{}) []jobj.Schema {
+	b.Helper()
+	schemas := make([]jobj.Schema, 0, 2*len(tools))
+	for _, tool := range tools {
+		funcType := reflect.TypeOf(tool)
+		input, output, err := schemasFromTypes(funcType.In(1), funcType.Out(0), options{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		schemas = append(schemas, input, output)
+	}
+	return schemas
+}
+
+// BenchmarkCatalogGenerate measures generating the schemas of the whole catalog from Go types.
+func BenchmarkCatalogGenerate(b *testing.B) {
+	tools := catalog(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		catalogSchemas(b, tools)
+	}
+}
+
+// BenchmarkCatalogSchemasFor measures looking up the schemas of the whole catalog once Warmup has
+// cached them, the cost per request of an agent server listing its tools.
+func BenchmarkCatalogSchemasFor(b *testing.B) {
+	tools := catalog(b)
+	if _, err := Warmup(tools...); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, tool := range tools {
+			if _, err := SchemasFor(tool); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkCatalogSerialize measures serializing the catalog's schemas in the forms sent to
+// providers and stored.
+func BenchmarkCatalogSerialize(b *testing.B) {
+	schemas := catalogSchemas(b, catalog(b))
+
+	b.Run("GetSchemaString", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for i := range schemas {
+				_ = schemas[i].GetSchemaString()
+			}
+		}
+	})
+	b.Run("GetPropertiesMap", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for i := range schemas {
+				if _, err := json.Marshal(GetPropertiesMap(schemas[i])); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("GetCompactSchemaString", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for i := range schemas {
+				_ = schemas[i].GetCompactSchemaString(jobj.CompactOptions{})
+			}
+		}
+	})
+}
+
+// BenchmarkCatalogExport measures converting the catalog's input schemas for each provider with
+// Provider.Apply, uncached and through ToolSchemas.ForProvider.
+func BenchmarkCatalogExport(b *testing.B) {
+	tools := catalog(b)
+	schemas := catalogSchemas(b, tools)
+	if _, err := Warmup(tools...); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, provider := range jobj.Providers() {
+		b.Run(string(provider), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				for i := 0; i < len(schemas); i += 2 {
+					if _, _, err := provider.Apply(&schemas[i]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+		b.Run(string(provider)+"/cached", func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				for _, tool := range tools {
+					schemas, err := SchemasFor(tool)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if _, err := schemas.ForProvider(provider); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
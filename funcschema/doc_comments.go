@@ -0,0 +1,117 @@
+package funcschema
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// docComments maps struct types, by package name and type name as in "search.Params", to the
+// doc comments of their fields by Go field name. Loaded by LoadDocComments.
+var docComments sync.Map
+
+// LoadDocComments parses the Go files in dir and uses the doc comments of struct fields as the
+// descriptions of fields that have no desc, description or jsonschema description tag, so
+// documentation does not have to be repeated in tags. A field's line comment is used when it has
+// no doc comment. Types declared inside functions are included; a type name declared more than
+// once in a package is ambiguous and its comments are ignored.
+//
+// Types are matched by package name and type name, since reflection does not know where a type
+// was declared. Load comments before generating schemas that use them, e.g. in an init function;
+// the source must be available at run time, so this suits tools and tests more than deployed
+// binaries.
+//
+// Example:
+//
+//	type SearchParams struct {
+//	    // Query is the full-text search query
+//	    Query string `json:"query" required:"true"`
+//	}
+//
+//	err := funcschema.LoadDocComments("./search")
+func LoadDocComments(dir string) error {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", dir, err)
+	}
+
+	for packageName, pkg := range packages {
+		types := make(map[string]map[string]string)
+		declared := make(map[string]int)
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(node ast.Node) bool {
+				spec, ok := node.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+				structType, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					return true
+				}
+				declared[spec.Name.Name]++
+				types[spec.Name.Name] = fieldComments(structType)
+				return true
+			})
+		}
+		for typeName, comments := range types {
+			key := packageName + "." + typeName
+			if declared[typeName] > 1 {
+				docComments.Delete(key)
+				continue
+			}
+			docComments.Store(key, comments)
+		}
+	}
+	resetSchemaCache()
+	return nil
+}
+
+// fieldComments returns the comments of the fields of structType by field name.
+func fieldComments(structType *ast.StructType) map[string]string {
+	comments := make(map[string]string)
+	for _, field := range structType.Fields.List {
+		group := field.Doc
+		if group == nil {
+			group = field.Comment
+		}
+		text := strings.Join(strings.Fields(group.Text()), " ")
+		if text == "" {
+			continue
+		}
+		for _, name := range field.Names {
+			comments[name.Name] = text
+		}
+		if len(field.Names) == 0 {
+			// Embedded fields are named after their type
+			embedded := field.Type
+			if star, ok := embedded.(*ast.StarExpr); ok {
+				embedded = star.X
+			}
+			if selector, ok := embedded.(*ast.SelectorExpr); ok {
+				embedded = selector.Sel
+			}
+			if ident, ok := embedded.(*ast.Ident); ok {
+				comments[ident.Name] = text
+			}
+		}
+	}
+	return comments
+}
+
+// docComment returns the doc comment loaded by LoadDocComments for field of the innermost struct
+// on path.
+func docComment(field reflect.StructField, path structPath) string {
+	if len(path.types) == 0 {
+		return ""
+	}
+	comments, ok := docComments.Load(path.types[len(path.types)-1].String())
+	if !ok {
+		return ""
+	}
+	return comments.(map[string]string)[field.Name]
+}
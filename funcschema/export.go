@@ -0,0 +1,182 @@
+package funcschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mhpenta/jobj"
+)
+
+// ToOpenAITool returns a tool definition in the shape of the OpenAI Chat Completions tools array:
+//
+//	{"type": "function", "function": {"name": ..., "description": ..., "parameters": ...}}
+//
+// The parameters are GetPropertiesMap(schema) or, with strict set, schema.Strict() along with
+// "strict": true, for OpenAI strict mode. The result can be encoded with encoding/json as is.
+//
+// Example:
+//
+//	input, err := funcschema.SchemaFromStruct[SearchParams]()
+//	tools := []interface{}{funcschema.ToOpenAITool("search", "Search the catalog", input, true)}
+func ToOpenAITool(name string, description string, schema jobj.Schema, strict bool) map[string]interface{} {
+	function := map[string]interface{}{
+		"name":        name,
+		"description": description,
+	}
+	if strict {
+		function["parameters"] = schema.Strict()
+		function["strict"] = true
+	} else {
+		function["parameters"] = GetPropertiesMap(schema)
+	}
+	return map[string]interface{}{
+		"type":     "function",
+		"function": function,
+	}
+}
+
+// ToOllamaTool returns a tool definition in the shape of the tools array of Ollama's chat API:
+//
+//	{"type": "function", "function": {"name": ..., "description": ..., "parameters": ...}}
+//
+// Ollama takes the OpenAI format without strict mode, so the parameters are
+// GetPropertiesMap(schema).
+//
+// Example:
+//
+//	input, err := funcschema.SchemaFromStruct[SearchParams]()
+//	tools := []interface{}{funcschema.ToOllamaTool("search", "Search the catalog", input)}
+func ToOllamaTool(name string, description string, schema jobj.Schema) map[string]interface{} {
+	return ToOpenAITool(name, description, schema, false)
+}
+
+// ToBedrockToolSpec returns an entry of the tools list of the AWS Bedrock Converse API's
+// toolConfig:
+//
+//	{"toolSpec": {"name": ..., "description": ..., "inputSchema": {"json": ...}}}
+//
+// The input schema is GetPropertiesMap(schema).
+//
+// Example:
+//
+//	input, err := funcschema.SchemaFromStruct[SearchParams]()
+//	toolConfig := map[string]interface{}{
+//	    "tools": []interface{}{funcschema.ToBedrockToolSpec("search", "Search the catalog", input)},
+//	}
+func ToBedrockToolSpec(name string, description string, schema jobj.Schema) map[string]interface{} {
+	return map[string]interface{}{
+		"toolSpec": map[string]interface{}{
+			"name":        name,
+			"description": description,
+			"inputSchema": map[string]interface{}{
+				"json": GetPropertiesMap(schema),
+			},
+		},
+	}
+}
+
+// ToGeminiFunctionDeclaration returns a function declaration in the shape of Gemini's
+// FunctionDeclaration:
+//
+//	{"name": ..., "description": ..., "parameters": ...}
+//
+// The parameters are the schema as jobj.ProviderGemini.Apply converts it, so keywords Gemini
+// rejects, such as $ref, $schema and additionalProperties, are removed and const unions become
+// string enums, the only enums Gemini accepts, so an int enum of 1 and 2 is sent as "1" and
+// "2". Type names are then uppercased, as in Gemini's Type enum, and every object lists its
+// properties in declaration order under propertyOrdering, since Gemini otherwise orders them
+// alphabetically.
+//
+// Example:
+//
+//	input, err := funcschema.SchemaFromStruct[SearchParams]()
+//	declaration, err := funcschema.ToGeminiFunctionDeclaration("search", "Search the catalog", input)
+func ToGeminiFunctionDeclaration(name string, description string, schema jobj.Schema) (map[string]interface{}, error) {
+	parameters, _, err := jobj.ProviderGemini.Apply(&schema)
+	if err != nil {
+		return nil, fmt.Errorf("tool %q: %w", name, err)
+	}
+	geminiNode(parameters, schema.Fields)
+
+	return map[string]interface{}{
+		"name":        name,
+		"description": description,
+		"parameters":  parameters,
+	}, nil
+}
+
+// geminiNode uppercases the types of node and adds propertyOrdering to its objects, following
+// fields, the Fields node was rendered from, or sorted names where no field describes it.
+func geminiNode(node interface{}, fields []*jobj.Field) {
+	document, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+	// Gemini marks nullable types with nullable rather than a type list
+	if typeNames, ok := document["type"].([]interface{}); ok && len(typeNames) == 2 && typeNames[1] == "null" {
+		document["type"] = typeNames[0]
+		document["nullable"] = true
+	}
+	if typeName, ok := document["type"].(string); ok {
+		document["type"] = strings.ToUpper(typeName)
+	}
+
+	if properties, ok := document["properties"].(map[string]interface{}); ok {
+		ordering := make([]string, 0, len(properties))
+		byName := make(map[string]*jobj.Field, len(fields))
+		for _, field := range fields {
+			if _, ok := properties[field.ValueName]; ok && byName[field.ValueName] == nil {
+				ordering = append(ordering, field.ValueName)
+				byName[field.ValueName] = field
+			}
+		}
+		var unknown []string
+		for propertyName := range properties {
+			if byName[propertyName] == nil {
+				unknown = append(unknown, propertyName)
+			}
+		}
+		sort.Strings(unknown)
+		ordering = append(ordering, unknown...)
+
+		for propertyName, property := range properties {
+			geminiField(property, byName[propertyName])
+		}
+		document["propertyOrdering"] = ordering
+	}
+}
+
+// geminiField applies geminiNode to the rendering of field, which may be nil, and its items and
+// variants.
+func geminiField(node interface{}, field *jobj.Field) {
+	document, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+	var subFields []*jobj.Field
+	if field != nil && field.ValueType == jobj.TypeObject {
+		subFields = field.SubFields
+	}
+	geminiNode(document, subFields)
+
+	if items, ok := document["items"]; ok {
+		var itemField *jobj.Field
+		if field != nil {
+			itemField = field.ArrayItemField
+			if itemField == nil && len(field.SubFields) > 0 {
+				itemField = jobj.Object("", field.SubFields)
+			}
+		}
+		geminiField(items, itemField)
+	}
+	if variants, ok := document["anyOf"].([]interface{}); ok {
+		for i, variant := range variants {
+			var variantField *jobj.Field
+			if field != nil && i < len(field.ValueVariants) {
+				variantField = field.ValueVariants[i]
+			}
+			geminiField(variant, variantField)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package funcschema
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToOpenAITool(t *testing.T) {
+	tool, err := NewTool("forecast", "Weather forecast for a city", func(ctx context.Context, input toolInput) (warmupOutput, error) {
+		return warmupOutput{}, nil
+	})
+	assert.NoError(t, err)
+
+	definition := ToOpenAITool(tool.Name, tool.Description, tool.Schemas.Input, false)
+	assert.Equal(t, "function", definition["type"])
+	function := definition["function"].(map[string]interface{})
+	assert.Equal(t, "forecast", function["name"])
+	assert.Equal(t, "Weather forecast for a city", function["description"])
+	assert.Equal(t, GetPropertiesMap(tool.Schemas.Input), function["parameters"])
+	assert.NotContains(t, function, "strict")
+
+	function = ToOpenAITool(tool.Name, tool.Description, tool.Schemas.Input, true)["function"].(map[string]interface{})
+	assert.Equal(t, true, function["strict"])
+	parameters := function["parameters"].(map[string]interface{})
+	assert.Equal(t, false, parameters["additionalProperties"])
+	assert.ElementsMatch(t, []interface{}{"city", "units"}, parameters["required"])
+
+	_, err = json.Marshal(definition)
+	assert.NoError(t, err)
+}
+
+type geminiInput struct {
+	Query   string   `json:"query" required:"true"`
+	Limit   int      `json:"limit"`
+	Filters []string `json:"filters"`
+	Sort    struct {
+		Order string `json:"order" enum:"asc,desc"`
+		By    string `json:"by"`
+	} `json:"sort"`
+	Meta map[string]string `json:"meta"`
+}
+
+func TestToGeminiFunctionDeclaration(t *testing.T) {
+	schema, err := SchemaFromStruct[geminiInput]()
+	assert.NoError(t, err)
+
+	declaration, err := ToGeminiFunctionDeclaration("search", "Search the catalog", schema)
+	assert.NoError(t, err)
+	assert.Equal(t, "search", declaration["name"])
+	assert.Equal(t, "Search the catalog", declaration["description"])
+
+	parameters := declaration["parameters"].(map[string]interface{})
+	assert.Equal(t, "OBJECT", parameters["type"])
+	assert.Equal(t, []string{"query", "limit", "filters", "sort", "meta"}, parameters["propertyOrdering"])
+	assert.NotContains(t, parameters, "$schema")
+	assert.NotContains(t, parameters, "additionalProperties")
+
+	properties := parameters["properties"].(map[string]interface{})
+	assert.Equal(t, "STRING", properties["query"].(map[string]interface{})["type"])
+	assert.Equal(t, "INTEGER", properties["limit"].(map[string]interface{})["type"])
+	filters := properties["filters"].(map[string]interface{})
+	assert.Equal(t, "ARRAY", filters["type"])
+	assert.Equal(t, "STRING", filters["items"].(map[string]interface{})["type"])
+	sortField := properties["sort"].(map[string]interface{})
+	assert.Equal(t, "OBJECT", sortField["type"])
+	assert.Equal(t, []string{"order", "by"}, sortField["propertyOrdering"])
+
+	encoded, err := json.Marshal(declaration)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encoded), "$ref")
+	assert.NotContains(t, string(encoded), `"type":"string"`)
+}
+
+func TestToGeminiFunctionDeclarationIntEnum(t *testing.T) {
+	type ratingInput struct {
+		Stars int    `json:"stars" jsonschema:"enum=1,enum=3,enum=5"`
+		Mode  string `json:"mode" jsonschema:"enum=fast,enum=slow"`
+	}
+	schema, err := SchemaFromStruct[ratingInput]()
+	assert.NoError(t, err)
+
+	declaration, err := ToGeminiFunctionDeclaration("rate", "Rate a result", schema)
+	assert.NoError(t, err)
+
+	properties := declaration["parameters"].(map[string]interface{})["properties"].(map[string]interface{})
+	stars := properties["stars"].(map[string]interface{})
+	assert.Equal(t, "STRING", stars["type"])
+	assert.Equal(t, []interface{}{"1", "3", "5"}, stars["enum"])
+	assert.NotContains(t, stars, "anyOf")
+	mode := properties["mode"].(map[string]interface{})
+	assert.Equal(t, "STRING", mode["type"])
+	assert.Equal(t, []interface{}{"fast", "slow"}, mode["enum"])
+}
+
+func TestToOllamaTool(t *testing.T) {
+	schema, err := SchemaFromStruct[toolInput]()
+	assert.NoError(t, err)
+
+	definition := ToOllamaTool("forecast", "Weather forecast for a city", schema)
+	assert.Equal(t, "function", definition["type"])
+	function := definition["function"].(map[string]interface{})
+	assert.Equal(t, "forecast", function["name"])
+	assert.Equal(t, "Weather forecast for a city", function["description"])
+	assert.Equal(t, GetPropertiesMap(schema), function["parameters"])
+	assert.NotContains(t, function, "strict")
+}
+
+func TestToBedrockToolSpec(t *testing.T) {
+	schema, err := SchemaFromStruct[toolInput]()
+	assert.NoError(t, err)
+
+	definition := ToBedrockToolSpec("forecast", "Weather forecast for a city", schema)
+	spec := definition["toolSpec"].(map[string]interface{})
+	assert.Equal(t, "forecast", spec["name"])
+	assert.Equal(t, "Weather forecast for a city", spec["description"])
+	inputSchema := spec["inputSchema"].(map[string]interface{})
+	assert.Equal(t, GetPropertiesMap(schema), inputSchema["json"])
+
+	_, err = json.Marshal(definition)
+	assert.NoError(t, err)
+}
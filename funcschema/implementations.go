@@ -0,0 +1,95 @@
+package funcschema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/mhpenta/jobj"
+)
+
+// implementations maps the interface types registered with RegisterImplementations to their
+// implementationSet.
+var implementations sync.Map
+
+// implementationSet holds the concrete types registered for an interface, in name order.
+type implementationSet struct {
+	discriminator string
+	names         []string
+	types         []reflect.Type
+}
+
+// RegisterImplementations lists the concrete struct types that may fill fields of interface type
+// iface, keyed by the value of the discriminator property that tells them apart, for polymorphic
+// tool parameters such as a payment method that is either a card or a bank transfer. Fields of
+// type iface, the items of slices of it and the values of maps of it are then described by a
+// variant per implementation: the implementation's object with the discriminator as a required
+// property whose only value is its key. The variants are emitted as oneOf with a discriminator
+// object naming the property; see jobj.Field.Discriminator. Map values remain described as any
+// object.
+//
+// The discriminator property is not part of the implementations' Go types; decode the field with
+// a custom UnmarshalJSON that reads it. Register implementations before generating schemas that
+// use them, e.g. in an init function.
+//
+// Example:
+//
+//	type PaymentMethod interface{ isPaymentMethod() }
+//
+//	funcschema.RegisterImplementations(reflect.TypeOf((*PaymentMethod)(nil)).Elem(), "kind", map[string]interface{}{
+//	    "card":     Card{},
+//	    "transfer": Transfer{},
+//	})
+func RegisterImplementations(iface reflect.Type, discriminator string, implementationsByName map[string]interface{}) error {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return fmt.Errorf("expected an interface type, got %v", iface)
+	}
+	if discriminator == "" {
+		return fmt.Errorf("interface %v: discriminator must not be empty", iface)
+	}
+	if len(implementationsByName) == 0 {
+		return fmt.Errorf("interface %v: no implementations", iface)
+	}
+
+	set := &implementationSet{discriminator: discriminator}
+	for name := range implementationsByName {
+		set.names = append(set.names, name)
+	}
+	sort.Strings(set.names)
+	for _, name := range set.names {
+		value := implementationsByName[name]
+		if value == nil {
+			return fmt.Errorf("interface %v: implementation %q must not be nil", iface, name)
+		}
+		t := reflect.TypeOf(value)
+		if !t.Implements(iface) {
+			return fmt.Errorf("interface %v: implementation %q: %v does not implement it", iface, name, t)
+		}
+		if derefType(t).Kind() != reflect.Struct {
+			return fmt.Errorf("interface %v: implementation %q: expected a struct, got %v", iface, name, t)
+		}
+		set.types = append(set.types, derefType(t))
+	}
+	implementations.Store(iface, set)
+	resetSchemaCache()
+	return nil
+}
+
+// field returns the variants of the implementations nested below path.
+func (s *implementationSet) field(path structPath) *jobj.Field {
+	variants := make([]*jobj.Field, 0, len(s.types))
+	for i, t := range s.types {
+		discriminator := jobj.Text(s.discriminator).Required()
+		discriminator.ValueAnyOf = []jobj.ConstDescription{{Const: s.names[i]}}
+
+		// The discriminator is not part of the struct, so the object does not share its definition
+		subFields, ok := structSubFields(t, path)
+		if !ok {
+			variants = append(variants, genericObject(""))
+			continue
+		}
+		variants = append(variants, jobj.Object("", append([]*jobj.Field{discriminator}, subFields...)))
+	}
+	return jobj.Variants("", variants...).Discriminator(s.discriminator)
+}
@@ -0,0 +1,94 @@
+package funcschema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/mhpenta/jobj"
+)
+
+// itemTypes maps the names registered with RegisterItemType to their Go types.
+var itemTypes sync.Map
+
+// RegisterItemType names the Go type of value for use in items tags, so that []any fields can
+// declare struct items alongside JSON types:
+//
+//	funcschema.RegisterItemType("note", Note{})
+//
+//	type Row struct {
+//	    Cells []any `json:"cells" desc:"Cell values" items:"number|string|note"`
+//	}
+//
+// Register item types before generating schemas that use them, e.g. in an init function.
+func RegisterItemType(name string, value interface{}) error {
+	if name == "" {
+		return fmt.Errorf("item type name must not be empty")
+	}
+	if value == nil {
+		return fmt.Errorf("item type %q: value must not be nil", name)
+	}
+	itemTypes.Store(name, reflect.TypeOf(value))
+	resetSchemaCache()
+	return nil
+}
+
+// interfaceItemField returns the item field of a []any field: any value, unless the field's items
+// tag lists the JSON types ("string", "number", "integer", "boolean", "object", "array") or
+// registered item types the items may have, separated by "|". Several types become Variants.
+func interfaceItemField(field reflect.StructField, path structPath) *jobj.Field {
+	tag, ok := field.Tag.Lookup("items")
+	if !ok {
+		return jobj.Any("")
+	}
+
+	var variants []*jobj.Field
+	for _, name := range strings.Split(tag, "|") {
+		variant := itemTypeField(strings.TrimSpace(name), path)
+		if variant == nil {
+			logWarn("Unknown item type in items tag", "field", field.Name, "type", name)
+			continue
+		}
+		variants = append(variants, variant)
+	}
+	switch len(variants) {
+	case 0:
+		return jobj.Any("")
+	case 1:
+		return variants[0]
+	}
+	return jobj.Variants("", variants...)
+}
+
+// itemTypeField returns the field of a JSON type or registered item type, or nil if name is
+// neither.
+func itemTypeField(name string, path structPath) *jobj.Field {
+	switch jobj.DataType(name) {
+	case jobj.TypeString:
+		return jobj.Text("")
+	case jobj.TypeNumber:
+		return jobj.Float("")
+	case jobj.TypeInteger:
+		return jobj.Int("")
+	case jobj.TypeBoolean:
+		return jobj.Bool("")
+	case jobj.TypeObject:
+		return genericObject("")
+	case jobj.TypeArray:
+		return jobj.ArrayOfField("", jobj.Any(""))
+	}
+
+	registered, ok := itemTypes.Load(name)
+	if !ok {
+		return nil
+	}
+	t := registered.(reflect.Type)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		return objectField("", t, path)
+	}
+	return createFieldFromType(t, "", path)
+}
@@ -0,0 +1,121 @@
+package funcschema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mhpenta/jobj"
+)
+
+// applyJSONSchemaTag maps the jsonschema tag of field, in the convention of the
+// github.com/invopop/jsonschema and github.com/alecthomas/jsonschema generators, to keywords of
+// jobjField, so structs written for those generators need not be retagged:
+//
+//	type Params struct {
+//	    Sort string `json:"sort" jsonschema:"title=Sort order,description=How to sort results,enum=relevance,enum=date,default=relevance"`
+//	}
+//
+// The keys title, description, enum (repeated once per value), default, example, format,
+// required, minimum, maximum, minLength, maxLength, minItems, maxItems and uniqueItems are read;
+// other keys are ignored. Commas within a value are escaped as "\,". funcschema tags on the same
+// field take precedence.
+func applyJSONSchemaTag(field reflect.StructField, jobjField *jobj.Field) {
+	tag, ok := field.Tag.Lookup("jsonschema")
+	if !ok {
+		return
+	}
+
+	var enums []jobj.ConstDescription
+	var examples []interface{}
+	for _, option := range splitJSONSchemaTag(tag) {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "title":
+			jobjField.Title(value)
+		case "description":
+			jobjField.Desc(value)
+		case "required":
+			jobjField.Required()
+		case "format":
+			if jobjField.ValueType == jobj.TypeString {
+				jobjField.Format(value)
+			}
+		case "uniqueItems":
+			if jobjField.ValueType == jobj.TypeArray && value == "true" {
+				jobjField.UniqueItems()
+			}
+		case "enum", "default", "example":
+			parsed, err := tagValue(jobjField, value)
+			if err != nil {
+				logWarn("Invalid "+key+" in jsonschema tag", "field", field.Name, "value", value, "error", err)
+				continue
+			}
+			switch key {
+			case "enum":
+				enums = append(enums, jobj.ConstDescription{Const: parsed})
+			case "default":
+				jobjField.Default(parsed)
+			default:
+				examples = append(examples, parsed)
+			}
+		case "minimum", "maximum":
+			bound, err := strconv.ParseFloat(value, 64)
+			if err != nil || (jobjField.ValueType != jobj.TypeNumber && jobjField.ValueType != jobj.TypeInteger) {
+				logWarn("Ignoring "+key+" in jsonschema tag", "field", field.Name, "value", value)
+				continue
+			}
+			if key == "minimum" {
+				jobjField.Minimum(bound)
+			} else {
+				jobjField.Maximum(bound)
+			}
+		case "minLength", "maxLength", "minItems", "maxItems":
+			n, err := strconv.Atoi(value)
+			want := jobj.TypeString
+			if strings.HasSuffix(key, "Items") {
+				want = jobj.TypeArray
+			}
+			if err != nil || n < 0 || jobjField.ValueType != want {
+				logWarn("Ignoring "+key+" in jsonschema tag", "field", field.Name, "value", value)
+				continue
+			}
+			switch key {
+			case "minLength":
+				jobjField.MinLength(n)
+			case "maxLength":
+				jobjField.MaxLength(n)
+			case "minItems":
+				jobjField.MinItems(n)
+			default:
+				jobjField.MaxItems(n)
+			}
+		}
+	}
+	if len(enums) > 0 {
+		jobjField.ValueAnyOf = enums
+	}
+	if len(examples) > 0 {
+		jobjField.Examples(examples...)
+	}
+}
+
+// splitJSONSchemaTag splits a jsonschema tag into its comma-separated options, keeping commas
+// escaped as "\," within them.
+func splitJSONSchemaTag(tag string) []string {
+	var options []string
+	var option strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			option.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			options = append(options, option.String())
+			option.Reset()
+		default:
+			option.WriteByte(tag[i])
+		}
+	}
+	return append(options, option.String())
+}
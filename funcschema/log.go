@@ -0,0 +1,11 @@
+//go:build !tinygo
+
+package funcschema
+
+import "log/slog"
+
+// logWarn reports Go types that have no schema equivalent and are skipped. TinyGo builds, where
+// log/slog is too heavy for browser bundles, use the version in log_tinygo.go.
+func logWarn(msg string, args ...any) {
+	slog.Warn(msg, args...)
+}
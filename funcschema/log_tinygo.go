@@ -0,0 +1,8 @@
+//go:build tinygo
+
+package funcschema
+
+// logWarn reports Go types that have no schema equivalent and are skipped, without log/slog.
+func logWarn(msg string, args ...any) {
+	println("funcschema:", msg)
+}
@@ -0,0 +1,143 @@
+package funcschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/mhpenta/jobj"
+)
+
+// typeMappers maps the Go types registered with RegisterTypeMapper to their mappers.
+var typeMappers sync.Map
+
+// RegisterTypeMapper makes schema generation describe fields of type t with the field returned by
+// mapper, for domain types whose JSON encoding differs from their Go shape, such as decimals and
+// UUIDs encoded as strings, or named types with a fixed set of values. The mapper applies to
+// fields of type t and *t, to the items of slices of them and to the values of maps of them. The
+// field's name is set from its json tag and its struct tags, such as desc and required, apply as
+// to any other field; for items and map values the struct field is the containing field. Map
+// values are described by the type of the mapped field only, unless it is an object, as maps of
+// primitives are. A mapper must return a new Field on each call, or nil to leave the field out.
+//
+// Register type mappers before generating schemas that use them, e.g. in an init function.
+//
+// Example:
+//
+//	funcschema.RegisterTypeMapper(reflect.TypeOf(decimal.Decimal{}), func(field reflect.StructField) *jobj.Field {
+//	    return jobj.Text("").Format("decimal")
+//	})
+func RegisterTypeMapper(t reflect.Type, mapper func(field reflect.StructField) *jobj.Field) error {
+	if t == nil {
+		return fmt.Errorf("type must not be nil")
+	}
+	if mapper == nil {
+		return fmt.Errorf("type %v: mapper must not be nil", t)
+	}
+	typeMappers.Store(t, mapper)
+	resetSchemaCache()
+	return nil
+}
+
+// SchemaProvider is implemented by types that describe their own schema, for library authors
+// whose types are opaque to reflection or encode differently from their Go shape. Schema
+// generation uses the field JobjSchema returns for fields of the type, a pointer to it, slices
+// of it and maps of it, as for RegisterTypeMapper, whose mappers take precedence. JobjSchema is
+// called on a zero value and must return a new Field on each call.
+//
+// Example:
+//
+//	type Money struct {
+//	    cents int64
+//	}
+//
+//	func (Money) JobjSchema() *jobj.Field {
+//	    return jobj.Text("").Format("decimal")
+//	}
+type SchemaProvider interface {
+	JobjSchema() *jobj.Field
+}
+
+var (
+	schemaProviderType  = reflect.TypeOf((*SchemaProvider)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// mappedField returns the field a registered mapper or SchemaProvider gives for field, described
+// by type t, named name, a string field for text types or the variants of an interface with
+// registered implementations; t is the field's type or, for items and map values, the element
+// type. It returns false if neither t nor, for pointers, its element type has one.
+func mappedField(field reflect.StructField, t reflect.Type, name string, path structPath) (*jobj.Field, bool) {
+	var mapped *jobj.Field
+	if set, ok := implementations.Load(t); ok {
+		mapped = set.(*implementationSet).field(path)
+	} else if mapper, ok := typeMappers.Load(t); ok {
+		mapped = mapper.(func(field reflect.StructField) *jobj.Field)(field)
+	} else if mapper, ok := typeMappers.Load(derefType(t)); ok {
+		mapped = mapper.(func(field reflect.StructField) *jobj.Field)(field)
+	} else if provider := derefType(t); provider.Kind() != reflect.Interface && reflect.PointerTo(provider).Implements(schemaProviderType) {
+		// A pointer to the zero value has the methods of both receiver kinds
+		mapped = reflect.New(provider).Interface().(SchemaProvider).JobjSchema()
+	} else if isTextType(derefType(t)) {
+		mapped = jobj.Text("")
+	} else {
+		return nil, false
+	}
+	if mapped == nil {
+		logWarn("Type mapper or SchemaProvider returned no field", "field", field.Name, "type", t)
+		return nil, true
+	}
+	mapped.ValueName = name
+	return mapped, true
+}
+
+// setMappedValue sets the values of map field to the field mapped for them. Map values other
+// than objects are described by their type, as for maps of primitive Go types.
+func setMappedValue(field *jobj.Field, value *jobj.Field) {
+	switch value.ValueType {
+	case jobj.TypeObject:
+		field.AdditionalPropertiesField = value
+	case "":
+		// Variants, which map values cannot describe: any object
+		field.AdditionalPropertiesField = &jobj.Field{ValueType: jobj.TypeObject}
+	default:
+		field.AdditionalPropertiesType = value.ValueType
+	}
+}
+
+// derefType returns the element type of pointer type t, or t itself.
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// isUnsigned reports whether t is an unsigned integer type, described as an integer of at least 0.
+func isUnsigned(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// isTextType reports whether encoding/json encodes values of t as JSON strings through their
+// MarshalText and UnmarshalText methods, as for custom IDs, enums with names and netip.Addr:
+// t implements encoding.TextMarshaler or encoding.TextUnmarshaler, but neither json.Marshaler
+// nor json.Unmarshaler, which encoding/json would use instead.
+func isTextType(t reflect.Type) bool {
+	if t.Kind() == reflect.Interface {
+		return false
+	}
+	ptr := reflect.PointerTo(t)
+	if ptr.Implements(jsonMarshalerType) || ptr.Implements(jsonUnmarshalerType) {
+		return false
+	}
+	return ptr.Implements(textMarshalerType) || ptr.Implements(textUnmarshalerType)
+}
@@ -0,0 +1,51 @@
+package funcschema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ToolsFromStruct exposes every exported method of v with the signature
+//
+//	func(context.Context, T) (R, error)
+//
+// as a Tool named after the method, keyed by that name, so a whole service can be offered to a
+// model in one call. The tools check and decode their input like those of NewTool. Methods with
+// other signatures are skipped. Pass a pointer to include methods with pointer receivers. The
+// schemas are generated with opts. It returns an error if no method matches or schema generation
+// fails for one; the error names the method.
+//
+// Example:
+//
+//	tools, err := funcschema.ToolsFromStruct(&CalendarService{})
+//	for name, tool := range tools {
+//	    fmt.Println(name, tool.Schemas.InputJSON)
+//	}
+//	result, err := tools["CreateEvent"].Execute(ctx, arguments)
+func ToolsFromStruct(v interface{}, opts ...Option) (map[string]Tool, error) {
+	if v == nil {
+		return nil, fmt.Errorf("received nil; must provide a struct or pointer to struct")
+	}
+	value := reflect.ValueOf(v)
+	if derefType(value.Type()).Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct or pointer to struct, got %v", value.Type())
+	}
+
+	tools := make(map[string]Tool)
+	for i := 0; i < value.NumMethod(); i++ {
+		name := value.Type().Method(i).Name
+		method := value.Method(i)
+		if checkHandlerType(method.Type()) != nil {
+			continue
+		}
+		tool, err := newTool(name, "", method, opts)
+		if err != nil {
+			return nil, err
+		}
+		tools[name] = tool
+	}
+	if len(tools) == 0 {
+		return nil, fmt.Errorf("no methods of %v have the signature func(context.Context, T) (R, error)", value.Type())
+	}
+	return tools, nil
+}
@@ -0,0 +1,60 @@
+package funcschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mhpenta/jobj"
+	"github.com/stretchr/testify/assert"
+)
+
+type methodService struct {
+	prefix string
+}
+
+func (s *methodService) Forecast(ctx context.Context, input warmupInput) (warmupOutput, error) {
+	return warmupOutput{Forecast: s.prefix + input.City}, nil
+}
+
+func (s methodService) Fail(ctx context.Context, input *warmupOtherInput) (string, error) {
+	return "", errors.New("unavailable")
+}
+
+func (s *methodService) Helper(query string) string {
+	return query
+}
+
+func TestToolsFromStruct(t *testing.T) {
+	tools, err := ToolsFromStruct(&methodService{prefix: "Sunny in "})
+	assert.NoError(t, err)
+	assert.Len(t, tools, 2)
+	assert.Contains(t, tools["Forecast"].Schemas.InputProperties["properties"], "city")
+
+	assert.Equal(t, "Forecast", tools["Forecast"].Name)
+
+	result, err := tools["Forecast"].Execute(context.Background(), json.RawMessage(`{"city":"Lisbon"}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"forecast":"Sunny in Lisbon"}`, string(result))
+
+	// Input is repaired and validated as for NewTool
+	result, err = tools["Forecast"].Execute(context.Background(), json.RawMessage("```json\n{\"city\": \"Porto\",}\n```"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"forecast":"Sunny in Porto"}`, string(result))
+	_, err = tools["Fail"].Execute(context.Background(), json.RawMessage(`{}`))
+	var invalid jobj.ValidationErrors
+	assert.True(t, errors.As(err, &invalid))
+	_, err = tools["Fail"].Execute(context.Background(), json.RawMessage(`{"query":"rain"}`))
+	assert.EqualError(t, err, "unavailable")
+
+	// Methods with pointer receivers need a pointer
+	tools, err = ToolsFromStruct(methodService{})
+	assert.NoError(t, err)
+	assert.Len(t, tools, 1)
+
+	_, err = ToolsFromStruct(42)
+	assert.Error(t, err)
+	_, err = ToolsFromStruct(&struct{}{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,202 @@
+package funcschema
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/mhpenta/jobj"
+)
+
+// FieldMap translates between the property paths of a schema generated from a struct and the Go
+// fields they were generated from, so logs, metrics and evals can name a property either way.
+//
+// Property paths are JSON Pointers into instances of the schema with "*" standing for any array
+// index or map key, e.g. "/officers/*/name", the form jobj.FieldScore.Field uses. Go paths are
+// field names joined by dots, e.g. "Officers.Name"; slices and maps are stepped through without
+// an index. Names are resolved exactly as during schema generation, including json tags.
+type FieldMap struct {
+	properties []string
+	fields     map[string]string // Property path -> Go path
+	byGoPath   map[string]string // Go path -> property path
+}
+
+// NewFieldMap returns the FieldMap of struct type T, covering every property SchemaFromStruct
+// generates for it with opts, nested ones included.
+//
+// Example:
+//
+//	names, err := funcschema.NewFieldMap[Filing]()
+//	field, ok := names.Field("/officers/0/name") // "Officers.Name", true
+//	property, ok := names.Property("Officers.Name") // "/officers/*/name", true
+func NewFieldMap[T any](opts ...Option) (*FieldMap, error) {
+	o := newOptions(opts)
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	schema, err := createSchemaFromType(t, o)
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	m := &FieldMap{
+		fields:   make(map[string]string),
+		byGoPath: make(map[string]string),
+	}
+	m.addFields(schema.Fields, t, "", "", o)
+	return m, nil
+}
+
+// addFields maps fields, generated from struct t with o, below the given paths.
+func (m *FieldMap) addFields(fields []*jobj.Field, t reflect.Type, property, goPath string, o options) {
+	for _, field := range fields {
+		structField, ok := fieldByProperty(t, field.ValueName, o)
+		if !ok {
+			continue
+		}
+		fieldProperty := property + "/" + strings.ReplaceAll(strings.ReplaceAll(field.ValueName, "~", "~0"), "/", "~1")
+		fieldGoPath := structField.Name
+		if goPath != "" {
+			fieldGoPath = goPath + "." + structField.Name
+		}
+
+		m.properties = append(m.properties, fieldProperty)
+		m.fields[fieldProperty] = fieldGoPath
+		m.byGoPath[fieldGoPath] = fieldProperty
+
+		elem, nested, wildcards := nestedStruct(structField.Type, field)
+		if elem.Kind() == reflect.Struct && nested.SubFields != nil {
+			m.addFields(nested.SubFields, elem, fieldProperty+wildcards, fieldGoPath, o)
+		}
+	}
+}
+
+// nestedStruct steps from a field of Go type t through pointers, slices and maps to the type
+// whose fields are nested below it, returning that type, the jobj.Field describing it and a "/*"
+// path token per slice or map stepped through.
+func nestedStruct(t reflect.Type, field *jobj.Field) (reflect.Type, *jobj.Field, string) {
+	wildcards := ""
+	for {
+		switch {
+		case t.Kind() == reflect.Ptr:
+			t = t.Elem()
+			continue
+		case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && field.ValueType == jobj.TypeArray:
+			t = t.Elem()
+			wildcards += "/*"
+			if field.ArrayItemField != nil {
+				field = field.ArrayItemField
+				continue
+			}
+		case t.Kind() == reflect.Map && field.AdditionalPropertiesField != nil:
+			t = t.Elem()
+			wildcards += "/*"
+			field = field.AdditionalPropertiesField
+			continue
+		}
+		break
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t, field, wildcards
+}
+
+// fieldByProperty returns the field of struct t that generates the property name with o.
+func fieldByProperty(t reflect.Type, name string, o options) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); propertyName(field, o) == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// Field returns the Go path of the field generating property. Concrete JSON Pointers into an
+// instance, with array indices and map keys, are accepted as well as paths with "*".
+func (m *FieldMap) Field(property string) (string, bool) {
+	if goPath, ok := m.fields[property]; ok {
+		return goPath, true
+	}
+	// Replace indices and keys with "*" one token at a time, from the left
+	tokens := strings.Split(property, "/")
+	return m.matchField(tokens, 1)
+}
+
+func (m *FieldMap) matchField(tokens []string, from int) (string, bool) {
+	if goPath, ok := m.fields[strings.Join(tokens, "/")]; ok {
+		return goPath, true
+	}
+	for i := from; i < len(tokens); i++ {
+		if tokens[i] == "*" {
+			continue
+		}
+		original := tokens[i]
+		tokens[i] = "*"
+		goPath, ok := m.matchField(tokens, i+1)
+		tokens[i] = original
+		if ok {
+			return goPath, true
+		}
+	}
+	return "", false
+}
+
+// Property returns the property path generated by the Go field at goPath, e.g. "Officers.Name".
+func (m *FieldMap) Property(goPath string) (string, bool) {
+	property, ok := m.byGoPath[goPath]
+	return property, ok
+}
+
+// Properties returns every property path, in schema order with nested properties following
+// their parent.
+func (m *FieldMap) Properties() []string {
+	properties := make([]string, len(m.properties))
+	copy(properties, m.properties)
+	return properties
+}
+
+// goPropertyNames renames the properties of document, a JSON value decoded into an interface{}
+// for Go type t, that o names differently from encoding/json, i.e. untagged fields named by
+// WithFieldNamer, to the Go field names encoding/json matches, at any depth.
+func goPropertyNames(document interface{}, t reflect.Type, o options) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := document.(map[string]interface{})
+		if !ok || isTextType(t) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+			return
+		}
+		renamed := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := propertyName(field, o)
+			value, ok := object[name]
+			if !field.IsExported() || name == "-" || !ok {
+				continue
+			}
+			goPropertyNames(value, field.Type, o)
+			if jsonName := propertyName(field, options{}); jsonName != name {
+				delete(object, name)
+				renamed[jsonName] = value
+			}
+		}
+		for name, value := range renamed {
+			object[name] = value
+		}
+	case reflect.Slice, reflect.Array:
+		if items, ok := document.([]interface{}); ok {
+			for _, item := range items {
+				goPropertyNames(item, t.Elem(), o)
+			}
+		}
+	case reflect.Map:
+		if object, ok := document.(map[string]interface{}); ok {
+			for _, value := range object {
+				goPropertyNames(value, t.Elem(), o)
+			}
+		}
+	}
+}
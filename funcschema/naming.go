@@ -0,0 +1,79 @@
+package funcschema
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fieldNamer holds the function chosen by WithFieldNamer. Options refer to it by pointer, so they
+// stay comparable and calls with the same namer share cached schemas.
+type fieldNamer struct {
+	name func(string) string
+}
+
+// snakeCaseNamer is the namer of WithSnakeCaseNames, shared by all its calls.
+var snakeCaseNamer = &fieldNamer{name: SnakeCase}
+
+// WithFieldNamer returns an Option naming the properties of struct fields without a name in their
+// json tag with namer, which receives the Go field name. Fields with a json tag name keep it.
+// Tools built with the option, by NewTool or ToolsFromStruct, map the names back to the Go fields
+// when decoding the model's arguments, using the same namer. Schemas are cached per returned
+// Option, so create it once and reuse it rather than calling WithFieldNamer per request.
+//
+// Example:
+//
+//	camelCase := funcschema.WithFieldNamer(func(name string) string {
+//	    return strings.ToLower(name[:1]) + name[1:]
+//	})
+//	schema, err := funcschema.SchemaFromStruct[Params](camelCase)
+func WithFieldNamer(namer func(string) string) Option {
+	if namer == nil {
+		return withNamer(nil)
+	}
+	return withNamer(&fieldNamer{name: namer})
+}
+
+// WithSnakeCaseNames returns an Option naming the properties of struct fields without a name in
+// their json tag with SnakeCase, so that a NoJSONTag field becomes "no_json_tag" rather than
+// "NoJSONTag". It is WithFieldNamer(SnakeCase), except that all its calls share cached schemas.
+//
+// Example:
+//
+//	schema, err := funcschema.SchemaFromStruct[Params](funcschema.WithSnakeCaseNames())
+func WithSnakeCaseNames() Option {
+	return withNamer(snakeCaseNamer)
+}
+
+func withNamer(namer *fieldNamer) Option {
+	return func(o *options) {
+		o.namer = namer
+	}
+}
+
+// goFieldName returns the property name of an untagged Go field name with o.
+func goFieldName(name string, o options) string {
+	if o.namer != nil {
+		return o.namer.name(name)
+	}
+	return name
+}
+
+// SnakeCase converts a Go identifier to snake_case, keeping initialisms together: "UserID"
+// becomes "user_id" and "HTTPServer" becomes "http_server". It is how WithSnakeCaseNames names
+// fields.
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	b.Grow(len(name) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
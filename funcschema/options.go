@@ -0,0 +1,31 @@
+package funcschema
+
+// Option adjusts how a single call generates schemas, such as WithStrict in
+// SchemaFromStruct[Params](funcschema.WithStrict()). Calls with the same options share cached
+// schemas, and calls with different options are cached apart, so one process can describe the
+// same type both ways.
+type Option func(*options)
+
+// options holds the generation settings chosen by Options; the zero value is the default. It is
+// comparable, as it keys the schema caches along with the type.
+type options struct {
+	strict            bool
+	wrappedName       string
+	inferredRequired  bool
+	omitemptyOptional bool
+	nullablePointers  bool
+	namer             *fieldNamer
+	validateTags      bool
+	noCache           bool
+}
+
+// newOptions returns the settings chosen by opts, applied in order.
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
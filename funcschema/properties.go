@@ -2,30 +2,92 @@ package funcschema
 
 import "github.com/mhpenta/jobj"
 
-// GetPropertiesMap returns a map of properties for a schema, often useful when constructing schemas for LLM tool calls
+// GetPropertiesMap returns a map of properties for a schema, often useful when constructing schemas for LLM tool calls.
+// Struct types used by several fields are described once under "definitions" and referenced with "$ref".
 func GetPropertiesMap(schema jobj.Schema) map[string]interface{} {
-	// Check if this is a non-struct return type (has RootField)
-	if schema.RootField != nil {
-		return generateSchemaForField(schema.RootField)
-	}
+	shared, definitions := schema.ShareDefinitions("#/definitions/")
 
-	// Default: struct type with Fields
-	return map[string]interface{}{
-		"type":                 "object",
-		"properties":           schema.FieldsJson(),
-		"required":             schema.RequiredFields(),
-		"additionalProperties": false,
+	var properties map[string]interface{}
+	if shared.RootField != nil {
+		// Non-struct return type
+		properties = generateSchemaForField(shared.RootField)
+	} else {
+		// Default: struct type with Fields
+		properties = map[string]interface{}{
+			"type":                 "object",
+			"properties":           shared.FieldsJson(),
+			"required":             shared.RequiredFields(),
+			"additionalProperties": shared.RootAdditionalProperties(),
+		}
+	}
+	if definitions != nil {
+		properties["definitions"] = definitions
 	}
+	shared.FinishDocument(properties)
+	return properties
 }
 
 // generateSchemaForField creates a JSON schema for a single field (used for non-struct return types)
 func generateSchemaForField(field *jobj.Field) map[string]interface{} {
 	schema := make(map[string]interface{})
 
-	switch field.ValueType {
-	case jobj.TypeArray:
+	if field.ValueRef != "" {
+		schema["$ref"] = field.ValueRef
+		if field.ValueDescription != "" {
+			schema["description"] = field.ValueDescription
+		}
+		return schema
+	}
+
+	if field.ValueAnyOf != nil {
+		// Enum values keep their Go type, so integer and boolean consts are emitted as JSON numbers and booleans
+		anyOf := make([]map[string]interface{}, 0, len(field.ValueAnyOf))
+		for _, enum := range field.ValueAnyOf {
+			anyOf = append(anyOf, map[string]interface{}{
+				"const":       enum.Const,
+				"description": enum.Description,
+			})
+		}
+		schema["anyOf"] = anyOf
+
+		if field.ValueDescription != "" {
+			schema["description"] = field.ValueDescription
+		}
+		if field.ValueTitle != "" {
+			schema["title"] = field.ValueTitle
+		}
+		if field.ValueDefault != nil {
+			schema["default"] = field.ValueDefault
+		}
+		if len(field.ValueExamples) > 0 {
+			schema["examples"] = field.ValueExamples
+		}
+		if field.ValueDeprecated {
+			schema["deprecated"] = true
+		}
+		return schema
+	}
+
+	switch {
+	case field.ValueVariants != nil:
+		variants := make([]map[string]interface{}, 0, len(field.ValueVariants))
+		for _, variant := range field.ValueVariants {
+			variants = append(variants, generateSchemaForField(variant))
+		}
+		if field.ValueDiscriminator != "" {
+			schema["oneOf"] = variants
+			schema["discriminator"] = map[string]interface{}{"propertyName": field.ValueDiscriminator}
+		} else {
+			schema["anyOf"] = variants
+		}
+	case field.ValueType == "":
+		// Any value
+	case field.ValueType == jobj.TypeArray:
 		schema["type"] = "array"
-		if field.ArrayItemType != "" {
+		if field.ArrayItemField != nil {
+			// Array whose items are a full field, e.g. nested arrays
+			schema["items"] = generateSchemaForField(field.ArrayItemField)
+		} else if field.ArrayItemType != "" {
 			// Array of primitives
 			schema["items"] = map[string]interface{}{
 				"type": string(field.ArrayItemType),
@@ -35,13 +97,17 @@ func generateSchemaForField(field *jobj.Field) map[string]interface{} {
 			schema["items"] = map[string]interface{}{
 				"type":       "object",
 				"properties": generatePropertiesForFields(field.SubFields),
+				"required":   generateRequiredForFields(field.SubFields),
 			}
 		}
-	case jobj.TypeObject:
+	case field.ValueType == jobj.TypeObject:
 		schema["type"] = "object"
 		if field.AdditionalProperties {
 			// This is a map
-			if field.AdditionalPropertiesType != "" {
+			if field.AdditionalPropertiesType != "" && field.AdditionalPropertiesField != nil {
+				// Map with primitive values described by a field, e.g. with a minimum
+				schema["additionalProperties"] = generateSchemaForField(field.AdditionalPropertiesField)
+			} else if field.AdditionalPropertiesType != "" {
 				// Map with primitive values
 				schema["additionalProperties"] = map[string]interface{}{
 					"type": string(field.AdditionalPropertiesType),
@@ -56,21 +122,76 @@ func generateSchemaForField(field *jobj.Field) map[string]interface{} {
 					schema["additionalProperties"] = map[string]interface{}{
 						"type":       "object",
 						"properties": generatePropertiesForFields(field.AdditionalPropertiesField.SubFields),
+						"required":   generateRequiredForFields(field.AdditionalPropertiesField.SubFields),
 					}
 				}
 			}
 		} else if field.SubFields != nil {
 			// Regular object with defined properties
 			schema["properties"] = generatePropertiesForFields(field.SubFields)
+			schema["required"] = generateRequiredForFields(field.SubFields)
 		}
 	default:
 		// Primitive types
 		schema["type"] = string(field.ValueType)
+		if field.ValueFormat != "" {
+			schema["format"] = field.ValueFormat
+		}
+		if field.ValueContentEncoding != "" {
+			schema["contentEncoding"] = field.ValueContentEncoding
+		}
+		if field.ValueMinLength != nil && field.ValueType == jobj.TypeString {
+			schema["minLength"] = *field.ValueMinLength
+		}
+		if field.ValueMaxLength != nil && field.ValueType == jobj.TypeString {
+			schema["maxLength"] = *field.ValueMaxLength
+		}
+		if field.ValueMinimum != nil && field.ValueType != jobj.TypeString {
+			schema["minimum"] = *field.ValueMinimum
+		}
+		if field.ValueMaximum != nil && field.ValueType != jobj.TypeString {
+			schema["maximum"] = *field.ValueMaximum
+		}
 	}
 
 	if field.ValueDescription != "" {
 		schema["description"] = field.ValueDescription
 	}
+	if field.ValueTitle != "" {
+		schema["title"] = field.ValueTitle
+	}
+	if field.ValueDefault != nil {
+		schema["default"] = field.ValueDefault
+	}
+	if len(field.ValueExamples) > 0 {
+		schema["examples"] = field.ValueExamples
+	}
+	if field.ValueDeprecated {
+		schema["deprecated"] = true
+	}
+	if field.ValueNullable {
+		if valueType, ok := schema["type"].(string); ok {
+			schema["type"] = []string{valueType, "null"}
+		} else if anyOf, ok := schema["anyOf"].([]map[string]interface{}); ok {
+			schema["anyOf"] = append(anyOf, map[string]interface{}{"type": "null"})
+		} else if oneOf, ok := schema["oneOf"].([]map[string]interface{}); ok {
+			schema["oneOf"] = append(oneOf, map[string]interface{}{"type": "null"})
+		}
+	}
+	if field.ValueType == jobj.TypeArray {
+		if items, ok := schema["items"].(map[string]interface{}); ok && field.ValueItemsDescription != "" {
+			items["description"] = field.ValueItemsDescription
+		}
+		if field.ValueMinItems != nil {
+			schema["minItems"] = *field.ValueMinItems
+		}
+		if field.ValueMaxItems != nil {
+			schema["maxItems"] = *field.ValueMaxItems
+		}
+		if field.ValueUniqueItems {
+			schema["uniqueItems"] = true
+		}
+	}
 
 	return schema
 }
@@ -83,3 +204,15 @@ func generatePropertiesForFields(fields []*jobj.Field) map[string]interface{} {
 	}
 	return properties
 }
+
+// generateRequiredForFields lists the names of the required fields, for the required keyword of
+// the object they belong to
+func generateRequiredForFields(fields []*jobj.Field) []string {
+	required := []string{}
+	for _, f := range fields {
+		if f.ValueRequired {
+			required = append(required, f.ValueName)
+		}
+	}
+	return required
+}
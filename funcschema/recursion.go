@@ -0,0 +1,157 @@
+package funcschema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mhpenta/jobj"
+)
+
+// maxStructDepth caps how many structs deep schema generation expands nested structs. Deeper
+// structs are described as generic objects, like recursive ones.
+const maxStructDepth = 32
+
+// ErrRecursiveType is returned by CheckRecursion for types that contain themselves.
+var ErrRecursiveType = errors.New("recursive type")
+
+// structPath holds the struct types being expanded, outermost first, along with the options of
+// the call generating them. A struct that is already on the path refers to itself, e.g. a tree
+// node with a Children []Node field, and expanding it again would never end.
+type structPath struct {
+	types   []reflect.Type
+	options options
+}
+
+// enter returns the path extended by t, or false if t is already on the path or the path is
+// maxStructDepth deep.
+func (p structPath) enter(t reflect.Type) (structPath, bool) {
+	if len(p.types) >= maxStructDepth {
+		logWarn("Nested struct too deep, described as a generic object", "type", t, "depth", len(p.types))
+		return structPath{}, false
+	}
+	for _, outer := range p.types {
+		if outer == t {
+			return structPath{}, false
+		}
+	}
+	// Copy so sibling fields do not share the backing array
+	return structPath{types: append(p.types[:len(p.types):len(p.types)], t), options: p.options}, true
+}
+
+// structSubFields returns the fields of struct t nested below path, or false if t cannot be
+// expanded there.
+func structSubFields(t reflect.Type, path structPath) ([]*jobj.Field, bool) {
+	inner, ok := path.enter(t)
+	if !ok {
+		return nil, false
+	}
+	subFields := make([]*jobj.Field, 0)
+	for i := 0; i < t.NumField(); i++ {
+		subField := createFieldFromStructField(t.Field(i), inner)
+		if subField != nil {
+			subFields = append(subFields, subField)
+		}
+	}
+	return subFields, true
+}
+
+// genericObject returns a field accepting any object, used where a struct cannot be expanded.
+func genericObject(name string) *jobj.Field {
+	return &jobj.Field{
+		ValueName:                 name,
+		ValueType:                 jobj.TypeObject,
+		AdditionalProperties:      true,
+		AdditionalPropertiesField: &jobj.Field{ValueType: jobj.TypeObject},
+	}
+}
+
+// objectField returns an object field for struct t, or a generic object if t is recursive.
+func objectField(name string, t reflect.Type, path structPath) *jobj.Field {
+	subFields, ok := structSubFields(t, path)
+	if !ok {
+		return genericObject(name)
+	}
+	return jobj.Object(name, subFields).Definition(definitionName(t))
+}
+
+// objectArrayField returns an array field of struct t, or an array of generic objects if t is
+// recursive.
+func objectArrayField(name string, t reflect.Type, path structPath) *jobj.Field {
+	subFields, ok := structSubFields(t, path)
+	if !ok {
+		return jobj.ArrayOfField(name, genericObject(""))
+	}
+	return jobj.Array(name, subFields).Definition(definitionName(t))
+}
+
+// definitionName returns the name under which the shape of struct t is shared when several
+// fields use it: the type's name, or "" for anonymous and generic structs, whose names are not
+// usable as definition keys.
+func definitionName(t reflect.Type) string {
+	if strings.ContainsAny(t.Name(), "[]") {
+		return ""
+	}
+	return t.Name()
+}
+
+// objectValueField returns the additionalProperties field of a map with struct t values. A
+// recursive t accepts any value, as for map[string]interface{}.
+func objectValueField(t reflect.Type, path structPath) *jobj.Field {
+	subFields, ok := structSubFields(t, path)
+	if !ok {
+		return &jobj.Field{ValueType: jobj.TypeObject}
+	}
+	return &jobj.Field{ValueType: jobj.TypeObject, SubFields: subFields}
+}
+
+// CheckRecursion returns an error wrapping ErrRecursiveType if T contains itself through its
+// exported fields, naming the fields that lead back to it. Schema generation describes the
+// recursive part of such types as a generic object rather than failing, so callers that need a
+// fully described schema can check their parameter types with CheckRecursion first.
+//
+// Example:
+//
+//	type Comment struct {
+//	    Text    string    `json:"text" desc:"Comment text"`
+//	    Replies []Comment `json:"replies" desc:"Replies to the comment"`
+//	}
+//
+//	err := CheckRecursion[Comment]() // recursive type: Comment.Replies -> Comment
+func CheckRecursion[T any]() error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if trail := findRecursion(t, nil, nil); trail != nil {
+		return fmt.Errorf("%w: %s", ErrRecursiveType, strings.Join(trail, " -> "))
+	}
+	return nil
+}
+
+// findRecursion returns the fields leading from a struct on path back to itself, or nil.
+func findRecursion(t reflect.Type, path []reflect.Type, trail []string) []string {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return findRecursion(t.Elem(), path, trail)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	for i, outer := range path {
+		if outer == t {
+			return append(trail[i:], t.Name())
+		}
+	}
+	path = append(path[:len(path):len(path)], t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		step := append(trail[:len(trail):len(trail)], t.Name()+"."+field.Name)
+		if found := findRecursion(field.Type, path, step); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package funcschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type treeNode struct {
+	Label    string              `json:"label" desc:"Node label" required:"true"`
+	Parent   *treeNode           `json:"parent" desc:"Parent node"`
+	Children []treeNode          `json:"children" desc:"Child nodes"`
+	ByName   map[string]treeNode `json:"by_name" desc:"Children by label"`
+}
+
+type thread struct {
+	Title string    `json:"title" desc:"Thread title"`
+	Root  comment   `json:"root" desc:"First comment"`
+	Tree  *treeNode `json:"tree" desc:"Topic tree"`
+}
+
+type comment struct {
+	Text    string    `json:"text" desc:"Comment text"`
+	Replies []comment `json:"replies" desc:"Replies"`
+}
+
+func TestRecursiveTypes(t *testing.T) {
+	handler := func(ctx context.Context, params thread) ([]treeNode, error) {
+		return nil, nil
+	}
+
+	input, output, err := NewSchemasFromFunc(handler)
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(input))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+
+	root := properties["properties"].(map[string]interface{})["root"].(map[string]interface{})
+	replies := root["properties"].(map[string]interface{})["replies"].(map[string]interface{})
+	assert.Equal(t, "array", replies["type"])
+	items := replies["items"].(map[string]interface{})
+	assert.Equal(t, "object", items["type"])
+	assert.Equal(t, true, items["additionalProperties"])
+	assert.NotContains(t, items, "properties")
+
+	tree := properties["properties"].(map[string]interface{})["tree"].(map[string]interface{})
+	treeProperties := tree["properties"].(map[string]interface{})
+	assert.Equal(t, "object", treeProperties["parent"].(map[string]interface{})["type"])
+	assert.Equal(t, true, treeProperties["parent"].(map[string]interface{})["additionalProperties"])
+	assert.Equal(t, "Parent node", treeProperties["parent"].(map[string]interface{})["description"])
+	assert.Equal(t, true, treeProperties["by_name"].(map[string]interface{})["additionalProperties"])
+
+	// The output root is an array of nodes, each expanded once
+	outputProperties := GetPropertiesMap(output)
+	assert.Equal(t, "array", outputProperties["type"])
+	nodeProperties := outputProperties["items"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Contains(t, nodeProperties, "label")
+	assert.Equal(t, true, nodeProperties["children"].(map[string]interface{})["items"].(map[string]interface{})["additionalProperties"])
+
+	assert.NotEmpty(t, input.GetSchemaString())
+}
+
+func TestCheckRecursion(t *testing.T) {
+	err := CheckRecursion[thread]()
+	assert.True(t, errors.Is(err, ErrRecursiveType))
+	assert.EqualError(t, err, "recursive type: comment.Replies -> comment")
+
+	err = CheckRecursion[*treeNode]()
+	assert.EqualError(t, err, "recursive type: treeNode.Parent -> treeNode")
+
+	type flat struct {
+		Name  string   `json:"name"`
+		Inner struct{} `json:"inner"`
+		Items []int    `json:"items"`
+	}
+	assert.NoError(t, CheckRecursion[flat]())
+}
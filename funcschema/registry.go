@@ -0,0 +1,95 @@
+package funcschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrToolNotFound is returned by Registry.Dispatch for names that are not registered.
+var ErrToolNotFound = errors.New("tool not found")
+
+// Registry collects Tools by name and dispatches the model's tool calls to them. A Registry is
+// safe for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	tools map[string]Tool
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{tools: make(map[string]Tool)}
+}
+
+// Register adds tool under its Name. It returns an error if the name is empty or already
+// registered, or if the tool was not created with NewTool.
+func (r *Registry) Register(tool Tool) error {
+	if tool.Name == "" {
+		return fmt.Errorf("tool name must not be empty")
+	}
+	if !tool.function.IsValid() {
+		return fmt.Errorf("tool %q has no handler; create it with NewTool", tool.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tools[tool.Name]; ok {
+		return fmt.Errorf("tool %q is already registered", tool.Name)
+	}
+	r.tools[tool.Name] = tool
+	return nil
+}
+
+// Get returns the tool registered under name.
+func (r *Registry) Get(name string) (Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tool, ok := r.tools[name]
+	return tool, ok
+}
+
+// List returns the registered tools sorted by name, e.g. to build a request's tool definitions.
+func (r *Registry) List() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(r.tools))
+	for _, tool := range r.tools {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+	return tools
+}
+
+// Dispatch calls the tool registered under name with rawArgs, the JSON arguments of the model's
+// tool call, and returns its JSON result. The arguments are repaired, defaulted and checked
+// against the tool's input schema by Tool.Execute; mismatches are returned as a
+// jobj.ValidationErrors, which can be sent back to the model to correct its call, without
+// invoking the tool. Unknown names return an error wrapping ErrToolNotFound.
+//
+// Example:
+//
+//	result, err := registry.Dispatch(ctx, call.Name, call.Arguments)
+//	var invalid jobj.ValidationErrors
+//	if errors.As(err, &invalid) {
+//	    // Ask the model to fix its arguments
+//	}
+func (r *Registry) Dispatch(ctx context.Context, name string, rawArgs json.RawMessage) (json.RawMessage, error) {
+	tool, ok := r.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrToolNotFound, name)
+	}
+
+	result, err := tool.Execute(ctx, rawArgs)
+	if err != nil {
+		return nil, fmt.Errorf("tool %q: %w", name, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package funcschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mhpenta/jobj"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	forecast, err := NewTool("forecast", "Weather forecast for a city", func(ctx context.Context, input toolInput) (warmupOutput, error) {
+		return warmupOutput{Forecast: "Rain in " + input.City}, nil
+	})
+	assert.NoError(t, err)
+	search, err := NewTool("search", "Search the catalog", func(ctx context.Context, input warmupOtherInput) ([]string, error) {
+		return []string{input.Query}, nil
+	})
+	assert.NoError(t, err)
+
+	registry := NewRegistry()
+	assert.NoError(t, registry.Register(search))
+	assert.NoError(t, registry.Register(forecast))
+	assert.Error(t, registry.Register(forecast))
+	assert.Error(t, registry.Register(Tool{Name: "empty"}))
+
+	tools := registry.List()
+	assert.Equal(t, []string{"forecast", "search"}, []string{tools[0].Name, tools[1].Name})
+	tool, ok := registry.Get("search")
+	assert.True(t, ok)
+	assert.Equal(t, "Search the catalog", tool.Description)
+
+	result, err := registry.Dispatch(context.Background(), "forecast", json.RawMessage(`{"city":"Porto"}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"forecast":"Rain in Porto"}`, string(result))
+
+	// Arguments are repaired before they are validated
+	result, err = registry.Dispatch(context.Background(), "forecast", json.RawMessage("```json\n{\"city\": \"Braga\",}\n```"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"forecast":"Rain in Braga"}`, string(result))
+
+	// Invalid arguments are reported without calling the tool
+	_, err = registry.Dispatch(context.Background(), "search", json.RawMessage(`{"query":42}`))
+	var invalid jobj.ValidationErrors
+	assert.True(t, errors.As(err, &invalid))
+	assert.Len(t, invalid, 1)
+	assert.Equal(t, "/query", invalid[0].Pointer)
+
+	_, err = registry.Dispatch(context.Background(), "search", json.RawMessage(`  `))
+	assert.ErrorContains(t, err, "failed to decode input")
+	_, err = registry.Dispatch(context.Background(), "missing", json.RawMessage(`{}`))
+	assert.True(t, errors.Is(err, ErrToolNotFound))
+}
+
+type snakeCaseInput struct {
+	UserID  string `desc:"User to look up" required:"true"`
+	Filters []struct {
+		FieldName string `desc:"Field to filter on"`
+	} `desc:"Filters"`
+	Limit int `json:"limit,omitempty" desc:"Maximum results"`
+}
+
+func TestDispatchSnakeCaseNames(t *testing.T) {
+	lookup, err := NewTool("lookup", "Look up a user", func(ctx context.Context, input snakeCaseInput) (snakeCaseInput, error) {
+		return input, nil
+	}, WithSnakeCaseNames())
+	assert.NoError(t, err)
+	assert.Contains(t, lookup.Schemas.InputProperties["properties"], "user_id")
+
+	registry := NewRegistry()
+	assert.NoError(t, registry.Register(lookup))
+
+	// Snake case properties decode into the untagged Go fields
+	result, err := registry.Dispatch(context.Background(), "lookup", json.RawMessage(`{"user_id":"42","filters":[{"field_name":"city"}],"limit":3}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"UserID":"42","Filters":[{"FieldName":"city"}],"limit":3}`, string(result))
+
+	_, err = registry.Dispatch(context.Background(), "lookup", json.RawMessage(`{"UserID":"42"}`))
+	var invalid jobj.ValidationErrors
+	assert.True(t, errors.As(err, &invalid))
+}
+
+func TestDispatchFieldNamer(t *testing.T) {
+	kebabCase := WithFieldNamer(func(name string) string {
+		return strings.ReplaceAll(SnakeCase(name), "_", "-")
+	})
+	handler := func(ctx context.Context, input snakeCaseInput) (snakeCaseInput, error) {
+		return input, nil
+	}
+	lookup, err := NewTool("lookup", "Look up a user", handler, kebabCase)
+	assert.NoError(t, err)
+	assert.Contains(t, lookup.Schemas.InputProperties["properties"], "user-id")
+
+	// The namer used for the schema also decodes the arguments
+	result, err := lookup.Execute(context.Background(), json.RawMessage(`{"user-id":"42","filters":[{"field-name":"city"}]}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"UserID":"42","Filters":[{"FieldName":"city"}]}`, string(result))
+
+	// Calls with the same Option share cached schemas
+	again, err := NewTool("lookup", "Look up a user", handler, kebabCase)
+	assert.NoError(t, err)
+	assert.Same(t, lookup.Schemas, again.Schemas)
+	snakeCase, err := NewTool("lookup", "Look up a user", handler, WithSnakeCaseNames())
+	assert.NoError(t, err)
+	assert.Contains(t, snakeCase.Schemas.InputProperties["properties"], "user_id")
+}
@@ -0,0 +1,90 @@
+package funcschema
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/mhpenta/jobj"
+)
+
+// WithInferredRequired returns an Option making struct fields required unless they are pointers
+// or their json tag has omitempty, so optionality is modelled by the Go types rather than by
+// required:"true" tags on every field. A required tag still takes precedence, with
+// required:"false" making a field optional.
+//
+// Example:
+//
+//	type Params struct {
+//	    Query string `json:"query" desc:"Search query"`        // required
+//	    Limit *int   `json:"limit" desc:"Maximum results"`     // optional
+//	    Sort  string `json:"sort,omitempty" desc:"Sort order"` // optional
+//	}
+//
+//	schema, err := funcschema.SchemaFromStruct[Params](funcschema.WithInferredRequired())
+func WithInferredRequired() Option {
+	return func(o *options) {
+		o.inferredRequired = true
+	}
+}
+
+// WithOmitemptyOptional returns an Option keeping fields whose json tag has omitempty optional,
+// even when a required tag, a validate or jsonschema required rule, or WithInferredRequired would
+// make them required, so the schema does not demand fields that encoding/json leaves out of the
+// objects it produces. Overridden required tags are logged.
+func WithOmitemptyOptional() Option {
+	return func(o *options) {
+		o.omitemptyOptional = true
+	}
+}
+
+// WithNullablePointers returns an Option making pointer fields nullable, emitted with "null" added
+// to their type as in ["string", "null"], rather than only optional, for strict OpenAI structured
+// outputs and validators that expect a missing value to be sent as null.
+func WithNullablePointers() Option {
+	return func(o *options) {
+		o.nullablePointers = true
+	}
+}
+
+// applyNullablePointer makes jobjField nullable if o makes pointer fields nullable and field is a
+// pointer.
+func applyNullablePointer(field reflect.StructField, jobjField *jobj.Field, o options) {
+	if o.nullablePointers && field.Type.Kind() == reflect.Ptr {
+		jobjField.Nullable()
+	}
+}
+
+// applyOmitemptyOptional makes jobjField optional if o keeps omitempty fields optional and field
+// is omitempty.
+func applyOmitemptyOptional(field reflect.StructField, jobjField *jobj.Field, o options) {
+	if !o.omitemptyOptional || !hasOmitempty(field) {
+		return
+	}
+	if jobjField.ValueRequired && field.Tag.Get("required") == "true" {
+		logWarn("Ignoring required tag on omitempty field", "field", field.Name)
+	}
+	jobjField.Optional()
+}
+
+// applyInferredRequired marks jobjField required if o infers requiredness and field is neither a
+// pointer nor omitempty.
+func applyInferredRequired(field reflect.StructField, jobjField *jobj.Field, o options) {
+	if o.inferredRequired && field.Type.Kind() != reflect.Ptr && !hasOmitempty(field) {
+		jobjField.Required()
+	}
+}
+
+// hasOmitempty reports whether the json tag of field has the omitempty option.
+func hasOmitempty(field reflect.StructField) bool {
+	jsonTag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return false
+	}
+	options := strings.Split(jsonTag, ",")[1:]
+	for _, option := range options {
+		if option == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
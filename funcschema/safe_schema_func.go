@@ -8,7 +8,8 @@ import (
 // This function provides a type-safe wrapper around schema generation using generics.
 //
 // Type parameters:
-//   - T: The type of the second parameter (must be a struct or pointer to struct)
+//   - T: The type of the second parameter (a struct or pointer to struct, or any supported
+//     type to be wrapped as by NewSchemaFromFuncV2)
 //   - R: The return type of the function (can be any type)
 //
 // The function accepts handlers with the signature:
@@ -16,7 +17,7 @@ import (
 //	func(context.Context, T) (R, error)
 //
 // It returns a map containing the JSON Schema properties derived from T's structure.
-// If schema generation fails (e.g., T is not a struct or has no exported fields),
+// If schema generation fails (e.g., T is a struct with no exported fields),
 // an error is returned and the properties map will be nil.
 //
 // This function is a convenient alternative to calling NewSchemaFromFuncV2 and
@@ -27,8 +28,8 @@ import (
 // equivalently for convenience.
 //
 // Internally, we use this to transform Go functions into "Tools" for LLM Agents.
-func SafeSchemaFromFunc[T any, R any](function func(context.Context, T) (R, error)) (map[string]interface{}, error) {
-	schema, err := NewSchemaFromFuncV2(function)
+func SafeSchemaFromFunc[T any, R any](function func(context.Context, T) (R, error), opts ...Option) (map[string]interface{}, error) {
+	schema, err := NewSchemaFromFuncV2(function, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -40,8 +41,9 @@ func SafeSchemaFromFunc[T any, R any](function func(context.Context, T) (R, erro
 // schema generation using generics.
 //
 // Type parameters:
-//   - T: The type of the second parameter (must be a struct or pointer to struct)
-//   - R: The return type of the function (must be a struct or pointer to struct)
+//   - T: The type of the second parameter; types other than structs are wrapped as in
+//     NewSchemasFromFunc
+//   - R: The return type of the function
 //
 // The function accepts handlers with the signature:
 //
@@ -51,8 +53,8 @@ func SafeSchemaFromFunc[T any, R any](function func(context.Context, T) (R, erro
 //  1. Input properties map: JSON Schema properties derived from T's structure
 //  2. Output properties map: JSON Schema properties derived from R's structure
 //
-// If schema generation fails (e.g., T or R is not a struct, or has no exported fields),
-// an error is returned and both properties maps will be nil.
+// If schema generation fails (e.g., T or R is a struct with no exported fields), an error is
+// returned and both properties maps will be nil.
 //
 // This function is a convenient alternative to calling NewSchemasFromFunc and
 // GetPropertiesMap separately while maintaining compile-time type checking. Use this
@@ -65,8 +67,8 @@ func SafeSchemaFromFunc[T any, R any](function func(context.Context, T) (R, erro
 //
 // Internally, we use this to transform Go functions into "Tools" for LLM Agents where
 // both input parameter validation and output structure validation are required.
-func SafeSchemasFromFunc[T any, R any](function func(context.Context, T) (R, error)) (map[string]interface{}, map[string]interface{}, error) {
-	schemaIn, schemaOut, err := NewSchemasFromFunc(function)
+func SafeSchemasFromFunc[T any, R any](function func(context.Context, T) (R, error), opts ...Option) (map[string]interface{}, map[string]interface{}, error) {
+	schemaIn, schemaOut, err := NewSchemasFromFunc(function, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
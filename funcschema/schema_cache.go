@@ -0,0 +1,127 @@
+package funcschema
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/mhpenta/jobj"
+)
+
+// schemaCache maps a schemaKey to the fields generated for it, so the entry points do not walk
+// the same type again. It is on by default: SchemaFromStruct, NewSchemaFromFunc and the other
+// entry points reflect over each struct type once per set of Options and afterwards return a
+// copy of the cached fields, so generating schemas per request in an agent loop is cheap. The
+// copy is the caller's to modify. Cleared whenever a global setting that changes generation, such
+// as RegisterTypeMapper, is set.
+var schemaCache sync.Map
+
+// schemaKey identifies the fields generated for a struct type with a call's options.
+type schemaKey struct {
+	t       reflect.Type
+	options options
+}
+
+// WithoutSchemaCache returns an Option generating the schemas of its call afresh, without reading
+// or storing the cached fields of struct types or the schemas cached by SchemasFor.
+//
+// Example:
+//
+//	schema, err := funcschema.SchemaFromStruct[Params](funcschema.WithoutSchemaCache())
+func WithoutSchemaCache() Option {
+	return func(o *options) {
+		o.noCache = true
+	}
+}
+
+// resetSchemaCache clears schemaCache and toolCache, for settings that change the schemas
+// generated for a type.
+func resetSchemaCache() {
+	for _, cache := range []*sync.Map{&schemaCache, &toolCache} {
+		cache.Range(func(key, value interface{}) bool {
+			cache.Delete(key)
+			return true
+		})
+	}
+}
+
+// structSchemaFields returns the fields of the schema of struct t generated with o, or an error
+// from checkSupported.
+func structSchemaFields(t reflect.Type, o options) ([]*jobj.Field, error) {
+	key := schemaKey{t: t, options: o}
+	if !o.noCache {
+		if cached, ok := schemaCache.Load(key); ok {
+			return cloneFields(cached.([]*jobj.Field)), nil
+		}
+	}
+
+	fields := make([]*jobj.Field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		jobjField := createFieldFromStructField(field, structPath{types: []reflect.Type{t}, options: o})
+		if jobjField != nil {
+			fields = append(fields, jobjField)
+		}
+	}
+
+	if err := checkSupported(t, fields, o); err != nil {
+		return nil, err
+	}
+
+	if !o.noCache {
+		schemaCache.Store(key, cloneFields(fields))
+	}
+	return fields, nil
+}
+
+// cloneFields returns a deep copy of fields.
+func cloneFields(fields []*jobj.Field) []*jobj.Field {
+	if fields == nil {
+		return nil
+	}
+	clones := make([]*jobj.Field, len(fields))
+	for i, field := range fields {
+		clones[i] = cloneField(field)
+	}
+	return clones
+}
+
+// cloneField returns a deep copy of field. Defaults and examples are copied as values.
+func cloneField(field *jobj.Field) *jobj.Field {
+	if field == nil {
+		return nil
+	}
+	clone := *field
+	clone.ValueExamples = cloneSlice(field.ValueExamples)
+	clone.ValueAnyOf = cloneSlice(field.ValueAnyOf)
+	clone.ValueMinItems = clonePointer(field.ValueMinItems)
+	clone.ValueMaxItems = clonePointer(field.ValueMaxItems)
+	clone.ValueMinLength = clonePointer(field.ValueMinLength)
+	clone.ValueMaxLength = clonePointer(field.ValueMaxLength)
+	clone.ValueMinimum = clonePointer(field.ValueMinimum)
+	clone.ValueMaximum = clonePointer(field.ValueMaximum)
+	clone.ValueVariants = cloneFields(field.ValueVariants)
+	clone.SubFields = cloneFields(field.SubFields)
+	clone.ArrayItemField = cloneField(field.ArrayItemField)
+	clone.AdditionalPropertiesField = cloneField(field.AdditionalPropertiesField)
+	return &clone
+}
+
+func cloneSlice[T any](values []T) []T {
+	if values == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(values)), values...)
+}
+
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
@@ -23,9 +23,11 @@ import (
 //	}
 //
 //	schema, err := SchemaFromStruct[User]()
-func SchemaFromStruct[T any]() (jobj.Schema, error) {
+//
+// Options such as WithStrict adjust the generation for this call only.
+func SchemaFromStruct[T any](opts ...Option) (jobj.Schema, error) {
 	var zero T
-	return createSchemaFromType(reflect.TypeOf(zero))
+	return createSchemaFromType(reflect.TypeOf(zero), newOptions(opts))
 }
 
 // createSchemaFromType generates a jobj.Schema from a reflect.Type.
@@ -37,7 +39,7 @@ func SchemaFromStruct[T any]() (jobj.Schema, error) {
 //
 // This is the underlying implementation used by SchemaFromStruct and the function
 // schema generators.
-func createSchemaFromType(t reflect.Type) (jobj.Schema, error) {
+func createSchemaFromType(t reflect.Type, o options) (jobj.Schema, error) {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
@@ -49,21 +51,13 @@ func createSchemaFromType(t reflect.Type) (jobj.Schema, error) {
 	schema := jobj.Schema{
 		Name:        t.Name(),
 		Description: fmt.Sprintf("Schema for %s", t.Name()),
-		Fields:      make([]*jobj.Field, 0, t.NumField()),
 	}
 
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-
-		if !field.IsExported() {
-			continue
-		}
-
-		jobjField := createFieldFromStructField(field)
-		if jobjField != nil {
-			schema.Fields = append(schema.Fields, jobjField)
-		}
+	fields, err := structSchemaFields(t, o)
+	if err != nil {
+		return jobj.Schema{}, err
 	}
+	schema.Fields = fields
 
 	if len(schema.Fields) == 0 {
 		return jobj.Schema{}, fmt.Errorf(
@@ -1,8 +1,17 @@
 package funcschema
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/mhpenta/jobj"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/netip"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 type UserInfo struct {
@@ -19,9 +28,11 @@ func TestSchemaFromStruct(t *testing.T) {
 
 	expected := `{
   "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "UserInfo",
   "definitions": {
     "UserInfo": {
       "additionalProperties": false,
+      "description": "Schema for UserInfo",
       "properties": {
         "Active": {
           "description": "Whether the user is active",
@@ -53,3 +64,1125 @@ func TestSchemaFromStruct(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "expected struct type")
 }
+
+type sharedAddress struct {
+	Street string `json:"street" desc:"Street and number" required:"true"`
+	City   string `json:"city" desc:"City" required:"true"`
+}
+
+type shipment struct {
+	Billing  sharedAddress   `json:"billing" desc:"Billing address" required:"true"`
+	Shipping *sharedAddress  `json:"shipping" desc:"Shipping address"`
+	Stops    []sharedAddress `json:"stops" desc:"Intermediate stops"`
+	Origin   struct {
+		Lat float64 `json:"lat" desc:"Latitude"`
+	} `json:"origin" desc:"Origin"`
+}
+
+func TestSchemaFromStructSharesRepeatedStructs(t *testing.T) {
+	schema, err := SchemaFromStruct[shipment]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+
+	definitions := properties["definitions"].(map[string]interface{})
+	assert.Len(t, definitions, 1)
+	assert.Contains(t, definitions["sharedAddress"].(map[string]interface{})["properties"], "street")
+
+	fields := properties["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/definitions/sharedAddress", "description": "Billing address"}, fields["billing"])
+	assert.Equal(t, "#/definitions/sharedAddress", fields["shipping"].(map[string]interface{})["$ref"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/definitions/sharedAddress"}, fields["stops"].(map[string]interface{})["items"])
+	assert.Contains(t, fields["origin"], "properties")
+
+	assert.Contains(t, schema.GetSchemaString(), `"$ref": "#/definitions/sharedAddress"`)
+}
+
+type sharedNote struct {
+	Text string `json:"text,omitempty" desc:"Note text"`
+}
+
+func TestGetPropertiesMapOmitsEmptyRequired(t *testing.T) {
+	type notes struct {
+		First  sharedNote `json:"first,omitempty" desc:"First note"`
+		Second sharedNote `json:"second,omitempty" desc:"Second note"`
+	}
+	schema, err := SchemaFromStruct[notes]()
+	require.NoError(t, err)
+
+	properties := GetPropertiesMap(schema)
+	assert.NotContains(t, properties, "required")
+	definitions := properties["definitions"].(map[string]interface{})
+	assert.NotContains(t, definitions["sharedNote"], "required")
+}
+
+func TestDefaultTag(t *testing.T) {
+	type options struct {
+		Query   string   `json:"query" desc:"Search query" required:"true"`
+		Limit   int      `json:"limit" desc:"Maximum results" default:"10"`
+		Score   float64  `json:"score" default:"0.5"`
+		Exact   *bool    `json:"exact" default:"true"`
+		Mode    string   `json:"mode" default:"fast"`
+		Sources []string `json:"sources" default:"[\"web\", \"news\"]"`
+		Broken  int      `json:"broken" default:"many"`
+	}
+
+	schema, err := SchemaFromStruct[options]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	assert.Equal(t, float64(10), fields["limit"].(map[string]interface{})["default"])
+	assert.Equal(t, 0.5, fields["score"].(map[string]interface{})["default"])
+	assert.Equal(t, true, fields["exact"].(map[string]interface{})["default"])
+	assert.Equal(t, "fast", fields["mode"].(map[string]interface{})["default"])
+	assert.Equal(t, []interface{}{"web", "news"}, fields["sources"].(map[string]interface{})["default"])
+	assert.NotContains(t, fields["broken"], "default")
+	assert.NotContains(t, fields["query"], "default")
+}
+
+func TestExampleTags(t *testing.T) {
+	type params struct {
+		Ticker  string   `json:"ticker" desc:"Stock ticker" example:"AAPL"`
+		Period  string   `json:"period" desc:"Reporting period" examples:"Q1 2024|FY 2023"`
+		Limit   int      `json:"limit" desc:"Maximum results" examples:"5|10|oops"`
+		Filings []string `json:"filings" desc:"Filing types" example:"[\"10-K\", \"10-Q\"]"`
+	}
+
+	schema, err := SchemaFromStruct[params]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	assert.Equal(t, []interface{}{"AAPL"}, fields["ticker"].(map[string]interface{})["examples"])
+	assert.Equal(t, []interface{}{"Q1 2024", "FY 2023"}, fields["period"].(map[string]interface{})["examples"])
+	assert.Equal(t, []interface{}{float64(5), float64(10)}, fields["limit"].(map[string]interface{})["examples"])
+	assert.Equal(t, []interface{}{[]interface{}{"10-K", "10-Q"}}, fields["filings"].(map[string]interface{})["examples"])
+}
+
+func TestFormatTag(t *testing.T) {
+	type contact struct {
+		Email    string  `json:"email" desc:"Email address" format:"email"`
+		Homepage *string `json:"homepage" desc:"Homepage" format:"uri"`
+		Age      int     `json:"age" desc:"Age in years" format:"email"`
+		Name     string  `json:"name" desc:"Full name"`
+	}
+
+	schema, err := SchemaFromStruct[contact]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	assert.Equal(t, "email", fields["email"].(map[string]interface{})["format"])
+	assert.Equal(t, "uri", fields["homepage"].(map[string]interface{})["format"])
+	assert.NotContains(t, fields["age"], "format")
+	assert.NotContains(t, fields["name"], "format")
+}
+
+func TestFieldMap(t *testing.T) {
+	type address struct {
+		City    string `json:"city" desc:"City"`
+		Country string `desc:"Country"`
+	}
+	type officer struct {
+		Name  string `json:"name" desc:"Officer name"`
+		Title string `json:"title,omitempty" desc:"Job title"`
+	}
+	type filing struct {
+		Company  string              `json:"company_name" desc:"Company name" required:"true"`
+		Address  *address            `json:"address" desc:"Registered address"`
+		Officers []officer           `json:"officers" desc:"Company officers"`
+		Segments map[string]*address `json:"segments" desc:"Segment locations"`
+		Internal string              `json:"-"`
+	}
+
+	names, err := NewFieldMap[filing]()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/company_name",
+		"/address", "/address/city", "/address/Country",
+		"/officers", "/officers/*/name", "/officers/*/title",
+		"/segments", "/segments/*/city", "/segments/*/Country",
+	}, names.Properties())
+
+	field, ok := names.Field("/company_name")
+	assert.True(t, ok)
+	assert.Equal(t, "Company", field)
+
+	field, ok = names.Field("/officers/*/title")
+	assert.True(t, ok)
+	assert.Equal(t, "Officers.Title", field)
+
+	field, ok = names.Field("/officers/3/name")
+	assert.True(t, ok)
+	assert.Equal(t, "Officers.Name", field)
+
+	field, ok = names.Field("/segments/retail/Country")
+	assert.True(t, ok)
+	assert.Equal(t, "Segments.Country", field)
+
+	_, ok = names.Field("/internal")
+	assert.False(t, ok)
+
+	property, ok := names.Property("Address.City")
+	assert.True(t, ok)
+	assert.Equal(t, "/address/city", property)
+
+	property, ok = names.Property("Officers.Name")
+	assert.True(t, ok)
+	assert.Equal(t, "/officers/*/name", property)
+
+	_, ok = names.Property("Internal")
+	assert.False(t, ok)
+}
+
+func TestInterfaceSlices(t *testing.T) {
+	type note struct {
+		Text string `json:"text" desc:"Note text" required:"true"`
+	}
+	require.NoError(t, RegisterItemType("sheet_note", note{}))
+
+	type row struct {
+		Values []any         `json:"values" desc:"Raw values"`
+		Cells  []interface{} `json:"cells" desc:"Cell values" items:"number|string|sheet_note"`
+		Labels []any         `json:"labels" desc:"Labels" items:"string"`
+	}
+
+	schema, err := SchemaFromStruct[row]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	assert.Equal(t, map[string]interface{}{"description": ""}, fields["values"].(map[string]interface{})["items"])
+	assert.Equal(t, "string", fields["labels"].(map[string]interface{})["items"].(map[string]interface{})["type"])
+
+	variants := fields["cells"].(map[string]interface{})["items"].(map[string]interface{})["anyOf"].([]interface{})
+	require.Len(t, variants, 3)
+	assert.Equal(t, "number", variants[0].(map[string]interface{})["type"])
+	assert.Equal(t, "string", variants[1].(map[string]interface{})["type"])
+	assert.Equal(t, "object", variants[2].(map[string]interface{})["type"])
+	assert.Contains(t, variants[2].(map[string]interface{})["properties"], "text")
+
+	errs, err := schema.ValidateJSON([]byte(`{"values":[1,"a",{}],"cells":[1,"b",{"text":"c"}],"labels":["d"]}`))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = schema.ValidateJSON([]byte(`{"cells":[true],"labels":[1]}`))
+	require.NoError(t, err)
+	assert.Len(t, errs, 2)
+}
+
+func TestCardinalityTags(t *testing.T) {
+	type summary struct {
+		Bullets []string `json:"bullets" desc:"Key points" minItems:"3" maxItems:"5" uniqueItems:"true"`
+		Tags    []string `json:"tags" desc:"Tags" maxItems:"many"`
+		Title   string   `json:"title" desc:"Title" minItems:"1"`
+	}
+
+	schema, err := SchemaFromStruct[summary]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	bullets := fields["bullets"].(map[string]interface{})
+	assert.Equal(t, float64(3), bullets["minItems"])
+	assert.Equal(t, float64(5), bullets["maxItems"])
+	assert.Equal(t, true, bullets["uniqueItems"])
+	assert.NotContains(t, fields["tags"], "maxItems")
+	assert.NotContains(t, fields["title"], "minItems")
+
+	errs, err := schema.ValidateJSON([]byte(`{"bullets":["a"]}`))
+	require.NoError(t, err)
+	assert.Len(t, errs, 1)
+}
+
+func TestItemsDescTag(t *testing.T) {
+	type term struct {
+		Start int `json:"start" desc:"Start year" required:"true"`
+		End   int `json:"end" desc:"End year"`
+	}
+	type officer struct {
+		Name  string `json:"name" desc:"Full name" required:"true"`
+		Terms []term `json:"terms" desc:"Terms served" itemsDesc:"One term of office"`
+	}
+
+	type params struct {
+		Company string `json:"company" desc:"Company name" required:"true"`
+	}
+	listOfficers := func(ctx context.Context, p params) ([]officer, error) { return nil, nil }
+
+	_, schema, err := NewSchemasFromFunc(listOfficers)
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+
+	items := properties["items"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"name"}, items["required"])
+	terms := items["properties"].(map[string]interface{})["terms"].(map[string]interface{})
+	assert.Equal(t, "Terms served", terms["description"])
+	termItems := terms["items"].(map[string]interface{})
+	assert.Equal(t, "One term of office", termItems["description"])
+	assert.Equal(t, []interface{}{"start"}, termItems["required"])
+}
+
+func TestValidateTags(t *testing.T) {
+	type search struct {
+		Query  string   `json:"query" desc:"Search query" validate:"required,min=1,max=200"`
+		Sort   string   `json:"sort" desc:"Sort order" validate:"omitempty,oneof=relevance date 'most recent'"`
+		Limit  int      `json:"limit" desc:"Maximum results" validate:"gte=1,lte=50"`
+		Rating float64  `json:"rating" desc:"Minimum rating" validate:"min=0.5"`
+		Email  string   `json:"email" desc:"Contact" validate:"email" format:"idn-email"`
+		Tags   []string `json:"tags" desc:"Tags" validate:"len=3,dive,max=10"`
+		Level  int      `json:"level" desc:"Level" validate:"oneof=1 2 3"`
+	}
+
+	schema, err := SchemaFromStruct[search](WithValidateTags())
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	assert.Equal(t, []interface{}{"query"}, properties["required"])
+	query := fields["query"].(map[string]interface{})
+	assert.Equal(t, float64(1), query["minLength"])
+	assert.Equal(t, float64(200), query["maxLength"])
+
+	sortOptions := fields["sort"].(map[string]interface{})["anyOf"].([]interface{})
+	require.Len(t, sortOptions, 3)
+	assert.Equal(t, "most recent", sortOptions[2].(map[string]interface{})["const"])
+
+	limit := fields["limit"].(map[string]interface{})
+	assert.Equal(t, float64(1), limit["minimum"])
+	assert.Equal(t, float64(50), limit["maximum"])
+	assert.Equal(t, 0.5, fields["rating"].(map[string]interface{})["minimum"])
+	assert.Equal(t, "idn-email", fields["email"].(map[string]interface{})["format"])
+
+	tags := fields["tags"].(map[string]interface{})
+	assert.Equal(t, float64(3), tags["minItems"])
+	assert.Equal(t, float64(3), tags["maxItems"])
+	assert.NotContains(t, tags["items"], "maxLength")
+
+	errs, err := schema.ValidateJSON([]byte(`{"query":"","sort":"oldest","limit":60,"level":4}`))
+	require.NoError(t, err)
+	assert.Len(t, errs, 4)
+
+	handler := func(ctx context.Context, input search) (string, error) { return "", nil }
+	schemas, err := SchemasFor(handler, WithValidateTags())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"query"}, schemas.Input.RequiredFields())
+
+	// The option applies to its own call only, also for the schemas cached by SchemasFor
+	plain, err := SchemaFromStruct[search]()
+	require.NoError(t, err)
+	assert.Empty(t, plain.RequiredFields())
+	schemas, err = SchemasFor(handler)
+	require.NoError(t, err)
+	assert.Empty(t, schemas.Input.RequiredFields())
+}
+
+func TestJSONSchemaTags(t *testing.T) {
+	type search struct {
+		Query string   `json:"query" jsonschema:"title=Query,description=Search query\\, in any language,required,minLength=1"`
+		Sort  string   `json:"sort" jsonschema:"enum=relevance,enum=date,default=relevance" desc:"Sort order"`
+		Limit int      `json:"limit" jsonschema:"description=Maximum results,minimum=1,maximum=50,example=10"`
+		Tags  []string `json:"tags" jsonschema:"description=Tags,maxItems=5,uniqueItems=true"`
+	}
+
+	schema, err := SchemaFromStruct[search]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	assert.Equal(t, []interface{}{"query"}, properties["required"])
+	query := fields["query"].(map[string]interface{})
+	assert.Equal(t, "Query", query["title"])
+	assert.Equal(t, "Search query, in any language", query["description"])
+	assert.Equal(t, float64(1), query["minLength"])
+
+	sort := fields["sort"].(map[string]interface{})
+	assert.Equal(t, "Sort order", sort["description"])
+	assert.Equal(t, "relevance", sort["default"])
+	require.Len(t, sort["anyOf"], 2)
+
+	limit := fields["limit"].(map[string]interface{})
+	assert.Equal(t, float64(1), limit["minimum"])
+	assert.Equal(t, float64(50), limit["maximum"])
+	assert.Equal(t, []interface{}{float64(10)}, limit["examples"])
+
+	tags := fields["tags"].(map[string]interface{})
+	assert.Equal(t, float64(5), tags["maxItems"])
+	assert.Equal(t, true, tags["uniqueItems"])
+
+	// The title is part of the root output too
+	assert.Contains(t, schema.GetSchemaString(), `"title": "Query"`)
+}
+
+func TestStrictTypes(t *testing.T) {
+	type filter struct {
+		Field string        `json:"field" desc:"Field to filter"`
+		Match func() bool   `json:"match" desc:"Matcher"`
+		Ch    chan struct{} `json:"-"`
+	}
+	type search struct {
+		Query    string             `json:"query" desc:"Search query"`
+		Filters  []filter           `json:"filters" desc:"Filters"`
+		Weights  []complex128       `json:"weights" desc:"Weights"`
+		ByName   map[string]*filter `json:"by_name" desc:"Filters by name"`
+		Callback func(string)       `json:"callback" desc:"Called per result"`
+		internal func()
+	}
+
+	// Off by default: unsupported fields are left out
+	schema, err := SchemaFromStruct[search]()
+	require.NoError(t, err)
+	assert.Len(t, schema.Fields, 3)
+
+	_, err = SchemaFromStruct[search](WithStrict())
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+	assert.EqualError(t, err, "unsupported field type: search.Filters.Match, search.Weights, search.ByName.Match, search.Callback")
+
+	// The option applies to its own call and is cached apart from the default
+	schema, err = SchemaFromStruct[search]()
+	require.NoError(t, err)
+	assert.Len(t, schema.Fields, 3)
+
+	handler := func(ctx context.Context, input filter) ([]filter, error) { return nil, nil }
+	_, _, err = NewSchemasFromFunc(handler, WithStrict())
+	assert.EqualError(t, err, "unsupported field type: filter.Match")
+	_, err = SchemasFor(handler, WithStrict())
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+	_, err = SchemasFor(handler)
+	assert.NoError(t, err)
+
+	type supported struct {
+		Query  string     `json:"query" desc:"Search query"`
+		When   time.Time  `json:"when" desc:"Search date"`
+		Values []any      `json:"values" desc:"Values"`
+		Parent *supported `json:"parent" desc:"Parent search"`
+	}
+	_, err = SchemaFromStruct[supported](WithStrict())
+	assert.NoError(t, err)
+}
+
+type mapperDecimal struct {
+	units int64
+	scale int32
+}
+
+type mapperUUID [16]byte
+
+type mapperStatus int
+
+func TestTypeMappers(t *testing.T) {
+	require.NoError(t, RegisterTypeMapper(reflect.TypeOf(mapperDecimal{}), func(field reflect.StructField) *jobj.Field {
+		return jobj.Text("").Format("decimal")
+	}))
+	require.NoError(t, RegisterTypeMapper(reflect.TypeOf(mapperUUID{}), func(field reflect.StructField) *jobj.Field {
+		return jobj.Text("").Format("uuid")
+	}))
+	require.NoError(t, RegisterTypeMapper(reflect.TypeOf(mapperStatus(0)), func(field reflect.StructField) *jobj.Field {
+		return jobj.AnyOf("", []jobj.ConstDescription{{Const: "open"}, {Const: "closed"}})
+	}))
+	assert.Error(t, RegisterTypeMapper(nil, func(field reflect.StructField) *jobj.Field { return nil }))
+
+	type order struct {
+		ID       mapperUUID               `json:"id" desc:"Order ID" required:"true"`
+		Total    *mapperDecimal           `json:"total" desc:"Order total"`
+		Status   mapperStatus             `json:"status" desc:"Order status"`
+		Lines    []mapperDecimal          `json:"lines" desc:"Line amounts" itemsDesc:"An amount"`
+		Balances map[string]mapperDecimal `json:"balances" desc:"Balances by account"`
+	}
+
+	schema, err := SchemaFromStruct[order]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	assert.Equal(t, []interface{}{"id"}, properties["required"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "uuid", "description": "Order ID"}, fields["id"])
+	assert.Equal(t, "decimal", fields["total"].(map[string]interface{})["format"])
+	assert.Len(t, fields["status"].(map[string]interface{})["anyOf"], 2)
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "decimal", "description": "An amount"},
+		fields["lines"].(map[string]interface{})["items"])
+	assert.Equal(t, "string", fields["balances"].(map[string]interface{})["additionalProperties"].(map[string]interface{})["type"])
+
+	errs, err := schema.ValidateJSON([]byte(`{"id":"6b2c0a4e-0c7e-4c43-9d35-0b8b7a1f7a7e","total":"12.50","status":"open","lines":["1.25"],"balances":{"main":"3"}}`))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	// Mapped types are mapped at the root of non-struct results too
+	handler := func(ctx context.Context, input order) ([]mapperDecimal, error) { return nil, nil }
+	_, output, err := NewSchemasFromFunc(handler)
+	require.NoError(t, err)
+	assert.Equal(t, "decimal", output.RootField.ArrayItemField.ValueFormat)
+}
+
+type providerMoney struct {
+	cents int64
+}
+
+func (providerMoney) JobjSchema() *jobj.Field {
+	return jobj.Text("").Format("decimal").Desc("Amount in the account currency")
+}
+
+type providerCode struct {
+	code string
+}
+
+func (*providerCode) JobjSchema() *jobj.Field {
+	return jobj.AnyOf("", []jobj.ConstDescription{{Const: "a"}, {Const: "b"}})
+}
+
+func TestSchemaProvider(t *testing.T) {
+	type invoice struct {
+		Total   providerMoney            `json:"total" required:"true"`
+		Tax     *providerMoney           `json:"tax" desc:"Tax due"`
+		Code    providerCode             `json:"code" desc:"Invoice code"`
+		Lines   []providerMoney          `json:"lines" desc:"Line amounts"`
+		ByLabel map[string]providerMoney `json:"by_label" desc:"Amounts by label"`
+	}
+
+	schema, err := SchemaFromStruct[invoice]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	// The provided description stands unless the field has a desc tag
+	total := fields["total"].(map[string]interface{})
+	assert.Equal(t, "decimal", total["format"])
+	assert.Equal(t, "Amount in the account currency", total["description"])
+	assert.Equal(t, "Tax due", fields["tax"].(map[string]interface{})["description"])
+	assert.Len(t, fields["code"].(map[string]interface{})["anyOf"], 2)
+	assert.Equal(t, "decimal", fields["lines"].(map[string]interface{})["items"].(map[string]interface{})["format"])
+	assert.Equal(t, "string", fields["by_label"].(map[string]interface{})["additionalProperties"].(map[string]interface{})["type"])
+	assert.Equal(t, []string{"total"}, schema.RequiredFields())
+
+	// Registered mappers take precedence over the type's own schema
+	require.NoError(t, RegisterTypeMapper(reflect.TypeOf(providerCode{}), func(field reflect.StructField) *jobj.Field {
+		return jobj.Text("")
+	}))
+	defer typeMappers.Delete(reflect.TypeOf(providerCode{}))
+	schema, err = SchemaFromStruct[invoice]()
+	require.NoError(t, err)
+	assert.Equal(t, jobj.TypeString, schema.Fields[2].ValueType)
+	assert.Nil(t, schema.Fields[2].ValueAnyOf)
+}
+
+type textLevel int
+
+func (l textLevel) MarshalText() ([]byte, error) {
+	return []byte([]string{"low", "high"}[l]), nil
+}
+
+func TestTextMarshalerFields(t *testing.T) {
+	type server struct {
+		Addr    netip.Addr            `json:"addr" desc:"Server address" required:"true"`
+		Prefix  *netip.Prefix         `json:"prefix" desc:"Subnet"`
+		Level   textLevel             `json:"level" desc:"Log level"`
+		Peers   []netip.Addr          `json:"peers" desc:"Peer addresses"`
+		ByName  map[string]netip.Addr `json:"by_name" desc:"Addresses by host name"`
+		Started time.Time             `json:"started" desc:"Start time"`
+	}
+
+	schema, err := SchemaFromStruct[server]()
+	require.NoError(t, err)
+
+	types := make(map[string]jobj.DataType)
+	for _, field := range schema.Fields {
+		types[field.ValueName] = field.ValueType
+	}
+	assert.Equal(t, jobj.TypeString, types["addr"])
+	assert.Equal(t, jobj.TypeString, types["prefix"])
+	assert.Equal(t, jobj.TypeString, types["level"])
+	assert.Equal(t, jobj.TypeString, schema.Fields[3].ArrayItemField.ValueType)
+	assert.Equal(t, jobj.TypeString, schema.Fields[4].AdditionalPropertiesType)
+	assert.Equal(t, jobj.Date("started").ValueType, types["started"])
+	assert.Equal(t, []string{"addr"}, schema.RequiredFields())
+
+	// The schema accepts what encoding/json produces for the types
+	prefix := netip.MustParsePrefix("10.0.0.0/8")
+	encoded, err := json.Marshal(server{
+		Addr:   netip.MustParseAddr("10.0.0.1"),
+		Prefix: &prefix,
+		Level:  1,
+		Peers:  []netip.Addr{netip.MustParseAddr("::1")},
+		ByName: map[string]netip.Addr{"db": netip.MustParseAddr("10.0.0.2")},
+	})
+	require.NoError(t, err)
+	errs, err := schema.ValidateJSON(encoded)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+type paymentMethod interface {
+	isPaymentMethod()
+}
+
+type paymentCard struct {
+	Number string `json:"number" desc:"Card number" required:"true"`
+}
+
+func (paymentCard) isPaymentMethod() {}
+
+type paymentTransfer struct {
+	IBAN string `json:"iban" desc:"Account IBAN" required:"true"`
+}
+
+func (*paymentTransfer) isPaymentMethod() {}
+
+func TestImplementations(t *testing.T) {
+	iface := reflect.TypeOf((*paymentMethod)(nil)).Elem()
+	assert.Error(t, RegisterImplementations(reflect.TypeOf(paymentCard{}), "kind", map[string]interface{}{"card": paymentCard{}}))
+	assert.Error(t, RegisterImplementations(iface, "kind", map[string]interface{}{"transfer": paymentTransfer{}}))
+	require.NoError(t, RegisterImplementations(iface, "kind", map[string]interface{}{
+		"transfer": &paymentTransfer{},
+		"card":     paymentCard{},
+	}))
+
+	type payment struct {
+		Amount   float64         `json:"amount" desc:"Amount" required:"true"`
+		Method   paymentMethod   `json:"method" desc:"How to pay" required:"true"`
+		Fallback []paymentMethod `json:"fallback" desc:"Methods to try next"`
+	}
+
+	schema, err := SchemaFromStruct[payment]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	method := fields["method"].(map[string]interface{})
+	assert.Equal(t, "How to pay", method["description"])
+	assert.Equal(t, map[string]interface{}{"propertyName": "kind"}, method["discriminator"])
+	assert.NotContains(t, method, "anyOf")
+	variants := method["oneOf"].([]interface{})
+	require.Len(t, variants, 2)
+	card := variants[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"kind", "number"}, card["required"])
+	assert.Equal(t, "card", card["properties"].(map[string]interface{})["kind"].(map[string]interface{})["anyOf"].([]interface{})[0].(map[string]interface{})["const"])
+	assert.Contains(t, fields["fallback"].(map[string]interface{})["items"], "oneOf")
+
+	errs, err := schema.ValidateJSON([]byte(`{"amount":5,"method":{"kind":"transfer","iban":"DE89"},"fallback":[{"kind":"card","number":"4242"}]}`))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	errs, err = schema.ValidateJSON([]byte(`{"amount":5,"method":{"kind":"cash"}}`))
+	require.NoError(t, err)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, schema.GetSchemaString(), `"const": "transfer"`)
+	assert.Contains(t, schema.GetSchemaString(), `"propertyName": "kind"`)
+
+	// Gemini has no oneOf, so the variants reach it as anyOf
+	gemini, report, err := jobj.ProviderGemini.Apply(&schema)
+	require.NoError(t, err)
+	geminiMethod := gemini["properties"].(map[string]interface{})["method"].(map[string]interface{})
+	assert.Len(t, geminiMethod["anyOf"], 2)
+	assert.NotContains(t, geminiMethod, "oneOf")
+	assert.Contains(t, report.Losses, jobj.Loss{Pointer: "/properties/method", Keyword: "oneOf", Detail: "rewritten to anyOf"})
+}
+
+func TestArraysOfMaps(t *testing.T) {
+	type report struct {
+		Rows    []map[string]string      `json:"rows" desc:"Rows by column" required:"true"`
+		Counts  []map[string]int         `json:"counts" desc:"Counts by key"`
+		Records []map[string]paymentCard `json:"records" desc:"Cards by holder"`
+		Any     []map[string]interface{} `json:"any" desc:"Free-form rows"`
+		Groups  [][]map[string]float64   `json:"groups" desc:"Grouped scores"`
+		Skipped []map[string]func()      `json:"skipped" desc:"Unsupported values"`
+	}
+
+	schema, err := SchemaFromStruct[report]()
+	require.NoError(t, err)
+	assert.Len(t, schema.Fields, 5)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	rows := fields["rows"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, "object", rows["type"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, rows["additionalProperties"])
+	records := fields["records"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Contains(t, records["additionalProperties"].(map[string]interface{})["properties"], "number")
+
+	// The root schema describes the items too
+	encoded, err = json.Marshal(schema.FieldsJson()["rows"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"array","description":"Rows by column","items":{"type":"object","description":"","additionalProperties":{"type":"string"}}}`, string(encoded))
+
+	errs, err := schema.ValidateJSON([]byte(`{"rows":[{"a":"1"}],"counts":[{"x":1}],"records":[{"ann":{"number":"4242"}}],"groups":[[{"s":0.5}]]}`))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	errs, err = schema.ValidateJSON([]byte(`{"rows":[{"a":1}],"counts":[{"x":"one"}]}`))
+	require.NoError(t, err)
+	assert.Len(t, errs, 2)
+}
+
+func TestSlicesOfPointers(t *testing.T) {
+	type item struct {
+		Name string `json:"name" desc:"Item name" required:"true"`
+	}
+	type order struct {
+		Items   []*item            `json:"items" desc:"Ordered items" required:"true"`
+		Notes   []*string          `json:"notes" desc:"Notes"`
+		Counts  []*int             `json:"counts" desc:"Counts"`
+		Batches [][]*item          `json:"batches" desc:"Items by batch"`
+		Paid    []*time.Time       `json:"paid" desc:"Payment times"`
+		Tags    []*map[string]bool `json:"tags" desc:"Tag sets"`
+	}
+
+	schema, err := SchemaFromStruct[order]()
+	require.NoError(t, err)
+	require.Len(t, schema.Fields, 6)
+
+	items := schema.Fields[0]
+	assert.Equal(t, jobj.TypeArray, items.ValueType)
+	require.Len(t, items.SubFields, 1)
+	assert.Equal(t, "name", items.SubFields[0].ValueName)
+	assert.Equal(t, jobj.TypeString, schema.Fields[1].ArrayItemType)
+	assert.Equal(t, jobj.TypeInteger, schema.Fields[2].ArrayItemType)
+	assert.Equal(t, "name", schema.Fields[3].ArrayItemField.SubFields[0].ValueName)
+
+	// The same pointers are also handled in non-struct results
+	handler := func(ctx context.Context, input order) ([]*item, error) { return nil, nil }
+	_, output, err := NewSchemasFromFunc(handler)
+	require.NoError(t, err)
+	assert.Equal(t, "name", output.RootField.SubFields[0].ValueName)
+
+	note, paid := "gift", time.Now()
+	encoded, err := json.Marshal(order{
+		Items:   []*item{{Name: "pen"}},
+		Notes:   []*string{&note},
+		Counts:  []*int{new(int)},
+		Batches: [][]*item{{{Name: "ink"}}},
+		Paid:    []*time.Time{&paid},
+		Tags:    []*map[string]bool{{"urgent": true}},
+	})
+	require.NoError(t, err)
+	errs, err := schema.ValidateJSON(encoded)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestSlicesOfSlices(t *testing.T) {
+	type cell struct {
+		Value string `json:"value" desc:"Cell value" required:"true"`
+	}
+	type table struct {
+		Rows       [][]string     `json:"rows" desc:"Table rows" required:"true"`
+		Embeddings [][4]float32   `json:"embeddings" desc:"One embedding per row"`
+		Cube       [][][]int      `json:"cube" desc:"Counts by x, y and z"`
+		Cells      [][]*cell      `json:"cells" desc:"Cells by row"`
+		Grid       [2][3]int      `json:"grid" desc:"Fixed grid"`
+		Tagged     []string       `json:"tagged" desc:"Tags" minItems:"1"`
+		Limited    [3]string      `json:"limited" desc:"Exactly three, or fewer by tag" minItems:"1"`
+		Unused     [][]complex128 `json:"unused" desc:"Unsupported items"`
+	}
+
+	schema, err := SchemaFromStruct[table]()
+	require.NoError(t, err)
+	require.Len(t, schema.Fields, 7)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	rows := fields["rows"].(map[string]interface{})
+	rowItems := rows["items"].(map[string]interface{})
+	assert.Equal(t, "array", rowItems["type"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, rowItems["items"])
+	embedding := fields["embeddings"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, float64(4), embedding["minItems"])
+	assert.Equal(t, float64(4), embedding["maxItems"])
+	cube := fields["cube"].(map[string]interface{})["items"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, cube["items"])
+	cells := fields["cells"].(map[string]interface{})["items"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"value"}, cells["required"])
+	grid := fields["grid"].(map[string]interface{})
+	assert.Equal(t, float64(2), grid["maxItems"])
+	assert.Equal(t, float64(3), grid["items"].(map[string]interface{})["maxItems"])
+	assert.NotContains(t, fields["tagged"], "maxItems")
+	limited := fields["limited"].(map[string]interface{})
+	assert.Equal(t, float64(1), limited["minItems"])
+	assert.Equal(t, float64(3), limited["maxItems"])
+
+	errs, err := schema.ValidateJSON([]byte(`{"rows":[["a","b"],[]],"embeddings":[[0.1,0.2,0.3]],"grid":[[1,2,3]]}`))
+	require.NoError(t, err)
+	assert.Len(t, errs, 2)
+}
+
+type docCommentFilter struct {
+	// Field is the name of the field to filter on
+	Field string `json:"field"`
+	Value string `json:"value"` // Value the field must equal
+}
+
+type docCommentParams struct {
+	// Query is the full-text search query.
+	// It may span several lines.
+	Query string `json:"query" required:"true"`
+
+	// Limit is overridden by the desc tag
+	Limit int `json:"limit" desc:"Maximum number of results"`
+
+	// Filters narrow down the results
+	Filters []docCommentFilter `json:"filters"`
+
+	Cursor string `json:"cursor"`
+}
+
+func TestLoadDocComments(t *testing.T) {
+	require.NoError(t, LoadDocComments("."))
+	defer docComments.Range(func(key, value interface{}) bool {
+		docComments.Delete(key)
+		return true
+	})
+
+	schema, err := SchemaFromStruct[docCommentParams]()
+	require.NoError(t, err)
+	descriptions := make(map[string]string)
+	for _, field := range schema.Fields {
+		descriptions[field.ValueName] = field.ValueDescription
+	}
+	assert.Equal(t, "Query is the full-text search query. It may span several lines.", descriptions["query"])
+	assert.Equal(t, "Maximum number of results", descriptions["limit"])
+	assert.Equal(t, "Filters narrow down the results", descriptions["filters"])
+	assert.Equal(t, "", descriptions["cursor"])
+
+	filter := schema.Fields[2].SubFields
+	assert.Equal(t, "Field is the name of the field to filter on", filter[0].ValueDescription)
+	assert.Equal(t, "Value the field must equal", filter[1].ValueDescription)
+
+	assert.Error(t, LoadDocComments("./does-not-exist"))
+}
+
+type cacheParams struct {
+	Query string   `json:"query" desc:"Search query" validate:"max=200"`
+	Tags  []string `json:"tags" desc:"Tags to match"`
+	Page  struct {
+		Size int `json:"size" desc:"Results per page" default:"10"`
+	} `json:"page"`
+}
+
+func TestSchemaCache(t *testing.T) {
+	first, err := SchemaFromStruct[cacheParams]()
+	require.NoError(t, err)
+	_, cached := schemaCache.Load(schemaKey{t: reflect.TypeOf(cacheParams{})})
+	assert.True(t, cached)
+
+	// Each call gets its own copy of the cached fields
+	first.Fields[0].Desc("Changed")
+	first.Fields[2].SubFields[0].Required()
+	second, err := NewSchemaFromFunc(func(ctx context.Context, params cacheParams) (string, error) { return "", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "Search query", second.Fields[0].ValueDescription)
+	assert.False(t, second.Fields[2].SubFields[0].ValueRequired)
+	assert.NotSame(t, first.Fields[1], second.Fields[1])
+
+	// Options that change generation are cached apart
+	validated, err := SchemaFromStruct[cacheParams](WithValidateTags())
+	require.NoError(t, err)
+	require.NotNil(t, validated.Fields[0].ValueMaxLength)
+	assert.Equal(t, 200, *validated.Fields[0].ValueMaxLength)
+	_, cached = schemaCache.Load(schemaKey{t: reflect.TypeOf(cacheParams{}), options: options{validateTags: true}})
+	assert.True(t, cached)
+	plain, err := SchemaFromStruct[cacheParams]()
+	require.NoError(t, err)
+	assert.Nil(t, plain.Fields[0].ValueMaxLength)
+
+	uncached, err := SchemaFromStruct[cacheParams](WithValidateTags(), WithoutSchemaCache())
+	require.NoError(t, err)
+	assert.Equal(t, validated.GetSchemaString(), uncached.GetSchemaString())
+	_, cached = schemaCache.Load(schemaKey{t: reflect.TypeOf(cacheParams{}), options: options{validateTags: true, noCache: true}})
+	assert.False(t, cached)
+}
+
+func TestInferredRequired(t *testing.T) {
+	type page struct {
+		Size   int `json:"size" desc:"Results per page"`
+		Offset int `json:"offset,omitempty" desc:"Results to skip"`
+	}
+	type search struct {
+		Query  string   `json:"query" desc:"Search query"`
+		Limit  *int     `json:"limit" desc:"Maximum results"`
+		Sort   string   `json:"sort,omitempty" desc:"Sort order"`
+		Tags   []string `json:"tags" desc:"Tags" required:"false"`
+		Cursor *string  `json:"cursor" desc:"Cursor" required:"true"`
+		Page   page     `json:"page" desc:"Paging"`
+	}
+
+	schema, err := SchemaFromStruct[search]()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cursor"}, schema.RequiredFields())
+
+	schema, err = SchemaFromStruct[search](WithInferredRequired())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"query", "cursor", "page"}, schema.RequiredFields())
+	assert.True(t, schema.Fields[5].SubFields[0].ValueRequired)
+	assert.False(t, schema.Fields[5].SubFields[1].ValueRequired)
+
+	// The option applies to its own call only
+	schema, err = SchemaFromStruct[search]()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cursor"}, schema.RequiredFields())
+	assert.False(t, schema.Fields[5].SubFields[0].ValueRequired)
+}
+
+func TestOmitemptyOptional(t *testing.T) {
+	type search struct {
+		Query  string  `json:"query" desc:"Search query" required:"true"`
+		Sort   string  `json:"sort,omitempty" desc:"Sort order" required:"true"`
+		Limit  int     `json:"limit,omitempty" desc:"Maximum results" validate:"required"`
+		Cursor *string `json:"cursor" desc:"Cursor"`
+	}
+
+	schema, err := SchemaFromStruct[search]()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"query", "sort"}, schema.RequiredFields())
+
+	schema, err = SchemaFromStruct[search](WithOmitemptyOptional(), WithInferredRequired(), WithValidateTags())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"query"}, schema.RequiredFields())
+
+	schema, err = SchemaFromStruct[search](WithInferredRequired(), WithValidateTags())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"query", "sort", "limit"}, schema.RequiredFields())
+}
+
+func TestTitleTag(t *testing.T) {
+	type event struct {
+		Name  string    `json:"name" title:"Event name" desc:"Shown on the calendar" required:"true"`
+		Start time.Time `json:"start" title:"Starts at" jsonschema:"title=Start"`
+		Notes string    `json:"notes" desc:"Free-form notes"`
+	}
+
+	schema, err := SchemaFromStruct[event]()
+	require.NoError(t, err)
+	assert.Equal(t, "Event name", schema.Fields[0].ValueTitle)
+	assert.Equal(t, "Shown on the calendar", schema.Fields[0].ValueDescription)
+	assert.Equal(t, "Starts at", schema.Fields[1].ValueTitle)
+	assert.Equal(t, "", schema.Fields[2].ValueTitle)
+
+	properties := GetPropertiesMap(schema)["properties"].(map[string]interface{})
+	assert.Equal(t, "Event name", properties["name"].(map[string]interface{})["title"])
+	assert.NotContains(t, properties["notes"], "title")
+}
+
+func TestDeprecatedTag(t *testing.T) {
+	type search struct {
+		Query  string `json:"query" desc:"Search query" required:"true"`
+		Filter string `json:"filter" desc:"Old filter syntax" deprecated:"true"`
+		Sort   string `json:"sort" desc:"Sort order" deprecated:"false"`
+	}
+
+	schema, err := SchemaFromStruct[search]()
+	require.NoError(t, err)
+	assert.False(t, schema.Fields[0].ValueDeprecated)
+	assert.True(t, schema.Fields[1].ValueDeprecated)
+	assert.False(t, schema.Fields[2].ValueDeprecated)
+	assert.Contains(t, schema.ToPromptText(), "deprecated")
+
+	// Legacy parameters are marked in the generated JSON Schema
+	schema, err = NewSchemaFromFunc(func(ctx context.Context, params search) (string, error) { return "", nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(schema.GetSchemaString(), `"deprecated": true`))
+	properties := GetPropertiesMap(schema)["properties"].(map[string]interface{})
+	assert.Equal(t, true, properties["filter"].(map[string]interface{})["deprecated"])
+	assert.NotContains(t, properties["sort"], "deprecated")
+
+	// Providers without the keyword do not receive it
+	declaration, err := ToGeminiFunctionDeclaration("search", "Search", schema)
+	require.NoError(t, err)
+	assert.NotContains(t, declaration["parameters"].(map[string]interface{})["properties"].(map[string]interface{})["filter"], "deprecated")
+}
+
+func TestNullablePointers(t *testing.T) {
+	type search struct {
+		Query  string  `json:"query" desc:"Search query" required:"true"`
+		Limit  *int    `json:"limit" desc:"Maximum results"`
+		Cursor *string `json:"cursor" desc:"Cursor" required:"true"`
+	}
+
+	schema, err := SchemaFromStruct[search]()
+	require.NoError(t, err)
+	assert.False(t, schema.Fields[1].ValueNullable)
+
+	schema, err = SchemaFromStruct[search](WithNullablePointers())
+	require.NoError(t, err)
+	assert.False(t, schema.Fields[0].ValueNullable)
+	assert.True(t, schema.Fields[1].ValueNullable)
+	assert.True(t, schema.Fields[2].ValueNullable)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema)["properties"])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	assert.Equal(t, []interface{}{"integer", "null"}, properties["limit"].(map[string]interface{})["type"])
+	assert.Equal(t, "string", properties["query"].(map[string]interface{})["type"])
+
+	errs, err := schema.ValidateJSON([]byte(`{"query":"go","limit":null,"cursor":null}`))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	// Gemini marks nullable types with nullable instead
+	declaration, err := ToGeminiFunctionDeclaration("search", "Search the catalog", schema)
+	require.NoError(t, err)
+	limit := declaration["parameters"].(map[string]interface{})["properties"].(map[string]interface{})["limit"].(map[string]interface{})
+	assert.Equal(t, "INTEGER", limit["type"])
+	assert.Equal(t, true, limit["nullable"])
+}
+
+func TestFieldNamer(t *testing.T) {
+	for name, expected := range map[string]string{
+		"NoJSONTag":  "no_json_tag",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"Page2Size":  "page2_size",
+		"query":      "query",
+		"A":          "a",
+	} {
+		assert.Equal(t, expected, SnakeCase(name), name)
+	}
+
+	type filter struct {
+		FieldName string
+	}
+	type search struct {
+		Query      string `json:"query" desc:"Search query"`
+		MaxResults int    `desc:"Maximum results"`
+		SortOrder  string `json:",omitempty" desc:"Sort order"`
+		Filters    []filter
+		Skipped    string `json:"-"`
+	}
+
+	schema, err := SchemaFromStruct[search]()
+	require.NoError(t, err)
+	assert.Equal(t, "MaxResults", schema.Fields[1].ValueName)
+	assert.Equal(t, "SortOrder", schema.Fields[2].ValueName)
+
+	schema, err = SchemaFromStruct[search](WithSnakeCaseNames())
+	require.NoError(t, err)
+	require.Len(t, schema.Fields, 4)
+	assert.Equal(t, "query", schema.Fields[0].ValueName)
+	assert.Equal(t, "max_results", schema.Fields[1].ValueName)
+	assert.Equal(t, "sort_order", schema.Fields[2].ValueName)
+	assert.Equal(t, "filters", schema.Fields[3].ValueName)
+	assert.Equal(t, "field_name", schema.Fields[3].SubFields[0].ValueName)
+
+	names, err := NewFieldMap[search](WithSnakeCaseNames())
+	require.NoError(t, err)
+	field, ok := names.Field("/filters/*/field_name")
+	assert.True(t, ok)
+	assert.Equal(t, "Filters.FieldName", field)
+
+	// The option applies to its own call only
+	schema, err = SchemaFromStruct[search]()
+	require.NoError(t, err)
+	assert.Equal(t, "MaxResults", schema.Fields[1].ValueName)
+}
+
+func TestUnsignedMinimum(t *testing.T) {
+	type page struct {
+		Offset uint   `json:"offset"`
+		Limit  *uint8 `json:"limit"`
+		Delta  int    `json:"delta"`
+	}
+
+	schema, err := SchemaFromStruct[page]()
+	require.NoError(t, err)
+	require.NotNil(t, schema.Fields[0].ValueMinimum)
+	assert.Equal(t, 0.0, *schema.Fields[0].ValueMinimum)
+	require.NotNil(t, schema.Fields[1].ValueMinimum)
+	assert.Equal(t, 0.0, *schema.Fields[1].ValueMinimum)
+	assert.Nil(t, schema.Fields[2].ValueMinimum)
+
+	schema, err = NewSchemaFromFuncN(func(ctx context.Context, count uint64) (string, error) {
+		return "", nil
+	}, []string{"count"})
+	require.NoError(t, err)
+	require.NotNil(t, schema.Fields[0].ValueMinimum)
+	assert.Equal(t, 0.0, *schema.Fields[0].ValueMinimum)
+
+	errs, err := schema.ValidateJSON([]byte(`{"count":-1}`))
+	require.NoError(t, err)
+	assert.Len(t, errs, 1)
+}
+
+func TestUnsignedMinimumItemsAndValues(t *testing.T) {
+	type shards struct {
+		IDs    []uint            `json:"ids"`
+		Counts map[string]uint32 `json:"counts"`
+		Grid   [][]uint16        `json:"grid"`
+	}
+
+	schema, err := SchemaFromStruct[shards]()
+	require.NoError(t, err)
+
+	var properties map[string]interface{}
+	encoded, err := json.Marshal(GetPropertiesMap(schema))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &properties))
+	fields := properties["properties"].(map[string]interface{})
+
+	ids := fields["ids"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, "integer", ids["type"])
+	assert.Equal(t, float64(0), ids["minimum"])
+	counts := fields["counts"].(map[string]interface{})["additionalProperties"].(map[string]interface{})
+	assert.Equal(t, "integer", counts["type"])
+	assert.Equal(t, float64(0), counts["minimum"])
+	grid := fields["grid"].(map[string]interface{})["items"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, float64(0), grid["minimum"])
+	assert.Equal(t, 3, strings.Count(schema.GetSchemaString(), `"minimum": 0`))
+
+	errs, err := schema.ValidateJSON([]byte(`{"ids":[1,-1],"counts":{"a":-2},"grid":[[-3]]}`))
+	require.NoError(t, err)
+	assert.Len(t, errs, 3)
+}
@@ -2,10 +2,11 @@ package funcschema
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/mhpenta/jobj"
-	"log/slog"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -14,7 +15,7 @@ import (
 // at compile time.
 //
 // Type parameters:
-//   - T: The type of the second parameter (must be a struct type)
+//   - T: The type of the second parameter (a struct type, or any supported type to be wrapped)
 //   - R: The return type of the function (can be any type)
 //
 // The function accepts handlers with the signature:
@@ -22,9 +23,11 @@ import (
 //	func(context.Context, T) (R, error)
 //
 // Returns a Schema describing the structure of type T and any error encountered.
-// An error is returned if T is not a struct type or if T has no exported fields
-// of supported types.
-func NewSchemaFromFuncV2[T any, R any](function func(context.Context, T) (R, error)) (jobj.Schema, error) {
+// If T is not a struct, such as a string or a []string, the schema is an object with T as its
+// only, required property; see WithWrappedParamName. An error is returned if T is a struct
+// with no exported fields of supported types. Options such as WithStrict adjust the generation.
+func NewSchemaFromFuncV2[T any, R any](function func(context.Context, T) (R, error), opts ...Option) (jobj.Schema, error) {
+	o := newOptions(opts)
 	var zero T
 	paramType := reflect.TypeOf(zero)
 
@@ -33,27 +36,19 @@ func NewSchemaFromFuncV2[T any, R any](function func(context.Context, T) (R, err
 	}
 
 	if paramType.Kind() != reflect.Struct {
-		return jobj.Schema{}, fmt.Errorf("second parameter must be a struct")
+		return wrappedParamSchema(reflect.TypeOf((*T)(nil)).Elem(), o)
 	}
 
 	schema := jobj.Schema{
 		Name:        paramType.Name(),
 		Description: fmt.Sprintf("Schema for %s function parameters", paramType.Name()),
-		Fields:      make([]*jobj.Field, 0),
 	}
 
-	for i := 0; i < paramType.NumField(); i++ {
-		field := paramType.Field(i)
-
-		if !field.IsExported() {
-			continue
-		}
-
-		jobjField := createFieldFromStructField(field)
-		if jobjField != nil {
-			schema.Fields = append(schema.Fields, jobjField)
-		}
+	fields, err := structSchemaFields(paramType, o)
+	if err != nil {
+		return jobj.Schema{}, err
 	}
+	schema.Fields = fields
 
 	if len(schema.Fields) == 0 {
 		return jobj.Schema{}, fmt.Errorf(
@@ -69,59 +64,68 @@ func NewSchemaFromFuncV2[T any, R any](function func(context.Context, T) (R, err
 // Uses generics to enforce the function signature at compile time.
 //
 // Type parameters:
-//   - T: The type of the second parameter (must be a struct type)
-//   - R: The return type of the function (must be a struct type)
+//   - T: The type of the second parameter
+//   - R: The return type of the function
 //
 // The function accepts handlers with the signature:
 //
 //	func(context.Context, T) (R, error)
 //
 // Returns input and output Schemas describing the structure of types T and R respectively,
-// and any error encountered. An error is returned if T or R are not struct types, or if
-// they have no exported fields of supported types.
-func NewSchemasFromFunc[T any, R any](function func(context.Context, T) (R, error)) (input jobj.Schema, output jobj.Schema, err error) {
-	// Create input schema from T
+// and any error encountered. If T is not a struct, the input schema wraps it as in
+// NewSchemaFromFuncV2; see WithWrappedParamName. An error is returned if T or R are structs
+// with no exported fields of supported types. Options such as WithStrict adjust the
+// generation of both schemas.
+func NewSchemasFromFunc[T any, R any](function func(context.Context, T) (R, error), opts ...Option) (input jobj.Schema, output jobj.Schema, err error) {
 	// Use reflect.TypeOf with a typed nil to get the type even for pointer types
-	inputType := reflect.TypeOf((*T)(nil)).Elem()
+	return schemasFromTypes(reflect.TypeOf((*T)(nil)).Elem(), reflect.TypeOf((*R)(nil)).Elem(), newOptions(opts))
+}
 
-	if inputType.Kind() == reflect.Ptr {
-		inputType = inputType.Elem()
+// schemasFromTypes implements NewSchemasFromFunc for the handler's parameter and return types.
+func schemasFromTypes(inputType reflect.Type, outputType reflect.Type, o options) (input jobj.Schema, output jobj.Schema, err error) {
+	// Create input schema from T
+	if derefType(inputType).Kind() != reflect.Struct {
+		input, err = wrappedParamSchema(inputType, o)
+	} else {
+		input, err = inputSchema(derefType(inputType), o)
+	}
+	if err != nil {
+		return jobj.Schema{}, jobj.Schema{}, err
 	}
 
-	if inputType.Kind() != reflect.Struct {
-		return jobj.Schema{}, jobj.Schema{}, fmt.Errorf("input parameter type must be a struct")
+	// Create output schema from R
+	output, err = outputSchema(outputType, o)
+	if err != nil {
+		return jobj.Schema{}, jobj.Schema{}, err
 	}
+	return input, output, nil
+}
 
-	input = jobj.Schema{
+// inputSchema returns the input schema of a handler whose parameter is the struct inputType.
+func inputSchema(inputType reflect.Type, o options) (jobj.Schema, error) {
+	input := jobj.Schema{
 		Name:        inputType.Name(),
 		Description: fmt.Sprintf("Input schema for %s function parameters", inputType.Name()),
-		Fields:      make([]*jobj.Field, 0),
 	}
 
-	for i := 0; i < inputType.NumField(); i++ {
-		field := inputType.Field(i)
-
-		if !field.IsExported() {
-			continue
-		}
-
-		jobjField := createFieldFromStructField(field)
-		if jobjField != nil {
-			input.Fields = append(input.Fields, jobjField)
-		}
+	fields, err := structSchemaFields(inputType, o)
+	if err != nil {
+		return jobj.Schema{}, err
 	}
+	input.Fields = fields
 
 	if len(input.Fields) == 0 {
-		return jobj.Schema{}, jobj.Schema{}, fmt.Errorf(
+		return jobj.Schema{}, fmt.Errorf(
 			"no valid fields found in input struct %s. Ensure fields are exported and of supported types",
 			inputType.Name(),
 		)
 	}
 
-	// Create output schema from R
-	// Use reflect.TypeOf with a typed nil to get the type even for pointer types
-	outputType := reflect.TypeOf((*R)(nil)).Elem()
+	return input, nil
+}
 
+// outputSchema returns the output schema of a handler returning outputType.
+func outputSchema(outputType reflect.Type, o options) (jobj.Schema, error) {
 	if outputType.Kind() == reflect.Ptr {
 		outputType = outputType.Elem()
 	}
@@ -129,60 +133,57 @@ func NewSchemasFromFunc[T any, R any](function func(context.Context, T) (R, erro
 	// Handle different return types
 	if outputType.Kind() == reflect.Struct {
 		// Struct return type - use Fields (existing behavior)
-		output = jobj.Schema{
+		output := jobj.Schema{
 			Name:        outputType.Name(),
 			Description: fmt.Sprintf("Output schema for %s function return value", outputType.Name()),
-			Fields:      make([]*jobj.Field, 0),
 		}
 
-		for i := 0; i < outputType.NumField(); i++ {
-			field := outputType.Field(i)
-
-			if !field.IsExported() {
-				continue
-			}
-
-			jobjField := createFieldFromStructField(field)
-			if jobjField != nil {
-				output.Fields = append(output.Fields, jobjField)
-			}
+		fields, err := structSchemaFields(outputType, o)
+		if err != nil {
+			return jobj.Schema{}, err
 		}
+		output.Fields = fields
 
 		if len(output.Fields) == 0 {
-			return jobj.Schema{}, jobj.Schema{}, fmt.Errorf(
+			return jobj.Schema{}, fmt.Errorf(
 				"no valid fields found in output struct %s. Ensure fields are exported and of supported types",
 				outputType.Name(),
 			)
 		}
-	} else {
-		// Non-struct return type - use RootField (new behavior)
-		rootField := createFieldFromType(outputType, "result")
-		if rootField == nil {
-			return jobj.Schema{}, jobj.Schema{}, fmt.Errorf(
-				"unsupported return type %v", outputType,
-			)
-		}
+		return output, nil
+	}
 
-		typeName := outputType.Name()
-		if typeName == "" {
-			// For unnamed types like []string or map[string]int
-			typeName = outputType.String()
-		}
+	// Non-struct return type - use RootField (new behavior)
+	rootField := createFieldFromType(outputType, "result", structPath{options: o})
+	if rootField == nil {
+		return jobj.Schema{}, fmt.Errorf(
+			"unsupported return type %v", outputType,
+		)
+	}
+	if err := checkSupportedRoot(outputType, rootField, o); err != nil {
+		return jobj.Schema{}, err
+	}
 
-		output = jobj.Schema{
-			Name:        typeName,
-			Description: fmt.Sprintf("Output schema for %s function return value", typeName),
-			RootField:   rootField,
-		}
+	typeName := outputType.Name()
+	if typeName == "" {
+		// For unnamed types like []string or map[string]int
+		typeName = outputType.String()
 	}
 
-	return input, output, nil
+	return jobj.Schema{
+		Name:        typeName,
+		Description: fmt.Sprintf("Output schema for %s function return value", typeName),
+		RootField:   rootField,
+	}, nil
 }
 
 // NewSchemaFromFunc creates a Schema from a function's second parameter type.
-// Returns an error if the function doesn't match signature func(context.Context, any)
-// or if the second parameter is not a struct type.
-func NewSchemaFromFunc(function interface{}) (jobj.Schema, error) {
+// Returns an error if the function doesn't match signature func(context.Context, any).
+// A second parameter that is not a struct, such as a string or a []string, is wrapped as
+// the only, required property of the schema; see WithWrappedParamName. Options such as
+// WithStrict adjust the generation.
+func NewSchemaFromFunc(function interface{}, opts ...Option) (jobj.Schema, error) {
+	o := newOptions(opts)
 	if function == nil {
 		return jobj.Schema{}, fmt.Errorf("received nil function; must provide a valid function")
 	}
@@ -206,30 +207,19 @@ func NewSchemaFromFunc(function interface{}) (jobj.Schema, error) {
 	}
 
 	if paramType.Kind() != reflect.Struct {
-		return jobj.Schema{}, fmt.Errorf(
-			"second parameter must be a struct or pointer to struct, got %v. Consider wrapping your parameter in a struct",
-			paramType,
-		)
+		return wrappedParamSchema(funcType.In(1), o)
 	}
 
 	schema := jobj.Schema{
 		Name:        paramType.Name(),
 		Description: fmt.Sprintf("Schema for %s function parameters", paramType.Name()),
-		Fields:      make([]*jobj.Field, 0),
 	}
 
-	for i := 0; i < paramType.NumField(); i++ {
-		field := paramType.Field(i)
-
-		if !field.IsExported() {
-			continue
-		}
-
-		jobjField := createFieldFromStructField(field)
-		if jobjField != nil {
-			schema.Fields = append(schema.Fields, jobjField)
-		}
+	fields, err := structSchemaFields(paramType, o)
+	if err != nil {
+		return jobj.Schema{}, err
 	}
+	schema.Fields = fields
 
 	if len(schema.Fields) == 0 {
 		return jobj.Schema{}, fmt.Errorf(
@@ -241,12 +231,120 @@ func NewSchemaFromFunc(function interface{}) (jobj.Schema, error) {
 	return schema, nil
 }
 
+// NewSchemaFromFuncN creates a Schema for a function with several parameters after the context,
+// func(context.Context, A, B, ...) (R, error), so existing service methods can be described
+// without wrapper structs. The schema is an object with a property per parameter, named by
+// names in order or, without names, by position: "arg1", "arg2" and so on. Parameters of any
+// supported type are allowed; structs become nested objects. Parameters that are not pointers
+// are required. Options apply as in NewSchemaFromFunc.
+//
+// Example:
+//
+//	func (s *Service) Transfer(ctx context.Context, from Account, to Account, cents int64) (Receipt, error)
+//
+//	schema, err := funcschema.NewSchemaFromFuncN(svc.Transfer, []string{"from", "to", "cents"}, funcschema.WithStrict())
+func NewSchemaFromFuncN(function interface{}, names []string, opts ...Option) (jobj.Schema, error) {
+	if function == nil {
+		return jobj.Schema{}, fmt.Errorf("received nil function; must provide a valid function")
+	}
+
+	funcType := reflect.TypeOf(function)
+	if funcType.Kind() != reflect.Func {
+		return jobj.Schema{}, fmt.Errorf("received %v, expected a function type", funcType.Kind())
+	}
+	if funcType.NumIn() < 2 || funcType.In(0).String() != "context.Context" {
+		return jobj.Schema{}, fmt.Errorf("expected func(context.Context, ...) with at least one parameter after the context, got %v", funcType)
+	}
+	if len(names) != 0 && len(names) != funcType.NumIn()-1 {
+		return jobj.Schema{}, fmt.Errorf("got %d names for %d parameters", len(names), funcType.NumIn()-1)
+	}
+
+	o := newOptions(opts)
+	schema := jobj.Schema{
+		Name:        "Params",
+		Description: "Schema for function parameters",
+		Fields:      make([]*jobj.Field, 0, funcType.NumIn()-1),
+	}
+	for i := 1; i < funcType.NumIn(); i++ {
+		name := fmt.Sprintf("arg%d", i)
+		if len(names) != 0 {
+			name = names[i-1]
+		}
+		jobjField, err := paramField(name, funcType.In(i), o)
+		if err != nil {
+			return jobj.Schema{}, fmt.Errorf("parameter %d: %w", i, err)
+		}
+		schema.Fields = append(schema.Fields, jobjField)
+	}
+
+	return schema, nil
+}
+
+// WithWrappedParamName returns an Option naming the property that describes the parameter of
+// handlers whose parameter is not a struct, such as func(context.Context, []string) (R, error),
+// in the schemas of NewSchemaFromFunc, NewSchemaFromFuncV2, NewSchemasFromFunc and SchemasFor.
+// It is "input" by default, which an empty name keeps. The model's arguments then arrive as an
+// object, e.g. {"input": ["a", "b"]}, which tools built by NewTool unwrap before calling the
+// handler.
+//
+// Example:
+//
+//	schema, err := funcschema.NewSchemaFromFuncV2(lookup, funcschema.WithWrappedParamName("tickers"))
+func WithWrappedParamName(name string) Option {
+	return func(o *options) {
+		o.wrappedName = name
+	}
+}
+
+// wrappedParamSchema returns the schema of a handler whose parameter of type paramType is not a
+// struct: an object with the parameter as its only property, named by WithWrappedParamName.
+func wrappedParamSchema(paramType reflect.Type, o options) (jobj.Schema, error) {
+	name := "input"
+	if o.wrappedName != "" {
+		name = o.wrappedName
+	}
+	jobjField, err := paramField(name, paramType, o)
+	if err != nil {
+		return jobj.Schema{}, fmt.Errorf("second parameter: %w", err)
+	}
+	return jobj.Schema{
+		Name:        "Params",
+		Description: "Schema for function parameters",
+		Fields:      []*jobj.Field{jobjField},
+	}, nil
+}
+
+// paramField returns the property describing a function parameter of type paramType, as a struct
+// field of its type would be with o. Parameters that are not pointers are required.
+func paramField(name string, paramType reflect.Type, o options) (*jobj.Field, error) {
+	param := reflect.StructField{Name: name, Type: paramType, Tag: reflect.StructTag(fmt.Sprintf("json:%q", name))}
+	jobjField := createFieldFromStructField(param, structPath{options: o})
+	if jobjField == nil {
+		return nil, fmt.Errorf("unsupported type %v", paramType)
+	}
+	if paramType.Kind() != reflect.Ptr {
+		jobjField.Required()
+	}
+	if elem := derefType(paramType); elem.Kind() == reflect.Struct && jobjField.SubFields != nil {
+		if err := checkSupported(elem, jobjField.SubFields, o); err != nil {
+			return nil, err
+		}
+	}
+	return jobjField, nil
+}
+
 // createFieldFromStructField converts a reflect.StructField to a Field
 // createFieldFromType creates a Field from a reflect.Type (for non-struct return types)
 // This is used when the return type is an array, map, or primitive rather than a struct
-func createFieldFromType(typ reflect.Type, name string) *jobj.Field {
+func createFieldFromType(typ reflect.Type, name string, path structPath) *jobj.Field {
 	var jobjField *jobj.Field
 
+	// Types with a registered mapper are mapped as fields without struct tags
+	field := reflect.StructField{Name: name, Type: typ}
+	if mapped, ok := mappedField(field, typ, name, path); ok {
+		return mapped
+	}
+
 	switch typ.Kind() {
 	case reflect.String:
 		jobjField = jobj.Text(name)
@@ -254,20 +352,54 @@ func createFieldFromType(typ reflect.Type, name string) *jobj.Field {
 		jobjField = jobj.Bool(name)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		jobjField = jobj.Int(name)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		jobjField = jobj.Int(name).Minimum(0)
 	case reflect.Float32, reflect.Float64:
 		jobjField = jobj.Float(name)
 	case reflect.Slice, reflect.Array:
 		elemType := typ.Elem()
-		if elemType.Kind() == reflect.Struct {
+		// Pointer items, e.g. []*Item, are described by the type they point to
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded by encoding/json as a base64 string
+			jobjField = jobj.Bytes(name)
+		} else if itemField, ok := mappedField(field, elemType, "", path); ok {
+			if itemField == nil {
+				return nil
+			}
+			jobjField = jobj.ArrayOfField(name, itemField)
+		} else if elemType.String() == "time.Time" || elemType.String() == "jobj.JsonDuration" {
+			// Arrays of times and durations, described as for single fields
+			itemField := jobj.Date("")
+			if elemType.String() == "jobj.JsonDuration" {
+				itemField = jobj.Duration("")
+			}
+			jobjField = jobj.ArrayOfField(name, itemField)
+		} else if elemType.Kind() == reflect.Struct {
 			// Array of structs
-			subFields := make([]*jobj.Field, 0)
-			for i := 0; i < elemType.NumField(); i++ {
-				subField := createFieldFromStructField(elemType.Field(i))
-				if subField != nil {
-					subFields = append(subFields, subField)
-				}
+			jobjField = objectArrayField(name, elemType, path)
+		} else if elemType.Kind() == reflect.Interface {
+			// []any
+			jobjField = jobj.ArrayOfField(name, jobj.Any(""))
+		} else if elemType.Kind() == reflect.Map {
+			// Arrays of maps, e.g. []map[string]string
+			itemField := createFieldFromType(elemType, "", path)
+			if itemField == nil {
+				return nil
+			}
+			jobjField = jobj.ArrayOfField(name, itemField)
+		} else if elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array {
+			// Nested arrays, e.g. [][]float64
+			itemField := createFieldFromType(elemType, "", path)
+			if itemField == nil {
+				return nil
 			}
-			jobjField = jobj.Array(name, subFields)
+			jobjField = jobj.ArrayOfField(name, itemField)
+		} else if isUnsigned(elemType) {
+			// Unsigned items are integers of at least 0
+			jobjField = jobj.ArrayOfField(name, jobj.Int("").Minimum(0))
 		} else {
 			// Array of primitives
 			var itemType jobj.DataType
@@ -281,11 +413,15 @@ func createFieldFromType(typ reflect.Type, name string) *jobj.Field {
 			case reflect.Float32, reflect.Float64:
 				itemType = jobj.TypeNumber
 			default:
-				slog.Warn("Unsupported array element type", "type", typ, "elemType", elemType.Kind())
+				logWarn("Unsupported array element type", "type", typ, "elemType", elemType.Kind())
 				return nil
 			}
 			jobjField = jobj.ArrayOf(name, itemType)
 		}
+		if typ.Kind() == reflect.Array && jobjField.ValueType == jobj.TypeArray {
+			// Go arrays, e.g. [768]float32 embeddings, always encode with exactly their length
+			jobjField.MinItems(typ.Len()).MaxItems(typ.Len())
+		}
 	case reflect.Map:
 		// Handle map types - maps become objects with additionalProperties
 		valueType := typ.Elem()
@@ -297,6 +433,14 @@ func createFieldFromType(typ reflect.Type, name string) *jobj.Field {
 			AdditionalProperties: true,
 		}
 
+		if valueField, ok := mappedField(field, valueType, "", path); ok {
+			if valueField == nil {
+				return nil
+			}
+			setMappedValue(jobjField, valueField)
+			break
+		}
+
 		// Determine the value type for additionalProperties
 		switch valueType.Kind() {
 		case reflect.String:
@@ -305,21 +449,15 @@ func createFieldFromType(typ reflect.Type, name string) *jobj.Field {
 			jobjField.AdditionalPropertiesType = jobj.TypeBoolean
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			jobjField.AdditionalPropertiesType = jobj.TypeInteger
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			// Unsigned values are integers of at least 0
+			jobjField.AdditionalPropertiesType = jobj.TypeInteger
+			jobjField.AdditionalPropertiesField = jobj.Int("").Minimum(0)
 		case reflect.Float32, reflect.Float64:
 			jobjField.AdditionalPropertiesType = jobj.TypeNumber
 		case reflect.Struct:
 			// Map with struct values
-			subFields := make([]*jobj.Field, 0)
-			for i := 0; i < valueType.NumField(); i++ {
-				subField := createFieldFromStructField(valueType.Field(i))
-				if subField != nil {
-					subFields = append(subFields, subField)
-				}
-			}
-			jobjField.AdditionalPropertiesField = &jobj.Field{
-				ValueType: jobj.TypeObject,
-				SubFields: subFields,
-			}
+			jobjField.AdditionalPropertiesField = objectValueField(valueType, path)
 		case reflect.Interface:
 			// Map with interface{} values
 			jobjField.AdditionalPropertiesField = &jobj.Field{
@@ -327,33 +465,49 @@ func createFieldFromType(typ reflect.Type, name string) *jobj.Field {
 				SubFields: nil,
 			}
 		default:
-			slog.Warn("Unsupported map value type", "type", typ, "valueType", valueType.Kind())
+			logWarn("Unsupported map value type", "type", typ, "valueType", valueType.Kind())
 			return nil
 		}
 	default:
-		slog.Warn("Unsupported return type", "type", typ, "kind", typ.Kind())
+		logWarn("Unsupported return type", "type", typ, "kind", typ.Kind())
 		return nil
 	}
 
 	return jobjField
 }
 
-func createFieldFromStructField(field reflect.StructField) *jobj.Field {
+// propertyName returns the schema property name of a struct field: the name from its json tag if
+// present, otherwise the Go field name, as named by o. Fields tagged json:"-" return "-".
+func propertyName(field reflect.StructField, o options) string {
+	jsonTag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return goFieldName(field.Name, o)
+	}
+	// Parse the json tag to get the field name (before any comma)
+	if commaIdx := strings.Index(jsonTag, ","); commaIdx != -1 {
+		jsonTag = jsonTag[:commaIdx]
+	}
+	if jsonTag == "" {
+		// Tags with options only, e.g. json:",omitempty", keep the Go name as in encoding/json
+		return goFieldName(field.Name, o)
+	}
+	return jsonTag
+}
+
+func createFieldFromStructField(field reflect.StructField, path structPath) *jobj.Field {
 	var jobjField *jobj.Field
 
-	// Get the field name from JSON tag if present, otherwise use the Go field name
-	fieldName := field.Name
-	if jsonTag, ok := field.Tag.Lookup("json"); ok {
-		// Parse the json tag to get the field name (before any comma)
-		if commaIdx := strings.Index(jsonTag, ","); commaIdx != -1 {
-			fieldName = jsonTag[:commaIdx]
-		} else {
-			fieldName = jsonTag
-		}
-		// Skip field if json tag is "-"
-		if fieldName == "-" {
+	fieldName := propertyName(field, path.options)
+	// Skip field if json tag is "-"
+	if fieldName == "-" {
+		return nil
+	}
+
+	if mapped, ok := mappedField(field, field.Type, fieldName, path); ok {
+		if mapped == nil {
 			return nil
 		}
+		return applyFieldTags(field, path, mapped)
 	}
 
 	switch field.Type.Kind() {
@@ -367,23 +521,20 @@ func createFieldFromStructField(field reflect.StructField) *jobj.Field {
 			jobjField = jobj.Bool(fieldName)
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			jobjField = jobj.Int(fieldName)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			jobjField = jobj.Int(fieldName).Minimum(0)
 		case reflect.Float32, reflect.Float64:
 			jobjField = jobj.Float(fieldName)
 		case reflect.Struct:
 			if elemType.String() == "time.Time" {
 				jobjField = jobj.Date(fieldName)
+			} else if elemType.String() == "jobj.JsonDuration" {
+				jobjField = jobj.Duration(fieldName)
 			} else {
-				subFields := make([]*jobj.Field, 0)
-				for i := 0; i < elemType.NumField(); i++ {
-					subField := createFieldFromStructField(elemType.Field(i))
-					if subField != nil {
-						subFields = append(subFields, subField)
-					}
-				}
-				jobjField = jobj.Object(fieldName, subFields)
+				jobjField = objectField(fieldName, elemType, path)
 			}
 		default:
-			slog.Warn("Unsupported pointer element type", "field", field.Name, "elemType", elemType.Kind())
+			logWarn("Unsupported pointer element type", "field", field.Name, "elemType", elemType.Kind())
 			return nil
 		}
 		// Pointer fields are inherently optional, so we don't mark them as required by default
@@ -393,33 +544,66 @@ func createFieldFromStructField(field reflect.StructField) *jobj.Field {
 		jobjField = jobj.Bool(fieldName)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		jobjField = jobj.Int(fieldName)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		jobjField = jobj.Int(fieldName).Minimum(0)
 	case reflect.Float32, reflect.Float64:
 		jobjField = jobj.Float(fieldName)
 	case reflect.Struct:
 		if field.Type.String() == "time.Time" {
 			jobjField = jobj.Date(fieldName)
+		} else if field.Type.String() == "jobj.JsonDuration" {
+			// time.Duration itself encodes as integer nanoseconds, so only JsonDuration maps to a duration string
+			jobjField = jobj.Duration(fieldName)
 		} else {
-			subFields := make([]*jobj.Field, 0)
-			for i := 0; i < field.Type.NumField(); i++ {
-				subField := createFieldFromStructField(field.Type.Field(i))
-				if subField != nil {
-					subFields = append(subFields, subField)
-				}
-			}
-			jobjField = jobj.Object(fieldName, subFields)
+			jobjField = objectField(fieldName, field.Type, path)
 		}
 	case reflect.Slice, reflect.Array:
 		elemType := field.Type.Elem()
-		if elemType.Kind() == reflect.Struct {
+		// Pointer items, e.g. []*Item, are described by the type they point to
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded by encoding/json as a base64 string
+			jobjField = jobj.Bytes(fieldName)
+		} else if itemField, ok := mappedField(field, elemType, "", path); ok {
+			// Items of a type with a registered mapper
+			if itemField == nil {
+				return nil
+			}
+			jobjField = jobj.ArrayOfField(fieldName, itemField)
+		} else if elemType.String() == "time.Time" || elemType.String() == "jobj.JsonDuration" {
+			// Arrays of times and durations, described as for single fields
+			itemField := jobj.Date("")
+			if elemType.String() == "jobj.JsonDuration" {
+				itemField = jobj.Duration("")
+			}
+			jobjField = jobj.ArrayOfField(fieldName, itemField)
+		} else if elemType.Kind() == reflect.Struct {
 			// Array of structs
-			subFields := make([]*jobj.Field, 0)
-			for i := 0; i < elemType.NumField(); i++ {
-				subField := createFieldFromStructField(elemType.Field(i))
-				if subField != nil {
-					subFields = append(subFields, subField)
-				}
+			jobjField = objectArrayField(fieldName, elemType, path)
+		} else if elemType.Kind() == reflect.Interface {
+			// []any - any items, or those listed by the items tag
+			jobjField = jobj.ArrayOfField(fieldName, interfaceItemField(field, path))
+		} else if elemType.Kind() == reflect.Map {
+			// Arrays of maps, e.g. []map[string]string - the items are objects with additionalProperties
+			itemField := createFieldFromType(elemType, "", path)
+			if itemField == nil {
+				logWarn("Unsupported map array element type", "field", field.Name, "elemType", elemType)
+				return nil
+			}
+			jobjField = jobj.ArrayOfField(fieldName, itemField)
+		} else if elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array {
+			// Nested arrays, e.g. [][]float64 - the items are themselves an array field
+			itemField := createFieldFromType(elemType, "", path)
+			if itemField == nil {
+				logWarn("Unsupported nested array element type", "field", field.Name, "elemType", elemType)
+				return nil
 			}
-			jobjField = jobj.Array(fieldName, subFields)
+			jobjField = jobj.ArrayOfField(fieldName, itemField)
+		} else if isUnsigned(elemType) {
+			// Unsigned items are integers of at least 0
+			jobjField = jobj.ArrayOfField(fieldName, jobj.Int("").Minimum(0))
 		} else {
 			// Array of primitives - use ArrayOf with the appropriate item type
 			var itemType jobj.DataType
@@ -433,11 +617,15 @@ func createFieldFromStructField(field reflect.StructField) *jobj.Field {
 			case reflect.Float32, reflect.Float64:
 				itemType = jobj.TypeNumber
 			default:
-				slog.Warn("Unsupported array element type", "field", field.Name, "elemType", elemType.Kind())
+				logWarn("Unsupported array element type", "field", field.Name, "elemType", elemType.Kind())
 				return nil
 			}
 			jobjField = jobj.ArrayOf(fieldName, itemType)
 		}
+		if field.Type.Kind() == reflect.Array && jobjField.ValueType == jobj.TypeArray {
+			// Go arrays, e.g. [768]float32 embeddings, always encode with exactly their length
+			jobjField.MinItems(field.Type.Len()).MaxItems(field.Type.Len())
+		}
 	case reflect.Map:
 		// Handle map types - maps become objects with additionalProperties
 		valueType := field.Type.Elem()
@@ -449,6 +637,15 @@ func createFieldFromStructField(field reflect.StructField) *jobj.Field {
 			AdditionalProperties: true,
 		}
 
+		// Values of a type with a registered mapper
+		if valueField, ok := mappedField(field, valueType, "", path); ok {
+			if valueField == nil {
+				return nil
+			}
+			setMappedValue(jobjField, valueField)
+			break
+		}
+
 		// Determine the value type for additionalProperties
 		switch valueType.Kind() {
 		case reflect.String:
@@ -457,38 +654,22 @@ func createFieldFromStructField(field reflect.StructField) *jobj.Field {
 			jobjField.AdditionalPropertiesType = jobj.TypeBoolean
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			jobjField.AdditionalPropertiesType = jobj.TypeInteger
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			// Unsigned values are integers of at least 0
+			jobjField.AdditionalPropertiesType = jobj.TypeInteger
+			jobjField.AdditionalPropertiesField = jobj.Int("").Minimum(0)
 		case reflect.Float32, reflect.Float64:
 			jobjField.AdditionalPropertiesType = jobj.TypeNumber
 		case reflect.Struct:
 			// Map with struct values
-			subFields := make([]*jobj.Field, 0)
-			for i := 0; i < valueType.NumField(); i++ {
-				subField := createFieldFromStructField(valueType.Field(i))
-				if subField != nil {
-					subFields = append(subFields, subField)
-				}
-			}
-			jobjField.AdditionalPropertiesField = &jobj.Field{
-				ValueType: jobj.TypeObject,
-				SubFields: subFields,
-			}
+			jobjField.AdditionalPropertiesField = objectValueField(valueType, path)
 		case reflect.Ptr:
 			// Map with pointer values - unwrap and process
 			elemType := valueType.Elem()
 			if elemType.Kind() == reflect.Struct {
-				subFields := make([]*jobj.Field, 0)
-				for i := 0; i < elemType.NumField(); i++ {
-					subField := createFieldFromStructField(elemType.Field(i))
-					if subField != nil {
-						subFields = append(subFields, subField)
-					}
-				}
-				jobjField.AdditionalPropertiesField = &jobj.Field{
-					ValueType: jobj.TypeObject,
-					SubFields: subFields,
-				}
+				jobjField.AdditionalPropertiesField = objectValueField(elemType, path)
 			} else {
-				slog.Warn("Unsupported map pointer value type", "field", field.Name, "valueType", elemType.Kind())
+				logWarn("Unsupported map pointer value type", "field", field.Name, "valueType", elemType.Kind())
 				return nil
 			}
 		case reflect.Interface:
@@ -500,26 +681,164 @@ func createFieldFromStructField(field reflect.StructField) *jobj.Field {
 				SubFields: nil, // Empty SubFields means any properties allowed
 			}
 		default:
-			slog.Warn("Unsupported map value type", "field", field.Name, "valueType", valueType.Kind())
+			logWarn("Unsupported map value type", "field", field.Name, "valueType", valueType.Kind())
 			return nil
 		}
 	default:
-		slog.Warn("Unsupported field type", "field", field.Name, "type", field.Type.Kind())
+		logWarn("Unsupported field type", "field", field.Name, "type", field.Type.Kind())
 		return nil
 	}
 
-	if jobjField != nil {
-		// Support both "desc" and "description" tags, with "desc" taking precedence
-		if desc, ok := field.Tag.Lookup("desc"); ok {
-			jobjField.Desc(desc)
-		} else if desc, ok := field.Tag.Lookup("description"); ok {
-			jobjField.Desc(desc)
+	if jobjField == nil {
+		return nil
+	}
+	return applyFieldTags(field, path, jobjField)
+}
+
+// applyFieldTags applies the struct tags of field, such as desc, required and default, to the
+// jobjField generated for it, along with its doc comment if loaded with LoadDocComments.
+func applyFieldTags(field reflect.StructField, path structPath, jobjField *jobj.Field) *jobj.Field {
+	// Doc comments come first, so any description tag takes precedence
+	if jobjField.ValueDescription == "" {
+		if comment := docComment(field, path); comment != "" {
+			jobjField.Desc(comment)
 		}
+	}
+
+	// Inferred requiredness comes first, so any required tag or rule takes precedence
+	applyInferredRequired(field, jobjField, path.options)
+	applyNullablePointer(field, jobjField, path.options)
+
+	// jsonschema tags come first, so funcschema tags on the same field take precedence
+	applyJSONSchemaTag(field, jobjField)
+
+	// Support both "desc" and "description" tags, with "desc" taking precedence
+	if desc, ok := field.Tag.Lookup("desc"); ok {
+		jobjField.Desc(desc)
+	} else if desc, ok := field.Tag.Lookup("description"); ok {
+		jobjField.Desc(desc)
+	}
+
+	if title, ok := field.Tag.Lookup("title"); ok {
+		jobjField.Title(title)
+	}
+
+	if deprecated, ok := field.Tag.Lookup("deprecated"); ok && deprecated == "true" {
+		jobjField.Deprecated()
+	}
+
+	// validator tags come first, so funcschema tags on the same field take precedence
+	applyValidateTag(field, jobjField, path.options)
 
-		if req, ok := field.Tag.Lookup("required"); ok && req == "true" {
+	if req, ok := field.Tag.Lookup("required"); ok {
+		switch req {
+		case "true":
 			jobjField.Required()
+		case "false":
+			jobjField.Optional()
 		}
 	}
+	applyOmitemptyOptional(field, jobjField, path.options)
+
+	if format, ok := field.Tag.Lookup("format"); ok {
+		if jobjField.ValueType == jobj.TypeString {
+			jobjField.Format(format)
+		} else {
+			logWarn("Ignoring format tag on non-string field", "field", field.Name, "type", jobjField.ValueType)
+		}
+	}
+
+	if desc, ok := field.Tag.Lookup("itemsDesc"); ok {
+		if jobjField.ValueType == jobj.TypeArray {
+			jobjField.ItemsDesc(desc)
+		} else {
+			logWarn("Ignoring itemsDesc tag on non-array field", "field", field.Name, "type", jobjField.ValueType)
+		}
+	}
+
+	// Array cardinality, e.g. minItems:"3" maxItems:"5" for "3 to 5 bullet points"
+	if n, ok := countTag(field, jobjField, "minItems"); ok {
+		jobjField.MinItems(n)
+	}
+	if n, ok := countTag(field, jobjField, "maxItems"); ok {
+		jobjField.MaxItems(n)
+	}
+	if unique, ok := field.Tag.Lookup("uniqueItems"); ok {
+		if jobjField.ValueType != jobj.TypeArray {
+			logWarn("Ignoring uniqueItems tag on non-array field", "field", field.Name, "type", jobjField.ValueType)
+		} else if unique == "true" {
+			jobjField.UniqueItems()
+		}
+	}
+
+	if def, ok := field.Tag.Lookup("default"); ok {
+		value, err := tagValue(jobjField, def)
+		if err != nil {
+			logWarn("Invalid default tag", "field", field.Name, "default", def, "error", err)
+		} else {
+			jobjField.Default(value)
+		}
+	}
+
+	// "examples" lists several examples separated by "|"; "example" holds a single one
+	var examples []string
+	if tag, ok := field.Tag.Lookup("examples"); ok {
+		examples = strings.Split(tag, "|")
+	} else if tag, ok := field.Tag.Lookup("example"); ok {
+		examples = []string{tag}
+	}
+	values := make([]interface{}, 0, len(examples))
+	for _, example := range examples {
+		value, err := tagValue(jobjField, example)
+		if err != nil {
+			logWarn("Invalid example tag", "field", field.Name, "example", example, "error", err)
+			continue
+		}
+		values = append(values, value)
+	}
+	if len(values) > 0 {
+		jobjField.Examples(values...)
+	}
 
 	return jobjField
 }
+
+// countTag returns the item count in the minItems or maxItems tag of an array field, warning
+// about counts that are not non-negative integers and about the tag on other fields.
+func countTag(field reflect.StructField, jobjField *jobj.Field, key string) (int, bool) {
+	tag, ok := field.Tag.Lookup(key)
+	if !ok {
+		return 0, false
+	}
+	if jobjField.ValueType != jobj.TypeArray {
+		logWarn("Ignoring "+key+" tag on non-array field", "field", field.Name, "type", jobjField.ValueType)
+		return 0, false
+	}
+	n, err := strconv.Atoi(tag)
+	if err != nil || n < 0 {
+		logWarn("Invalid "+key+" tag", "field", field.Name, "value", tag)
+		return 0, false
+	}
+	return n, true
+}
+
+// tagValue parses the value of a struct tag, such as default or example, as a value of field's
+// type: strings are taken as they are, numbers and booleans are parsed, and arrays and objects are
+// read as JSON.
+func tagValue(field *jobj.Field, tag string) (interface{}, error) {
+	switch field.ValueType {
+	case jobj.TypeString:
+		return tag, nil
+	case jobj.TypeInteger:
+		return strconv.ParseInt(tag, 10, 64)
+	case jobj.TypeNumber:
+		return strconv.ParseFloat(tag, 64)
+	case jobj.TypeBoolean:
+		return strconv.ParseBool(tag)
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(tag), &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/mhpenta/jobj"
 	"github.com/mhpenta/jobj/safeunmarshal"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 type SearchTool struct{}
@@ -30,9 +32,11 @@ func TestSearchTool_Parameters(t *testing.T) {
 
 	correct := `{
   "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "SearchToolParams",
   "definitions": {
     "SearchToolParams": {
       "additionalProperties": false,
+      "description": "Schema for SearchToolParams function parameters",
       "properties": {
         "ID": {
           "description": "ID of item to search",
@@ -84,9 +88,11 @@ func TestSearchTool_ParametersV2(t *testing.T) {
 
 	correct := `{
   "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "SearchToolParams",
   "definitions": {
     "SearchToolParams": {
       "additionalProperties": false,
+      "description": "Schema for SearchToolParams function parameters",
       "properties": {
         "ID": {
           "description": "ID of item to search",
@@ -356,8 +362,9 @@ func TestNewSchemasFromFunc(t *testing.T) {
 	assert.NotContains(t, outputRequired, "user_id")
 }
 
-// TestNewSchemasFromFunc_InvalidInputType tests error handling when input type is not a struct
-func TestNewSchemasFromFunc_InvalidInputType(t *testing.T) {
+// TestNewSchemasFromFunc_WrappedInputType tests that an input type that is not a struct is
+// wrapped as the only property of the input schema
+func TestNewSchemasFromFunc_WrappedInputType(t *testing.T) {
 	type ValidOutput struct {
 		Result string `json:"result"`
 	}
@@ -367,9 +374,17 @@ func TestNewSchemasFromFunc_InvalidInputType(t *testing.T) {
 		return ValidOutput{Result: input}, nil
 	}
 
-	_, _, err := NewSchemasFromFunc(handler)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "input parameter type must be a struct")
+	input, output, err := NewSchemasFromFunc(handler, WithWrappedParamName("query"))
+	assert.NoError(t, err)
+	assert.Len(t, input.Fields, 1)
+	assert.Equal(t, "query", input.Fields[0].ValueName)
+	assert.True(t, input.Fields[0].ValueRequired)
+	assert.Equal(t, "result", output.Fields[0].ValueName)
+
+	_, _, err = NewSchemasFromFunc(func(ctx context.Context, input chan int) (ValidOutput, error) {
+		return ValidOutput{}, nil
+	})
+	assert.EqualError(t, err, "second parameter: unsupported type chan int")
 }
 
 // TestNewSchemasFromFunc_NonStructOutputType tests that non-struct output types are now supported
@@ -1371,3 +1386,262 @@ func TestSafeSchemasFromFuncWithNonStructReturn(t *testing.T) {
 	items := outputMap["items"].(map[string]interface{})
 	assert.Equal(t, "string", items["type"])
 }
+
+// TestTypedConstRootField tests that typed AnyOf consts keep their JSON types in GetPropertiesMap
+func TestTypedConstRootField(t *testing.T) {
+	schema := jobj.Schema{
+		Name: "Severity",
+		RootField: jobj.AnyOf("result", []jobj.ConstDescription{
+			{Const: 1, Description: "low"},
+			{Const: true, Description: "critical"},
+		}).Desc("Severity level"),
+	}
+
+	propsMap := GetPropertiesMap(schema)
+	assert.Equal(t, "Severity level", propsMap["description"])
+
+	anyOf := propsMap["anyOf"].([]map[string]interface{})
+	assert.Len(t, anyOf, 2)
+	assert.Equal(t, 1, anyOf[0]["const"])
+	assert.Equal(t, true, anyOf[1]["const"])
+
+	encoded, err := json.Marshal(propsMap)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"const":1`)
+}
+
+// TestDurationFields tests that JsonDuration fields map to duration-formatted strings
+func TestDurationFields(t *testing.T) {
+	type RetryParams struct {
+		Timeout jobj.JsonDuration  `json:"timeout" desc:"How long to wait" required:"true"`
+		Backoff *jobj.JsonDuration `json:"backoff"`
+		Retries int                `json:"retries"`
+	}
+
+	schema, err := SchemaFromStruct[RetryParams]()
+	assert.NoError(t, err)
+
+	props := GetPropertiesMap(schema)["properties"].(map[string]interface{})
+
+	timeout := props["timeout"].(map[string]string)
+	assert.Equal(t, "string", timeout["type"])
+	assert.Equal(t, "duration", timeout["format"])
+	assert.Equal(t, "How long to wait", timeout["description"])
+
+	backoff := props["backoff"].(map[string]string)
+	assert.Equal(t, "duration", backoff["format"])
+
+	var params RetryParams
+	err = json.Unmarshal([]byte(`{"timeout":"PT30S","backoff":"2s","retries":3}`), &params)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, params.Timeout.Duration)
+	assert.Equal(t, 2*time.Second, params.Backoff.Duration)
+}
+
+// TestByteSliceFields tests that []byte maps to a base64 string rather than being dropped
+func TestByteSliceFields(t *testing.T) {
+	type UploadParams struct {
+		Name    string `json:"name"`
+		Content []byte `json:"content" desc:"File content"`
+	}
+
+	schema, err := SchemaFromStruct[UploadParams]()
+	assert.NoError(t, err)
+	assert.Len(t, schema.Fields, 2)
+
+	props := GetPropertiesMap(schema)["properties"].(map[string]interface{})
+	content := props["content"].(map[string]string)
+	assert.Equal(t, "string", content["type"])
+	assert.Equal(t, "base64", content["contentEncoding"])
+
+	_, output, err := NewSchemasFromFunc(func(ctx context.Context, p UploadParams) ([]byte, error) {
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	outputMap := GetPropertiesMap(output)
+	assert.Equal(t, "string", outputMap["type"])
+	assert.Equal(t, "base64", outputMap["contentEncoding"])
+}
+
+// TestNestedArrays tests that [][]T fields are emitted as arrays of arrays instead of being dropped
+func TestNestedArrays(t *testing.T) {
+	type EmbeddingParams struct {
+		Matrix [][]float64   `json:"matrix" desc:"Embedding matrix" required:"true"`
+		Grid   [][][]string  `json:"grid"`
+		Rows   [][]RowRecord `json:"rows"`
+	}
+
+	schema, err := SchemaFromStruct[EmbeddingParams]()
+	assert.NoError(t, err)
+	assert.Len(t, schema.Fields, 3)
+
+	props := GetPropertiesMap(schema)["properties"].(map[string]interface{})
+
+	matrix := props["matrix"].(map[string]interface{})
+	assert.Equal(t, "array", matrix["type"])
+	assert.Equal(t, "Embedding matrix", matrix["description"])
+	matrixItems := matrix["items"].(map[string]interface{})
+	assert.Equal(t, "array", matrixItems["type"])
+	assert.Equal(t, map[string]interface{}{"type": "number"}, matrixItems["items"])
+
+	grid := props["grid"].(map[string]interface{})
+	gridInner := grid["items"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, "array", gridInner["type"])
+
+	rows := props["rows"].(map[string]interface{})
+	rowItems := rows["items"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, "object", rowItems["type"])
+
+	_, output, err := NewSchemasFromFunc(func(ctx context.Context, p EmbeddingParams) ([][]int, error) {
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	outputMap := GetPropertiesMap(output)
+	assert.Equal(t, "array", outputMap["items"].(map[string]interface{})["type"])
+}
+
+type RowRecord struct {
+	Label string `json:"label"`
+}
+
+// TestUnsignedIntegerFields tests that unsigned integer fields map to integer instead of being dropped
+func TestUnsignedIntegerFields(t *testing.T) {
+	type Counters struct {
+		Count   uint            `json:"count" desc:"Number of items" required:"true"`
+		Small   uint8           `json:"small"`
+		Port    *uint16         `json:"port"`
+		ID      uint64          `json:"id"`
+		Shards  []uint32        `json:"shards"`
+		ByName  map[string]uint `json:"by_name"`
+		Payload []byte          `json:"payload"`
+	}
+
+	handler := func(ctx context.Context, params Counters) (map[string]uint64, error) {
+		return nil, nil
+	}
+
+	inputSchema, outputSchema, err := NewSchemasFromFunc(handler)
+	assert.NoError(t, err)
+
+	// Round-trip through JSON so nested schemas can be inspected uniformly
+	var input map[string]interface{}
+	encoded, _ := json.Marshal(GetPropertiesMap(inputSchema))
+	assert.NoError(t, json.Unmarshal(encoded, &input))
+	props := input["properties"].(map[string]interface{})
+	for _, name := range []string{"count", "small", "port", "id"} {
+		assert.Contains(t, props, name)
+		assert.Equal(t, "integer", props[name].(map[string]interface{})["type"], name)
+	}
+	assert.Equal(t, "integer", props["shards"].(map[string]interface{})["items"].(map[string]interface{})["type"])
+	assert.Equal(t, "integer", props["by_name"].(map[string]interface{})["additionalProperties"].(map[string]interface{})["type"])
+	assert.Equal(t, "string", props["payload"].(map[string]interface{})["type"])
+
+	assert.Equal(t, "integer", GetPropertiesMap(outputSchema)["additionalProperties"].(map[string]interface{})["type"])
+}
+
+type transferAccount struct {
+	IBAN string `json:"iban" desc:"Account IBAN" required:"true"`
+}
+
+func transfer(ctx context.Context, from transferAccount, to *transferAccount, cents int64, memo []string) (string, error) {
+	return "", nil
+}
+
+func TestNewSchemaFromFuncN(t *testing.T) {
+	schema, err := NewSchemaFromFuncN(transfer, []string{"from", "to", "cents", "memo"})
+	assert.NoError(t, err)
+	assert.Len(t, schema.Fields, 4)
+	assert.Equal(t, []string{"from", "cents", "memo"}, schema.RequiredFields())
+	assert.Equal(t, "iban", schema.Fields[0].SubFields[0].ValueName)
+	assert.Equal(t, "iban", schema.Fields[1].SubFields[0].ValueName)
+	assert.Equal(t, jobj.TypeInteger, schema.Fields[2].ValueType)
+	assert.Equal(t, jobj.TypeString, schema.Fields[3].ArrayItemType)
+
+	errs, err := schema.ValidateJSON([]byte(`{"from":{"iban":"DE89"},"cents":500,"memo":["rent"]}`))
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+
+	// Without names, parameters are named by position
+	schema, err = NewSchemaFromFuncN(transfer, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"arg1", "arg2", "arg3", "arg4"}, []string{
+		schema.Fields[0].ValueName, schema.Fields[1].ValueName, schema.Fields[2].ValueName, schema.Fields[3].ValueName,
+	})
+
+	// Options apply to every parameter
+	schema, err = NewSchemaFromFuncN(transfer, nil, WithNullablePointers())
+	assert.NoError(t, err)
+	assert.False(t, schema.Fields[0].ValueNullable)
+	assert.True(t, schema.Fields[1].ValueNullable)
+
+	_, err = NewSchemaFromFuncN(transfer, []string{"from"})
+	assert.EqualError(t, err, "got 1 names for 4 parameters")
+	_, err = NewSchemaFromFuncN(func(ctx context.Context) error { return nil }, nil)
+	assert.Error(t, err)
+	_, err = NewSchemaFromFuncN(func(ctx context.Context, callback func()) error { return nil }, nil)
+	assert.EqualError(t, err, "parameter 1: unsupported type func()")
+}
+
+func TestNewSchemaFromFuncWrappedParam(t *testing.T) {
+	schema, err := NewSchemaFromFunc(func(ctx context.Context, tickers []string) (string, error) { return "", nil })
+	assert.NoError(t, err)
+	assert.Len(t, schema.Fields, 1)
+	assert.Equal(t, "input", schema.Fields[0].ValueName)
+	assert.Equal(t, jobj.TypeArray, schema.Fields[0].ValueType)
+	assert.Equal(t, jobj.TypeString, schema.Fields[0].ArrayItemType)
+	assert.Equal(t, []string{"input"}, schema.RequiredFields())
+
+	errs, err := schema.ValidateJSON([]byte(`{"input":["AAPL","MSFT"]}`))
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+
+	// Pointers are optional
+	schema, err = NewSchemaFromFuncV2(func(ctx context.Context, limit *int) (string, error) { return "", nil })
+	assert.NoError(t, err)
+	assert.Equal(t, jobj.TypeInteger, schema.Fields[0].ValueType)
+	assert.Empty(t, schema.RequiredFields())
+
+	search := func(ctx context.Context, query string) (string, error) { return "", nil }
+	properties, err := SafeSchemaFromFunc(search, WithWrappedParamName("query"))
+	assert.NoError(t, err)
+	assert.Contains(t, properties["properties"], "query")
+	assert.Equal(t, []string{"query"}, properties["required"])
+
+	// The name applies to its own call; an empty name keeps the default
+	schema, err = NewSchemaFromFunc(search, WithWrappedParamName(""))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"input"}, schema.RequiredFields())
+
+	_, err = NewSchemaFromFunc(func(ctx context.Context, callback func()) (string, error) { return "", nil })
+	assert.EqualError(t, err, "second parameter: unsupported type func()")
+}
+
+type streamParams struct {
+	Topic string `json:"topic" desc:"Topic to follow" required:"true"`
+}
+
+type streamEvent struct {
+	Kind string `json:"kind" desc:"Event kind"`
+	Seq  int    `json:"seq" desc:"Sequence number"`
+}
+
+func TestNewSchemasFromStreamFunc(t *testing.T) {
+	stream := func(ctx context.Context, params streamParams, yield func(streamEvent) error) error { return nil }
+	input, output, err := NewSchemasFromStreamFunc(stream)
+	assert.NoError(t, err)
+	assert.Equal(t, "streamParams", input.Name)
+	assert.Equal(t, []string{"topic"}, input.RequiredFields())
+	assert.Equal(t, "streamEvent", output.Name)
+	assert.Len(t, output.Fields, 2)
+
+	watch := func(ctx context.Context, params *streamParams) (<-chan []string, error) { return nil, nil }
+	input, output, err = NewSchemasFromChanFunc(watch)
+	assert.NoError(t, err)
+	assert.Equal(t, "streamParams", input.Name)
+	assert.Equal(t, jobj.TypeArray, output.RootField.ValueType)
+
+	// Parameters that are not structs are wrapped, as for NewSchemasFromFunc
+	input, _, err = NewSchemasFromStreamFunc(func(ctx context.Context, topic string, yield func(streamEvent) error) error { return nil })
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"input"}, input.RequiredFields())
+}
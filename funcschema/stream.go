@@ -0,0 +1,42 @@
+package funcschema
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/mhpenta/jobj"
+)
+
+// NewSchemasFromStreamFunc creates jobj.Schemas for a streaming handler, which passes each result
+// to yield as it is produced instead of returning it:
+//
+//	func(context.Context, T, func(R) error) error
+//
+// The input schema describes T and the output schema describes a single streamed R, with the
+// same rules as NewSchemasFromFunc, so streaming tools are described like request/response ones.
+//
+// Example:
+//
+//	func (s *Search) Stream(ctx context.Context, params SearchParams, yield func(Hit) error) error
+//
+//	input, output, err := funcschema.NewSchemasFromStreamFunc(search.Stream)
+func NewSchemasFromStreamFunc[T any, R any](function func(context.Context, T, func(R) error) error, opts ...Option) (input jobj.Schema, output jobj.Schema, err error) {
+	return schemasFromTypes(reflect.TypeOf((*T)(nil)).Elem(), reflect.TypeOf((*R)(nil)).Elem(), newOptions(opts))
+}
+
+// NewSchemasFromChanFunc creates jobj.Schemas for a streaming handler that returns a channel of
+// results:
+//
+//	func(context.Context, T) (<-chan R, error)
+//
+// As with NewSchemasFromStreamFunc, the output schema describes a single R received from the
+// channel.
+//
+// Example:
+//
+//	func (s *Search) Watch(ctx context.Context, params SearchParams) (<-chan Hit, error)
+//
+//	input, output, err := funcschema.NewSchemasFromChanFunc(search.Watch)
+func NewSchemasFromChanFunc[T any, R any](function func(context.Context, T) (<-chan R, error), opts ...Option) (input jobj.Schema, output jobj.Schema, err error) {
+	return schemasFromTypes(reflect.TypeOf((*T)(nil)).Elem(), reflect.TypeOf((*R)(nil)).Elem(), newOptions(opts))
+}
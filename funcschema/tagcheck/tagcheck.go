@@ -0,0 +1,372 @@
+// Package tagcheck provides an analyzer that checks the struct tags of types whose schemas are
+// generated by funcschema, catching schema-quality bugs at build time instead of in prompts.
+//
+// It reports exported fields without a desc (or description) tag, tag keys that look like
+// misspellings of the keys funcschema reads (e.g. `descr`, `requird`, `exmaple`), and required
+// and deprecated tags whose value is not "true" or "false", which funcschema silently ignores. Handlers
+// passed to NewSchemaFromFunc, SchemasFor and Warmup, which take interface{} and would only
+// reject a bad signature at run time, are checked against func(context.Context, T) (R, error).
+//
+// Types are checked when they reach a funcschema entry point in the package being analyzed:
+// SchemaFromStruct, the NewSchema*FromFunc and Safe*FromFunc families, NewSchemaFromFuncN, the
+// streaming NewSchemasFromStreamFunc and NewSchemasFromChanFunc, SchemasFor, Warmup and
+// toolservice.NewTool. Nested structs, slices, maps and pointers are followed. Run it with
+// go vet:
+//
+//	go install github.com/mhpenta/jobj/cmd/jobjtagcheck@latest
+//	go vet -vettool=$(which jobjtagcheck) ./...
+package tagcheck
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	funcschemaPath  = "github.com/mhpenta/jobj/funcschema"
+	toolservicePath = "github.com/mhpenta/jobj/toolservice"
+)
+
+// Analyzer reports missing and misspelled funcschema struct tags and malformed handlers.
+var Analyzer = &analysis.Analyzer{
+	Name:     "jobjtags",
+	Doc:      "check struct tags of types used with funcschema for missing descriptions and misspelled keys, and handler signatures",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// handlerUse says which parts of a handler func(context.Context, T) (R, error) a funcschema
+// entry point turns into schemas.
+type handlerUse struct {
+	input  bool
+	output bool
+
+	// dynamic is set for entry points that take the handler as an interface{} and check its
+	// signature only at run time.
+	dynamic bool
+
+	// params is set for entry points that describe every parameter after the context, as
+	// NewSchemaFromFuncN does, instead of a handler's T and R.
+	params bool
+}
+
+var handlerFuncs = map[string]map[string]handlerUse{
+	funcschemaPath: {
+		"NewSchemaFromFunc":   {input: true, dynamic: true},
+		"NewSchemaFromFuncV2": {input: true},
+		"NewSchemaFromFuncN":  {params: true},
+		"SafeSchemaFromFunc":  {input: true},
+		"NewSchemasFromFunc":  {input: true, output: true},
+		"SafeSchemasFromFunc": {input: true, output: true},
+		"SchemasFor":          {input: true, output: true, dynamic: true},
+		"Warmup":              {input: true, output: true, dynamic: true},
+		"NewTool":             {input: true, output: true},
+
+		"NewSchemasFromStreamFunc": {input: true, output: true},
+		"NewSchemasFromChanFunc":   {input: true, output: true},
+	},
+	toolservicePath: {
+		"NewTool": {input: true, output: true},
+	},
+}
+
+// tagKeys are the struct tag keys funcschema reads; keys close to one of them but not equal are
+// reported as misspellings.
+var tagKeys = []string{"desc", "description", "required", "default", "example", "examples", "format", "items", "itemsDesc", "minItems", "maxItems", "uniqueItems", "jsonschema", "title", "deprecated", "validate"}
+
+// otherTagKeys are struct tag keys read by other common packages, such as form for HTML forms and
+// db for SQL mappers. They are never reported as misspellings, however close to a funcschema key.
+var otherTagKeys = []string{"json", "xml", "yaml", "toml", "form", "db", "doc", "bson", "mapstructure", "binding"}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	c := &checker{pass: pass, visited: make(map[types.Type]bool)}
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil {
+			return
+		}
+
+		if fn.Pkg().Path() == funcschemaPath && fn.Name() == "SchemaFromStruct" {
+			if instance, ok := pass.TypesInfo.Instances[calleeIdent(call.Fun)]; ok && instance.TypeArgs.Len() == 1 {
+				c.checkType(instance.TypeArgs.At(0))
+			}
+			return
+		}
+
+		use, ok := handlerFuncs[fn.Pkg().Path()][fn.Name()]
+		if !ok {
+			return
+		}
+		for _, arg := range call.Args {
+			if isNamed(pass.TypesInfo.TypeOf(arg), funcschemaPath, "Option") {
+				continue
+			}
+			if use.params {
+				if signature, ok := pass.TypesInfo.TypeOf(arg).Underlying().(*types.Signature); ok {
+					for i := 1; i < signature.Params().Len(); i++ {
+						c.checkType(signature.Params().At(i).Type())
+					}
+				}
+				continue
+			}
+			if use.dynamic && !checkHandler(pass, arg, fn.Name()) {
+				continue
+			}
+			signature, ok := pass.TypesInfo.TypeOf(arg).Underlying().(*types.Signature)
+			if !ok {
+				continue
+			}
+			input, output, ok := handlerTypes(signature)
+			if !ok {
+				continue
+			}
+			if use.input {
+				c.checkType(input)
+			}
+			if use.output {
+				c.checkType(output)
+			}
+		}
+	})
+	return nil, nil
+}
+
+// handlerTypes returns the input type T and output type R of a handler with one of the
+// signatures funcschema describes:
+//
+//	func(context.Context, T) (R, error)
+//	func(context.Context, T) (<-chan R, error)
+//	func(context.Context, T, func(R) error) error
+func handlerTypes(signature *types.Signature) (types.Type, types.Type, bool) {
+	params, results := signature.Params(), signature.Results()
+	switch {
+	case params.Len() == 2 && results.Len() == 2:
+		output := results.At(0).Type()
+		if channel, ok := output.Underlying().(*types.Chan); ok {
+			output = channel.Elem()
+		}
+		return params.At(1).Type(), output, true
+	case params.Len() == 3 && results.Len() == 1:
+		yield, ok := params.At(2).Type().Underlying().(*types.Signature)
+		if !ok || yield.Params().Len() != 1 {
+			return nil, nil, false
+		}
+		return params.At(1).Type(), yield.Params().At(0).Type(), true
+	}
+	return nil, nil, false
+}
+
+// checkHandler reports a handler passed to an interface{} parameter of a funcschema entry point
+// whose type cannot have the signature func(context.Context, T) (R, error), which would otherwise
+// only fail, or panic, at run time. A T that is not a struct is allowed, as the entry points wrap
+// it as the schema's only property. Arguments whose static type is an interface are not checked.
+// It returns whether the handler is well formed.
+func checkHandler(pass *analysis.Pass, arg ast.Expr, callee string) bool {
+	typ := pass.TypesInfo.TypeOf(arg)
+	if typ == nil || types.IsInterface(typ) {
+		return false
+	}
+	signature, ok := typ.Underlying().(*types.Signature)
+	if !ok {
+		pass.Reportf(arg.Pos(), "%s expects a function, got %s", callee, typ)
+		return false
+	}
+
+	params, results := signature.Params(), signature.Results()
+	switch {
+	case params.Len() != 2 || signature.Variadic():
+		pass.Reportf(arg.Pos(), "handler passed to %s has %d parameters; expected func(context.Context, T) (R, error)", callee, params.Len())
+	case !isNamed(params.At(0).Type(), "context", "Context"):
+		pass.Reportf(arg.Pos(), "first parameter of handler passed to %s must be context.Context, got %s", callee, params.At(0).Type())
+	case results.Len() != 2 || !isNamed(results.At(1).Type(), "", "error"):
+		pass.Reportf(arg.Pos(), "handler passed to %s must return (R, error), got %s", callee, results)
+	default:
+		return true
+	}
+	return false
+}
+
+// isNamed reports whether typ is the named type pkg.name; an empty pkg means a predeclared type.
+func isNamed(typ types.Type, pkg string, name string) bool {
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Name() != name {
+		return false
+	}
+	if named.Obj().Pkg() == nil {
+		return pkg == ""
+	}
+	return named.Obj().Pkg().Path() == pkg
+}
+
+// calleeIdent returns the identifier naming the called function, e.g. SchemaFromStruct in
+// funcschema.SchemaFromStruct[T].
+func calleeIdent(fun ast.Expr) *ast.Ident {
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		return fun
+	case *ast.SelectorExpr:
+		return fun.Sel
+	case *ast.IndexExpr:
+		return calleeIdent(fun.X)
+	case *ast.IndexListExpr:
+		return calleeIdent(fun.X)
+	}
+	return nil
+}
+
+type checker struct {
+	pass    *analysis.Pass
+	visited map[types.Type]bool
+}
+
+// checkType checks the struct fields reachable from typ. Each type is checked once per package,
+// so a struct used by several handlers is reported once.
+func (c *checker) checkType(typ types.Type) {
+	if c.visited[typ] {
+		return
+	}
+	c.visited[typ] = true
+
+	switch t := typ.Underlying().(type) {
+	case *types.Pointer:
+		c.checkType(t.Elem())
+	case *types.Slice:
+		c.checkType(t.Elem())
+	case *types.Array:
+		c.checkType(t.Elem())
+	case *types.Map:
+		c.checkType(t.Elem())
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			c.checkField(t.Field(i), t.Tag(i))
+		}
+	}
+}
+
+func (c *checker) checkField(field *types.Var, tag string) {
+	if !field.Exported() || field.Anonymous() {
+		return
+	}
+	structTag := reflect.StructTag(tag)
+	if structTag.Get("json") == "-" {
+		return
+	}
+	c.checkType(field.Type())
+
+	// Fields declared in other packages are reported when those packages are analyzed.
+	if field.Pkg() != c.pass.Pkg {
+		return
+	}
+
+	misspelledDesc := false
+	for _, key := range structTagKeys(tag) {
+		if intended := misspelling(key); intended != "" {
+			c.pass.Reportf(field.Pos(), "field %s has tag key %q; did you mean %q?", field.Name(), key, intended)
+			misspelledDesc = misspelledDesc || intended != "required"
+		}
+	}
+
+	_, hasDesc := structTag.Lookup("desc")
+	_, hasDescription := structTag.Lookup("description")
+	// jsonschema:"...,description=..." also describes the field
+	hasDescription = hasDescription || strings.Contains(structTag.Get("jsonschema"), "description=")
+	if !hasDesc && !hasDescription && !misspelledDesc {
+		c.pass.Reportf(field.Pos(), "exported field %s has no desc tag, so its schema property has no description", field.Name())
+	}
+
+	if required, ok := structTag.Lookup("required"); ok && required != "true" && required != "false" {
+		c.pass.Reportf(field.Pos(), "field %s has required:%q; funcschema only treats \"true\" as required", field.Name(), required)
+	}
+	if deprecated, ok := structTag.Lookup("deprecated"); ok && deprecated != "true" && deprecated != "false" {
+		c.pass.Reportf(field.Pos(), "field %s has deprecated:%q; funcschema only treats \"true\" as deprecated", field.Name(), deprecated)
+	}
+}
+
+// misspelling returns the funcschema tag key that key is probably a misspelling of, or "".
+func misspelling(key string) string {
+	for _, known := range append(tagKeys, otherTagKeys...) {
+		if key == known {
+			return ""
+		}
+	}
+	for _, known := range tagKeys {
+		if editDistance(key, known) <= 2 {
+			return known
+		}
+	}
+	return ""
+}
+
+// structTagKeys returns the keys of a struct tag in the conventional key:"value" format,
+// parsed as reflect.StructTag.Lookup does.
+func structTagKeys(tag string) []string {
+	var keys []string
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		if _, err := strconv.Unquote(tag[:i+1]); err != nil {
+			break
+		}
+		keys = append(keys, key)
+		tag = tag[i+1:]
+	}
+	return keys
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
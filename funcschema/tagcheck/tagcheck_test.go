@@ -0,0 +1,37 @@
+package tagcheck
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestMisspelling(t *testing.T) {
+	tests := map[string]string{
+		"desc":        "",
+		"description": "",
+		"required":    "",
+		"json":        "",
+		"yaml":        "",
+		"form":        "",
+		"doc":         "",
+		"db":          "",
+		"xml":         "",
+		"validate":    "",
+		"valdiate":    "validate",
+		"descr":       "desc",
+		"dsec":        "desc",
+		"requird":     "required",
+		"require":     "required",
+		"descripton":  "description",
+	}
+	for key, expected := range tests {
+		if got := misspelling(key); got != expected {
+			t.Errorf("misspelling(%q) = %q, want %q", key, got, expected)
+		}
+	}
+}
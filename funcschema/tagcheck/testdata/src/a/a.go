@@ -0,0 +1,70 @@
+package a
+
+import (
+	"context"
+
+	"github.com/mhpenta/jobj/funcschema"
+)
+
+type SearchParams struct {
+	Query    string   `json:"query" desc:"Search query" required:"true"`
+	Limit    int      `json:"limit"`                                        // want `exported field Limit has no desc tag`
+	Language string   `json:"language" descr:"Language code"`               // want `field Language has tag key "descr"; did you mean "desc"\?`
+	Strict   bool     `json:"strict" desc:"Exact match" requird:"true"`     // want `field Strict has tag key "requird"; did you mean "required"\?`
+	Sort     string   `json:"sort" description:"Sort order" required:"yes"` // want `field Sort has required:"yes"`
+	Filters  []Filter `json:"filters" desc:"Filters to apply"`
+	Page     int      `json:"page" desc:"Result page" defualt:"1"`             // want `field Page has tag key "defualt"; did you mean "default"\?`
+	Region   string   `json:"region" desc:"Region code" exmaple:"eu"`          // want `field Region has tag key "exmaple"; did you mean "example"\?`
+	Contact  string   `json:"contact" desc:"Contact address" fromat:"email"`   // want `field Contact has tag key "fromat"; did you mean "format"\?`
+	Values   []any    `json:"values" desc:"Cell values" itmes:"number|string"` // want `field Values has tag key "itmes"; did you mean "items"\?`
+	Cursor   string   `json:"cursor" jsonschema:"title=Cursor,description=Page cursor"`
+	Owner    string   `json:"owner" desc:"Owner name" form:"owner" db:"owner" doc:"owner" yaml:"owner" xml:"owner" validate:"required"`
+	Internal string   `json:"-"`
+	private  string
+}
+
+type Filter struct {
+	Field string `json:"field"` // want `exported field Field has no desc tag`
+	Value string `json:"value" desc:"Value to match"`
+}
+
+type SearchResult struct {
+	Count int `json:"count"` // want `exported field Count has no desc tag`
+}
+
+type Unused struct {
+	Missing string
+}
+
+type Config struct {
+	Name string // want `exported field Name has no desc tag`
+}
+
+type Transfer struct {
+	IBAN string `json:"iban"` // want `exported field IBAN has no desc tag`
+}
+
+func Send(ctx context.Context, from Transfer, cents int64) (string, error) {
+	return "", nil
+}
+
+type WarmInput struct {
+	ID int `json:"id"` // want `exported field ID has no desc tag`
+}
+
+func Search(ctx context.Context, params SearchParams) (SearchResult, error) {
+	return SearchResult{}, nil
+}
+
+func Warm(ctx context.Context, input *WarmInput) (string, error) {
+	return "", nil
+}
+
+func Register() {
+	_, _, _ = funcschema.NewSchemasFromFunc(Search)
+	_, _ = funcschema.NewSchemaFromFuncV2(Search)
+	_, _ = funcschema.SchemaFromStruct[Config]()
+	_, _ = funcschema.Warmup(Warm)
+	_, _ = funcschema.SchemasFor(Search, funcschema.WithStrict())
+	_, _ = funcschema.NewSchemaFromFuncN(Send, []string{"from", "cents"})
+}
@@ -0,0 +1,60 @@
+package a
+
+import (
+	"context"
+
+	"github.com/mhpenta/jobj/funcschema"
+)
+
+type Params struct {
+	Query  string `json:"query" desc:"Search query"`
+	Legacy string `json:"legacy" desc:"Old search query" deprecated:"yes"` // want `field Legacy has deprecated:"yes"`
+}
+
+func good(ctx context.Context, params Params) (string, error)         { return "", nil }
+func goodPointer(ctx context.Context, params *Params) (string, error) { return "", nil }
+func noContext(params Params) (string, error)                         { return "", nil }
+func wrongContext(ctx string, params Params) (string, error)          { return "", nil }
+func notStruct(ctx context.Context, query string) (string, error)     { return "", nil }
+func noError(ctx context.Context, params Params) string               { return "" }
+func errorNotLast(ctx context.Context, params Params) (error, string) {
+	return nil, ""
+}
+
+type StreamParams struct {
+	Topic string `json:"topic"` // want `exported field Topic has no desc tag`
+}
+
+type StreamEvent struct {
+	Kind string `json:"kind"` // want `exported field Kind has no desc tag`
+}
+
+type ChanEvent struct {
+	Seq int `json:"seq"` // want `exported field Seq has no desc tag`
+}
+
+func stream(ctx context.Context, params StreamParams, yield func(StreamEvent) error) error {
+	return nil
+}
+
+func watch(ctx context.Context, params StreamParams) (<-chan ChanEvent, error) { return nil, nil }
+
+type tool struct{}
+
+func (tool) Run(ctx context.Context, params Params) (string, error) { return "", nil }
+
+func RegisterHandlers(dynamic interface{}) {
+	_, _ = funcschema.NewSchemaFromFunc(good)
+	_, _ = funcschema.NewSchemaFromFunc(goodPointer)
+	_, _ = funcschema.NewSchemaFromFunc(tool{}.Run)
+	_, _ = funcschema.NewSchemaFromFunc(dynamic)
+	_, _ = funcschema.NewSchemaFromFunc(noContext)    // want `handler passed to NewSchemaFromFunc has 1 parameters`
+	_, _ = funcschema.NewSchemaFromFunc(wrongContext) // want `first parameter of handler passed to NewSchemaFromFunc must be context.Context, got string`
+	_, _ = funcschema.NewSchemaFromFunc(notStruct)
+	_, _ = funcschema.SchemasFor(notStruct)
+	_, _ = funcschema.NewSchemaFromFunc(noError) // want `handler passed to NewSchemaFromFunc must return \(R, error\), got \(string\)`
+	_, _ = funcschema.SchemasFor(errorNotLast)   // want `handler passed to SchemasFor must return \(R, error\)`
+	_, _ = funcschema.Warmup(good, Params{})     // want `Warmup expects a function, got a.Params`
+	_, _, _ = funcschema.NewSchemasFromStreamFunc(stream)
+	_, _, _ = funcschema.NewSchemasFromChanFunc(watch)
+}
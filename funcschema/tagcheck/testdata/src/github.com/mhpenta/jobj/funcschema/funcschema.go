@@ -0,0 +1,38 @@
+// Package funcschema is a stub of the real package's entry points for the analyzer tests.
+package funcschema
+
+import "context"
+
+func SchemaFromStruct[T any]() (interface{}, error) { return nil, nil }
+
+func NewSchemaFromFuncV2[T any, R any](function func(context.Context, T) (R, error)) (interface{}, error) {
+	return nil, nil
+}
+
+func NewSchemasFromFunc[T any, R any](function func(context.Context, T) (R, error)) (interface{}, interface{}, error) {
+	return nil, nil, nil
+}
+
+func NewSchemasFromStreamFunc[T any, R any](function func(context.Context, T, func(R) error) error) (interface{}, interface{}, error) {
+	return nil, nil, nil
+}
+
+func NewSchemasFromChanFunc[T any, R any](function func(context.Context, T) (<-chan R, error)) (interface{}, interface{}, error) {
+	return nil, nil, nil
+}
+
+func Warmup(functions ...interface{}) (interface{}, error) { return nil, nil }
+
+func NewSchemaFromFunc(function interface{}, opts ...Option) (interface{}, error) { return nil, nil }
+
+func SchemasFor(function interface{}, opts ...Option) (interface{}, error) { return nil, nil }
+
+func NewSchemaFromFuncN(function interface{}, names []string, opts ...Option) (interface{}, error) {
+	return nil, nil
+}
+
+type Option func(*options)
+
+type options struct{}
+
+func WithStrict() Option { return nil }
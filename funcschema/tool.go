@@ -0,0 +1,150 @@
+package funcschema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/mhpenta/jobj"
+	"github.com/mhpenta/jobj/safeunmarshal"
+)
+
+// Tool bundles a handler with what a model needs to call it: its name, description and
+// schemas, and Execute, which takes the model's JSON arguments and returns the JSON result.
+// Schemas are shared with SchemasFor's cache and must be treated as read-only. Create one with
+// NewTool, or with ToolsFromStruct for the methods of a service.
+type Tool struct {
+	Name        string
+	Description string
+	Schemas     *ToolSchemas
+
+	function reflect.Value
+	options  options
+}
+
+// NewTool wraps a handler with the signature func(context.Context, T) (R, error) as a Tool. Its
+// schemas come from SchemasFor with opts, so they are shared with Warmup's cache.
+//
+// Example:
+//
+//	tool, err := funcschema.NewTool("search", "Search the catalog", catalog.Search)
+//	result, err := tool.Execute(ctx, call.Arguments)
+func NewTool[T any, R any](name string, description string, function func(context.Context, T) (R, error), opts ...Option) (Tool, error) {
+	if function == nil {
+		return Tool{}, fmt.Errorf("tool %q: received nil function", name)
+	}
+	return newTool(name, description, reflect.ValueOf(function), opts)
+}
+
+// newTool returns a Tool calling function, a value of a func with the handler signature.
+func newTool(name string, description string, function reflect.Value, opts []Option) (Tool, error) {
+	if name == "" {
+		return Tool{}, fmt.Errorf("tool name must not be empty")
+	}
+	schemas, err := SchemasFor(function.Interface(), opts...)
+	if err != nil {
+		return Tool{}, fmt.Errorf("tool %q: %w", name, err)
+	}
+	return Tool{Name: name, Description: description, Schemas: schemas, function: function, options: newOptions(opts)}, nil
+}
+
+// Execute calls the tool with input, the JSON arguments produced by the model, and returns the
+// handler's result as JSON. Input is checked and decoded by Decode. Errors returned by the
+// handler are passed through unchanged.
+func (t Tool) Execute(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+	params, err := t.Decode(input)
+	if err != nil {
+		return nil, err
+	}
+	result, err := t.Call(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
+
+// Decode turns input, the JSON arguments produced by the model, into the handler's parameter.
+// Arguments wrapped in code fences or slightly malformed are repaired first, and omitted fields
+// take their schema defaults, as with safeunmarshal.ToWithDefaults. The result is then checked
+// with ValidateJSON against the input schema; mismatches are returned as a
+// jobj.ValidationErrors, which can be sent back to the model to correct its call. Properties
+// named by WithFieldNamer or WithSnakeCaseNames are decoded into their Go fields, and parameters
+// that are not structs are taken from the property wrapping them; see WithWrappedParamName.
+func (t Tool) Decode(input json.RawMessage) (interface{}, error) {
+	if !t.function.IsValid() {
+		return nil, fmt.Errorf("tool %q has no handler; create it with NewTool", t.Name)
+	}
+	filled, err := safeunmarshal.RepairWithDefaults(input, &t.Schemas.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode input: %w", err)
+	}
+	errs, err := t.Schemas.Input.ValidateJSON(filled)
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid arguments: %w", jobj.ValidationErrors(errs))
+	}
+
+	paramType := t.function.Type().In(1)
+	if derefType(paramType).Kind() != reflect.Struct {
+		if filled, err = unwrapParam(filled, t.Schemas.Input.Fields[0].ValueName); err != nil {
+			return nil, fmt.Errorf("failed to decode input: %w", err)
+		}
+	}
+	if t.options.namer != nil {
+		if filled, err = withGoPropertyNames(filled, paramType, t.options); err != nil {
+			return nil, fmt.Errorf("failed to decode input: %w", err)
+		}
+	}
+	param := reflect.New(derefType(paramType))
+	decoder := json.NewDecoder(bytes.NewReader(filled))
+	decoder.UseNumber()
+	if err := decoder.Decode(param.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode input: %w", err)
+	}
+	if paramType.Kind() != reflect.Ptr {
+		return param.Elem().Interface(), nil
+	}
+	return param.Interface(), nil
+}
+
+// Call calls the handler with params, a value returned by Decode, and returns its result.
+func (t Tool) Call(ctx context.Context, params interface{}) (interface{}, error) {
+	if !t.function.IsValid() {
+		return nil, fmt.Errorf("tool %q has no handler; create it with NewTool", t.Name)
+	}
+	results := t.function.Call([]reflect.Value{reflect.ValueOf(&ctx).Elem(), reflect.ValueOf(params)})
+	if err, _ := results[1].Interface().(error); err != nil {
+		return nil, err
+	}
+	return results[0].Interface(), nil
+}
+
+// withGoPropertyNames returns data, JSON arguments for paramType, with the properties o names
+// differently from encoding/json renamed to their Go field names; see goPropertyNames.
+func withGoPropertyNames(data []byte, paramType reflect.Type, o options) ([]byte, error) {
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	goPropertyNames(document, paramType, o)
+	return json.Marshal(document)
+}
+
+// unwrapParam returns the property name of data, the JSON arguments of a handler whose parameter
+// is wrapped as that property, or null when it is missing.
+func unwrapParam(data []byte, name string) ([]byte, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	if value, ok := object[name]; ok {
+		return value, nil
+	}
+	return []byte("null"), nil
+}
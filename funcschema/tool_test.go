@@ -0,0 +1,78 @@
+package funcschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mhpenta/jobj"
+	"github.com/stretchr/testify/assert"
+)
+
+type toolInput struct {
+	City  string `json:"city" desc:"City to look up" required:"true"`
+	Units string `json:"units" desc:"Temperature units" default:"C"`
+}
+
+func TestNewTool(t *testing.T) {
+	forecast := func(ctx context.Context, input toolInput) (warmupOutput, error) {
+		if input.City == "" {
+			return warmupOutput{}, errors.New("city is required")
+		}
+		return warmupOutput{Forecast: "21" + input.Units + " in " + input.City}, nil
+	}
+
+	tool, err := NewTool("forecast", "Weather forecast for a city", forecast)
+	assert.NoError(t, err)
+	assert.Equal(t, "forecast", tool.Name)
+	assert.Equal(t, []string{"city"}, tool.Schemas.Input.RequiredFields())
+	assert.Equal(t, "forecast", tool.Schemas.Output.Fields[0].ValueName)
+
+	// Arguments are repaired and omitted fields take their defaults
+	result, err := tool.Execute(context.Background(), json.RawMessage("```json\n{\"city\": \"Lisbon\",}\n```"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"forecast":"21C in Lisbon"}`, string(result))
+
+	// Invalid arguments are reported without calling the handler, whose errors pass through
+	_, err = tool.Execute(context.Background(), json.RawMessage(`{}`))
+	var invalid jobj.ValidationErrors
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "/city", invalid[0].Pointer)
+	_, err = tool.Execute(context.Background(), json.RawMessage(`{"city":""}`))
+	assert.EqualError(t, err, "city is required")
+
+	params, err := tool.Decode(json.RawMessage(`{"city":"Lisbon","units":"F"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, toolInput{City: "Lisbon", Units: "F"}, params)
+	_, err = tool.Execute(context.Background(), json.RawMessage(``))
+	assert.ErrorContains(t, err, "failed to decode input")
+
+	_, err = NewTool("", "No name", forecast)
+	assert.Error(t, err)
+	_, err = Tool{Name: "empty"}.Execute(context.Background(), json.RawMessage(`{}`))
+	assert.Error(t, err)
+}
+
+func TestNewToolWrappedParam(t *testing.T) {
+	quote := func(ctx context.Context, tickers []string) (map[string]int, error) {
+		prices := make(map[string]int, len(tickers))
+		for i, ticker := range tickers {
+			prices[ticker] = i + 1
+		}
+		return prices, nil
+	}
+
+	tool, err := NewTool("quote", "Latest prices", quote, WithWrappedParamName("tickers"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tickers"}, tool.Schemas.Input.RequiredFields())
+
+	// The parameter is taken from the property wrapping it
+	result, err := tool.Execute(context.Background(), json.RawMessage(`{"tickers":["AAPL","MSFT"]}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"AAPL":1,"MSFT":2}`, string(result))
+
+	_, err = tool.Execute(context.Background(), json.RawMessage(`["AAPL"]`))
+	var invalid jobj.ValidationErrors
+	assert.True(t, errors.As(err, &invalid))
+}
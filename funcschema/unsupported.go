@@ -0,0 +1,93 @@
+package funcschema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mhpenta/jobj"
+)
+
+// ErrUnsupportedType is returned by schema generation with WithStrict for structs with fields
+// whose Go types have no schema equivalent.
+var ErrUnsupportedType = errors.New("unsupported field type")
+
+// WithStrict makes schema generation fail with an error wrapping ErrUnsupportedType when a
+// struct has exported fields of types with no schema equivalent, such as funcs, channels or
+// complex numbers, rather than logging a warning and leaving them out of the schema. The error
+// lists the offending fields by Go path, so a tool missing a parameter is noticed at startup
+// rather than when the model never fills it.
+//
+// Example:
+//
+//	type Params struct {
+//	    Query    string       `json:"query" desc:"Search query"`
+//	    Callback func(string) `json:"callback" desc:"Called per result"`
+//	}
+//
+//	_, err := funcschema.SchemaFromStruct[Params](funcschema.WithStrict()) // unsupported field type: Params.Callback
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// checkSupported returns an error wrapping ErrUnsupportedType if o is strict and exported fields
+// of struct t, at any depth, are missing from the fields generated for it.
+func checkSupported(t reflect.Type, fields []*jobj.Field, o options) error {
+	if !o.strict {
+		return nil
+	}
+	if unsupported := unsupportedFields(t, fields, t.Name(), o, nil); len(unsupported) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// checkSupportedRoot is checkSupported for the root field of a non-struct return type.
+func checkSupportedRoot(t reflect.Type, root *jobj.Field, o options) error {
+	if !o.strict {
+		return nil
+	}
+	elem, nested, _ := nestedStruct(t, root)
+	if elem.Kind() != reflect.Struct || nested.SubFields == nil {
+		return nil
+	}
+	if unsupported := unsupportedFields(elem, nested.SubFields, elem.Name(), o, nil); len(unsupported) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// unsupportedFields appends the Go paths of the exported fields of struct t, below goPath, that
+// generated no field among fields, and of those nested below the fields that did. Generation
+// leaves out exactly the fields of unsupported types, including slices and maps of them.
+func unsupportedFields(t reflect.Type, fields []*jobj.Field, goPath string, o options, unsupported []string) []string {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		name := propertyName(structField, o)
+		if !structField.IsExported() || name == "-" {
+			continue
+		}
+		fieldGoPath := goPath + "." + structField.Name
+
+		var field *jobj.Field
+		for _, generated := range fields {
+			if generated.ValueName == name {
+				field = generated
+				break
+			}
+		}
+		if field == nil {
+			unsupported = append(unsupported, fieldGoPath)
+			continue
+		}
+
+		elem, nested, _ := nestedStruct(structField.Type, field)
+		if elem.Kind() == reflect.Struct && nested.SubFields != nil {
+			unsupported = unsupportedFields(elem, nested.SubFields, fieldGoPath, o, unsupported)
+		}
+	}
+	return unsupported
+}
@@ -0,0 +1,155 @@
+package funcschema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mhpenta/jobj"
+)
+
+// WithValidateTags returns an Option reading the validate tags of github.com/go-playground/validator
+// during schema generation, so constraints already declared for validator need not be repeated in
+// funcschema tags. Without it validate tags are ignored. The rules map to JSON Schema keywords:
+//
+//	required           required
+//	min=N, gte=N       minLength on strings, minimum on numbers, minItems on slices
+//	max=N, lte=N       maxLength on strings, maximum on numbers, maxItems on slices
+//	len=N              both of the above
+//	oneof=a b 'c d'    an enum of the listed values
+//	email, url, uri    format email or uri; likewise uuid, hostname, ipv4 and ipv6
+//
+// Other rules, alternatives joined by "|" and the rules after dive are ignored. funcschema tags
+// on the same field take precedence.
+//
+// Example:
+//
+//	type Params struct {
+//	    Query string `json:"query" desc:"Search query" validate:"required,min=1,max=200"`
+//	    Sort  string `json:"sort" desc:"Sort order" validate:"oneof=relevance date"`
+//	}
+//
+//	schema, err := funcschema.SchemaFromStruct[Params](funcschema.WithValidateTags())
+func WithValidateTags() Option {
+	return func(o *options) {
+		o.validateTags = true
+	}
+}
+
+// validateFormats maps validator rules to the string formats they check.
+var validateFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+}
+
+// applyValidateTag maps the rules of field's validate tag to keywords of jobjField, if o reads
+// validate tags.
+func applyValidateTag(field reflect.StructField, jobjField *jobj.Field, o options) {
+	if !o.validateTags {
+		return
+	}
+	tag, ok := field.Tag.Lookup("validate")
+	if !ok {
+		return
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "dive" {
+			// The remaining rules apply to the items, not the field
+			return
+		}
+		if strings.Contains(rule, "|") {
+			continue
+		}
+
+		switch name {
+		case "required":
+			jobjField.Required()
+		case "min", "gte":
+			applyValidateBound(field, jobjField, rule, param, true)
+		case "max", "lte":
+			applyValidateBound(field, jobjField, rule, param, false)
+		case "len":
+			applyValidateBound(field, jobjField, rule, param, true)
+			applyValidateBound(field, jobjField, rule, param, false)
+		case "oneof":
+			values := splitOneOf(param)
+			enums := make([]jobj.ConstDescription, 0, len(values))
+			for _, value := range values {
+				constValue, err := tagValue(jobjField, value)
+				if err != nil {
+					logWarn("Invalid oneof value in validate tag", "field", field.Name, "value", value, "error", err)
+					continue
+				}
+				enums = append(enums, jobj.ConstDescription{Const: constValue})
+			}
+			if len(enums) > 0 {
+				jobjField.ValueAnyOf = enums
+			}
+		default:
+			if format, ok := validateFormats[name]; ok && jobjField.ValueType == jobj.TypeString {
+				jobjField.Format(format)
+			}
+		}
+	}
+}
+
+// applyValidateBound applies the lower or upper bound of a min, max or len rule, which validator
+// applies to the length of strings and slices and to the value of numbers.
+func applyValidateBound(field reflect.StructField, jobjField *jobj.Field, rule string, param string, lower bool) {
+	switch jobjField.ValueType {
+	case jobj.TypeString, jobj.TypeArray:
+		n, err := strconv.Atoi(param)
+		if err != nil || n < 0 {
+			logWarn("Invalid length in validate tag", "field", field.Name, "rule", rule)
+			return
+		}
+		switch {
+		case jobjField.ValueType == jobj.TypeArray && lower:
+			jobjField.MinItems(n)
+		case jobjField.ValueType == jobj.TypeArray:
+			jobjField.MaxItems(n)
+		case lower:
+			jobjField.MinLength(n)
+		default:
+			jobjField.MaxLength(n)
+		}
+	case jobj.TypeNumber, jobj.TypeInteger:
+		value, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			logWarn("Invalid bound in validate tag", "field", field.Name, "rule", rule)
+			return
+		}
+		if lower {
+			jobjField.Minimum(value)
+		} else {
+			jobjField.Maximum(value)
+		}
+	}
+}
+
+// splitOneOf splits the parameter of a oneof rule into its values, which are separated by spaces
+// and may be quoted with single quotes to contain spaces.
+func splitOneOf(param string) []string {
+	var values []string
+	for param = strings.TrimSpace(param); param != ""; param = strings.TrimSpace(param) {
+		if param[0] == '\'' {
+			if end := strings.IndexByte(param[1:], '\''); end >= 0 {
+				values = append(values, param[1:end+1])
+				param = param[end+2:]
+				continue
+			}
+		}
+		value, rest, _ := strings.Cut(param, " ")
+		values = append(values, value)
+		param = rest
+	}
+	return values
+}
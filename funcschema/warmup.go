@@ -0,0 +1,232 @@
+package funcschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mhpenta/jobj"
+)
+
+// ToolSchemas holds everything generated for one handler: the input and output schemas and
+// their provider exports. Values returned by SchemasFor are shared between callers and must be
+// treated as read-only.
+type ToolSchemas struct {
+	Input  jobj.Schema
+	Output jobj.Schema
+
+	InputProperties  map[string]interface{} // GetPropertiesMap(Input), as sent in tool definitions
+	OutputProperties map[string]interface{} // GetPropertiesMap(Output)
+	InputStrict      map[string]interface{} // Input.Strict(), for OpenAI strict mode
+	InputJSON        string                 // Input.GetSchemaString(), the schema ValidateJSON checks against
+
+	providers *sync.Map // jobj.Provider -> map[string]interface{}, filled by ForProvider
+}
+
+// ForProvider returns the input schema as provider receives it, converted with Provider.Apply on
+// first use and cached with the other schemas afterwards; converting is by far the most
+// expensive export, see BenchmarkCatalogExport. The loss report is not kept, call Apply on Input
+// to inspect it. The returned map is shared between callers and must be treated as read-only.
+func (s *ToolSchemas) ForProvider(provider jobj.Provider) (map[string]interface{}, error) {
+	if s.providers == nil {
+		document, _, err := provider.Apply(&s.Input)
+		return document, err
+	}
+	if cached, ok := s.providers.Load(provider); ok {
+		return cached.(map[string]interface{}), nil
+	}
+	document, _, err := provider.Apply(&s.Input)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := s.providers.LoadOrStore(provider, document)
+	return actual.(map[string]interface{}), nil
+}
+
+// ToolTiming is how long generating the schemas for one handler took during Warmup.
+type ToolTiming struct {
+	Tool     string // The handler's function type, e.g. "func(context.Context, main.Params) (main.Result, error)"
+	Duration time.Duration
+}
+
+// WarmupStats summarizes a call to Warmup.
+type WarmupStats struct {
+	Tools     int           // Number of handlers given
+	Generated int           // Handlers whose schemas were generated, rather than already cached
+	Elapsed   time.Duration // Wall-clock time of the whole warm-up
+	Total     time.Duration // Sum of the per-handler generation times
+	Timings   []ToolTiming  // Per-handler generation times, slowest first; cached handlers are omitted
+}
+
+// CacheOptions tunes what is generated and cached for each handler; see SetCacheOptions.
+type CacheOptions struct {
+	// Providers lists the providers whose ToolSchemas.ForProvider conversion is computed together
+	// with the schemas, e.g. during Warmup, rather than on first use. Converting costs about as
+	// much as generating and serializing the schemas together, so servers that answer every
+	// listing for a known set of providers should name them here.
+	Providers []jobj.Provider
+}
+
+// cacheOptions holds the options set by SetCacheOptions.
+var cacheOptions atomic.Pointer[CacheOptions]
+
+// SetCacheOptions sets the options applied to handlers whose schemas are generated afterwards.
+// Call it at startup, before Warmup; schemas already cached keep the options they were
+// generated with. It returns an error for unknown providers.
+//
+// Example:
+//
+//	err := funcschema.SetCacheOptions(funcschema.CacheOptions{
+//	    Providers: []jobj.Provider{jobj.ProviderOpenAIStrict, jobj.ProviderAnthropic},
+//	})
+func SetCacheOptions(options CacheOptions) error {
+	known := jobj.Providers()
+	for _, provider := range options.Providers {
+		if !slices.Contains(known, provider) {
+			return fmt.Errorf("unknown provider %q", provider)
+		}
+	}
+	options.Providers = slices.Clone(options.Providers)
+	cacheOptions.Store(&options)
+	return nil
+}
+
+// toolCache maps a toolKey to its *ToolSchemas. Schemas depend only on the parameter and
+// return types and the options, so handlers sharing a signature share an entry.
+var toolCache sync.Map
+
+// toolKey identifies the schemas of a handler's function type generated with a call's options.
+type toolKey struct {
+	funcType reflect.Type
+	options  options
+}
+
+// SchemasFor returns the schemas for a handler with the signature
+//
+//	func(context.Context, T) (R, error)
+//
+// generating them with the same rules and opts as NewSchemasFromFunc on first use and caching
+// them for the life of the process, or until a setting that affects generation changes. It is
+// safe for concurrent use.
+func SchemasFor(function interface{}, opts ...Option) (*ToolSchemas, error) {
+	schemas, _, err := schemasFor(function, newOptions(opts))
+	return schemas, err
+}
+
+// Warmup generates and caches the schemas of every handler concurrently, so that the first
+// request to an agent server does not pay for reflection. Call it at process start with the
+// same functions later passed to SchemasFor:
+//
+//	stats, err := funcschema.Warmup(searchTool.Search, calendarTool.Create)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	log.Printf("warmed %d tools in %s", stats.Tools, stats.Elapsed)
+//
+// Errors for individual handlers are joined; schemas for the other handlers are still cached.
+// Schemas are generated with the default options; handlers described with Options are cached
+// by their first SchemasFor call instead.
+func Warmup(functions ...interface{}) (WarmupStats, error) {
+	stats := WarmupStats{Tools: len(functions)}
+	start := time.Now()
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		errs   []error
+		timing = make([]ToolTiming, 0, len(functions))
+	)
+	for _, function := range functions {
+		wg.Add(1)
+		go func(function interface{}) {
+			defer wg.Done()
+			toolStart := time.Now()
+			_, generated, err := schemasFor(function, options{})
+			duration := time.Since(toolStart)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			if generated {
+				timing = append(timing, ToolTiming{Tool: reflect.TypeOf(function).String(), Duration: duration})
+			}
+		}(function)
+	}
+	wg.Wait()
+
+	sort.SliceStable(timing, func(i, j int) bool {
+		return timing[i].Duration > timing[j].Duration
+	})
+	for _, t := range timing {
+		stats.Total += t.Duration
+	}
+	stats.Generated = len(timing)
+	stats.Timings = timing
+	stats.Elapsed = time.Since(start)
+	return stats, errors.Join(errs...)
+}
+
+// schemasFor implements SchemasFor, also reporting whether the schemas were generated by this call.
+func schemasFor(function interface{}, o options) (*ToolSchemas, bool, error) {
+	funcType := reflect.TypeOf(function)
+	if err := checkHandlerType(funcType); err != nil {
+		return nil, false, err
+	}
+	key := toolKey{funcType: funcType, options: o}
+	if cached, ok := toolCache.Load(key); ok && !o.noCache {
+		return cached.(*ToolSchemas), false, nil
+	}
+
+	input, output, err := schemasFromTypes(funcType.In(1), funcType.Out(0), o)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", funcType, err)
+	}
+	schemas := &ToolSchemas{
+		Input:            input,
+		Output:           output,
+		InputProperties:  GetPropertiesMap(input),
+		OutputProperties: GetPropertiesMap(output),
+		InputStrict:      input.Strict(),
+		InputJSON:        input.GetSchemaString(),
+		providers:        &sync.Map{},
+	}
+	if options := cacheOptions.Load(); options != nil {
+		for _, provider := range options.Providers {
+			if _, err := schemas.ForProvider(provider); err != nil {
+				return nil, false, fmt.Errorf("%s: %w", funcType, err)
+			}
+		}
+	}
+
+	if o.noCache {
+		return schemas, true, nil
+	}
+	// Another goroutine may have generated the same type meanwhile; keep the first so every
+	// caller sees the same value.
+	actual, loaded := toolCache.LoadOrStore(key, schemas)
+	return actual.(*ToolSchemas), !loaded, nil
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+func checkHandlerType(funcType reflect.Type) error {
+	if funcType == nil || funcType.Kind() != reflect.Func {
+		return fmt.Errorf("handler must be a function, got %v", funcType)
+	}
+	if funcType.NumIn() != 2 || funcType.In(0) != contextType || funcType.NumOut() != 2 || funcType.Out(1) != errorType {
+		return fmt.Errorf("handler %s must have the signature func(context.Context, T) (R, error)", funcType)
+	}
+	return nil
+}
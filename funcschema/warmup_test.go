@@ -0,0 +1,124 @@
+package funcschema
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mhpenta/jobj"
+	"github.com/stretchr/testify/assert"
+)
+
+type warmupInput struct {
+	City string `json:"city" desc:"City to look up" required:"true"`
+}
+
+type warmupOutput struct {
+	Forecast string `json:"forecast" desc:"Forecast text"`
+}
+
+type warmupOtherInput struct {
+	Query string `json:"query" desc:"Search query" required:"true"`
+}
+
+func TestWarmup(t *testing.T) {
+	weather := func(ctx context.Context, input warmupInput) (warmupOutput, error) { return warmupOutput{}, nil }
+	search := func(ctx context.Context, input *warmupOtherInput) ([]string, error) { return nil, nil }
+
+	stats, err := Warmup(weather, search)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Tools)
+	assert.LessOrEqual(t, stats.Generated, 2)
+	assert.Len(t, stats.Timings, stats.Generated)
+
+	schemas, err := SchemasFor(weather)
+	assert.NoError(t, err)
+	assert.Equal(t, "warmupInput", schemas.Input.Name)
+	assert.Equal(t, "warmupOutput", schemas.Output.Name)
+	assert.Contains(t, schemas.InputProperties["properties"], "city")
+	assert.Equal(t, false, schemas.InputStrict["additionalProperties"])
+	assert.Contains(t, schemas.InputJSON, `"city"`)
+
+	// A second warm-up finds everything cached.
+	stats, err = Warmup(weather, search)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stats.Generated)
+	assert.Empty(t, stats.Timings)
+
+	again, err := SchemasFor(weather)
+	assert.NoError(t, err)
+	assert.Same(t, schemas, again)
+}
+
+func TestWarmup_Errors(t *testing.T) {
+	valid := func(ctx context.Context, input warmupInput) (warmupOutput, error) { return warmupOutput{}, nil }
+	unsupported := func(ctx context.Context, input func()) (string, error) { return "", nil }
+
+	stats, err := Warmup(valid, unsupported, "not a function", func(input warmupInput) error { return nil })
+	assert.Error(t, err)
+	assert.Equal(t, 4, stats.Tools)
+	assert.True(t, strings.Contains(err.Error(), "second parameter: unsupported type func()"))
+	assert.True(t, strings.Contains(err.Error(), "handler must be a function"))
+	assert.True(t, strings.Contains(err.Error(), "must have the signature"))
+
+	_, err = SchemasFor(valid)
+	assert.NoError(t, err)
+}
+
+func TestSchemasFor_Concurrent(t *testing.T) {
+	type concurrentInput struct {
+		ID int `json:"id" required:"true"`
+	}
+	handler := func(ctx context.Context, input concurrentInput) (warmupOutput, error) { return warmupOutput{}, nil }
+
+	results := make([]*ToolSchemas, 16)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = SchemasFor(handler)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		assert.Same(t, results[0], result)
+	}
+}
+
+type providerInput struct {
+	Units string `json:"units" desc:"Temperature units"`
+}
+
+func TestForProvider(t *testing.T) {
+	assert.Error(t, SetCacheOptions(CacheOptions{Providers: []jobj.Provider{"unknown"}}))
+	assert.NoError(t, SetCacheOptions(CacheOptions{Providers: []jobj.Provider{jobj.ProviderOpenAIStrict}}))
+	defer SetCacheOptions(CacheOptions{})
+
+	forecast := func(ctx context.Context, input providerInput) (warmupOutput, error) { return warmupOutput{}, nil }
+	schemas, err := SchemasFor(forecast)
+	assert.NoError(t, err)
+
+	// Computed with the schemas, so the same map is returned every time
+	_, computed := schemas.providers.Load(jobj.ProviderOpenAIStrict)
+	assert.True(t, computed)
+	strict, err := schemas.ForProvider(jobj.ProviderOpenAIStrict)
+	assert.NoError(t, err)
+	again, err := schemas.ForProvider(jobj.ProviderOpenAIStrict)
+	assert.NoError(t, err)
+	assert.Equal(t, false, strict["additionalProperties"])
+	assert.Equal(t, reflect.ValueOf(strict).Pointer(), reflect.ValueOf(again).Pointer())
+
+	// Other providers are converted on first use
+	_, computed = schemas.providers.Load(jobj.ProviderGemini)
+	assert.False(t, computed)
+	gemini, err := schemas.ForProvider(jobj.ProviderGemini)
+	assert.NoError(t, err)
+	assert.NotContains(t, gemini, "additionalProperties")
+
+	_, err = schemas.ForProvider("unknown")
+	assert.Error(t, err)
+}
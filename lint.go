@@ -0,0 +1,108 @@
+package jobj
+
+import (
+	"fmt"
+)
+
+// Lint rules reported in LintWarning.Rule.
+const (
+	LintMissingDescription = "missing-description"
+	LintEmptyEnum          = "empty-enum"
+	LintRequiredNullable   = "required-nullable"
+	LintNestingDepth       = "nesting-depth"
+	LintDuplicateName      = "duplicate-name"
+)
+
+// LintMaxDepth is the object nesting depth beyond which Lint warns. Deeply nested schemas are
+// hard for models to fill in reliably, and OpenAI strict mode rejects them outright.
+const LintMaxDepth = 5
+
+// LintWarning is one problem found by Lint. Pointer locates the field like FieldStats.Pointer,
+// with "*" standing for array items; it is empty for problems with the schema itself.
+type LintWarning struct {
+	Pointer string
+	Rule    string
+	Message string
+}
+
+func (w LintWarning) String() string {
+	if w.Pointer == "" {
+		return fmt.Sprintf("%s: %s", w.Rule, w.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", w.Pointer, w.Rule, w.Message)
+}
+
+// Lint checks a schema for problems that are legal JSON Schema but hurt extraction quality or
+// maintainability: missing descriptions, enums without options, required fields that allow null,
+// nesting deeper than LintMaxDepth and duplicate field names. Warnings are returned in field order.
+func Lint(schema *Schema) []LintWarning {
+	var warnings []LintWarning
+	if schema.Description == "" {
+		warnings = append(warnings, LintWarning{Rule: LintMissingDescription, Message: fmt.Sprintf("schema %q has no description", schema.Name)})
+	}
+
+	if schema.RootField != nil {
+		lintField(schema.RootField, "", 0, &warnings)
+		return warnings
+	}
+	lintFields(schema.Fields, "", 0, &warnings)
+	return warnings
+}
+
+func lintFields(fields []*Field, pointer string, depth int, warnings *[]LintWarning) {
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		fieldPointer := pointer + "/" + escapePointerToken(field.ValueName)
+		if seen[field.ValueName] {
+			*warnings = append(*warnings, LintWarning{Pointer: fieldPointer, Rule: LintDuplicateName, Message: fmt.Sprintf("field %q is declared more than once", field.ValueName)})
+		}
+		seen[field.ValueName] = true
+
+		if field.ValueDescription == "" {
+			*warnings = append(*warnings, LintWarning{Pointer: fieldPointer, Rule: LintMissingDescription, Message: fmt.Sprintf("field %q has no description", field.ValueName)})
+		}
+		lintField(field, fieldPointer, depth, warnings)
+	}
+}
+
+func lintField(field *Field, pointer string, depth int, warnings *[]LintWarning) {
+	if field.ValueAnyOf != nil {
+		if len(field.ValueAnyOf) == 0 {
+			*warnings = append(*warnings, LintWarning{Pointer: pointer, Rule: LintEmptyEnum, Message: "anyOf has no options, so no value is valid"})
+		}
+		for _, option := range field.ValueAnyOf {
+			if option.Const == nil && field.ValueRequired {
+				*warnings = append(*warnings, LintWarning{Pointer: pointer, Rule: LintRequiredNullable, Message: "field is required but allows null"})
+				break
+			}
+		}
+		return
+	}
+
+	switch field.ValueType {
+	case TypeObject:
+		if field.AdditionalProperties {
+			if field.AdditionalPropertiesField != nil {
+				lintNested(field.AdditionalPropertiesField.SubFields, pointer+"/*", depth+1, warnings)
+			}
+			return
+		}
+		lintNested(field.SubFields, pointer, depth+1, warnings)
+	case TypeArray:
+		if field.ArrayItemField != nil {
+			lintField(field.ArrayItemField, pointer+"/*", depth, warnings)
+			return
+		}
+		lintNested(field.SubFields, pointer+"/*", depth+1, warnings)
+	}
+}
+
+func lintNested(fields []*Field, pointer string, depth int, warnings *[]LintWarning) {
+	if fields == nil {
+		return
+	}
+	if depth == LintMaxDepth+1 {
+		*warnings = append(*warnings, LintWarning{Pointer: pointer, Rule: LintNestingDepth, Message: fmt.Sprintf("objects are nested more than %d levels deep", LintMaxDepth)})
+	}
+	lintFields(fields, pointer, depth, warnings)
+}
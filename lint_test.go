@@ -0,0 +1,57 @@
+package jobj
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	nested := Text("leaf").Desc("Deepest field")
+	for i := 0; i < LintMaxDepth+1; i++ {
+		nested = Object("level", []*Field{nested}).Desc("A nesting level")
+	}
+
+	s := &Schema{
+		Name: "Linted",
+		Fields: []*Field{
+			Text("headline").Desc("The headline").Required(),
+			Text("headline").Desc("Declared twice"),
+			Text("summary"),
+			AnyOf("empty", []ConstDescription{}).Desc("No options"),
+			AnyOf("status", []ConstDescription{{Const: "open"}, {Const: nil}}).Desc("Status or null").Required(),
+			Array("quotes", []*Field{Text("speaker")}).Desc("Quotes"),
+			nested,
+		},
+	}
+
+	got := make([]string, 0)
+	for _, warning := range Lint(s) {
+		got = append(got, warning.Pointer+" "+warning.Rule)
+	}
+	expected := []string{
+		" missing-description",
+		"/headline duplicate-name",
+		"/summary missing-description",
+		"/empty empty-enum",
+		"/status required-nullable",
+		"/quotes/*/speaker missing-description",
+		"/level/level/level/level/level/level nesting-depth",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected lint warnings.\nwant: %v\ngot:  %v", expected, got)
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	s := &Schema{
+		Name:        "Clean",
+		Description: "A clean schema",
+		Fields: []*Field{
+			Text("headline").Desc("The headline").Required(),
+			MapOf("people", Object("", []*Field{Text("name").Desc("Name")})).Desc("People by id"),
+		},
+	}
+	if warnings := Lint(s); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+}
@@ -0,0 +1,231 @@
+package jobj
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaConfig is the JSON/YAML form of a Schema accepted by LoadSchemaFile.
+type schemaConfig struct {
+	Name        string         `json:"name" yaml:"name"`
+	Description string         `json:"description" yaml:"description"`
+	ID          string         `json:"id" yaml:"id"`
+	Version     string         `json:"version" yaml:"version"`
+	Fields      []*fieldConfig `json:"fields" yaml:"fields"`
+}
+
+// fieldConfig is the JSON/YAML form of a Field. Arrays set either Items (primitive item type) or
+// Fields (object items); maps set either Values (primitive value type) or Fields (object values).
+type fieldConfig struct {
+	Name        string         `json:"name" yaml:"name"`
+	Type        string         `json:"type" yaml:"type"`
+	Description string         `json:"description" yaml:"description"`
+	Required    bool           `json:"required" yaml:"required"`
+	Items       string         `json:"items" yaml:"items"`
+	Values      string         `json:"values" yaml:"values"`
+	Enum        []constConfig  `json:"enum" yaml:"enum"`
+	Fields      []*fieldConfig `json:"fields" yaml:"fields"`
+}
+
+type constConfig struct {
+	Const       interface{} `json:"const" yaml:"const"`
+	Description string      `json:"description" yaml:"description"`
+}
+
+// LoadSchemaFile reads a Schema from a config file so schemas can be changed by operators without
+// recompiling. The format is chosen by extension: .json and .yaml/.yml files hold a document of the form
+//
+//	name: PressRelease
+//	description: Press release extraction
+//	id: https://example.com/schemas/press-release.json
+//	version: 1.2.0
+//	fields:
+//	  - name: headline
+//	    type: string
+//	    description: The exact headline
+//	    required: true
+//	  - name: tags
+//	    type: array
+//	    items: string
+//	  - name: sentiment
+//	    enum:
+//	      - const: positive
+//	      - const: negative
+//	  - name: quotes
+//	    type: array
+//	    fields:
+//	      - name: speaker
+//	        type: string
+//
+// Any other extension is parsed with ParseDSL, using the file name without its extension as the
+// schema name. Field types are the DataType values plus "date", "datetime", "email", "uri", "uuid"
+// and "map"; a field with an enum has no type. The loaded schema is validated and an error
+// describes the first problem found.
+func LoadSchemaFile(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	var config schemaConfig
+	switch ext {
+	case ".json":
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse schema file %s: %w", path, err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse schema file %s: %w", path, err)
+		}
+	default:
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		schema, err := ParseDSL(name, string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse schema file %s: %w", path, err)
+		}
+		if err := schema.Finalize(); err != nil {
+			return nil, fmt.Errorf("invalid schema file %s: %w", path, err)
+		}
+		return schema, nil
+	}
+
+	schema, err := config.schema()
+	if err == nil {
+		err = schema.Finalize()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema file %s: %w", path, err)
+	}
+	return schema, nil
+}
+
+func (c *schemaConfig) schema() (*Schema, error) {
+	if c.Name == "" {
+		return nil, fmt.Errorf("schema name must not be empty")
+	}
+	if len(c.Fields) == 0 {
+		return nil, fmt.Errorf("schema %q has no fields", c.Name)
+	}
+
+	fields, err := configFields(c.Fields, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{
+		Name:        c.Name,
+		Description: c.Description,
+		SchemaID:    c.ID,
+		Version:     c.Version,
+		Fields:      fields,
+	}, nil
+}
+
+func configFields(configs []*fieldConfig, path string) ([]*Field, error) {
+	fields := make([]*Field, 0, len(configs))
+	for i, config := range configs {
+		if config == nil || config.Name == "" {
+			return nil, fmt.Errorf("field %d%s: name must not be empty", i, path)
+		}
+		field, err := config.field(path + "/" + config.Name)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field.Desc(config.Description))
+		if config.Required {
+			field.Required()
+		}
+	}
+	return fields, nil
+}
+
+func (c *fieldConfig) field(path string) (*Field, error) {
+	if len(c.Enum) > 0 {
+		if c.Type != "" {
+			return nil, fmt.Errorf("field %s: enum fields must not set a type", path)
+		}
+		enums := make([]ConstDescription, 0, len(c.Enum))
+		for _, enum := range c.Enum {
+			enums = append(enums, ConstDescription{Const: enum.Const, Description: enum.Description})
+		}
+		return AnyOf(c.Name, enums), nil
+	}
+
+	switch c.Type {
+	case string(TypeString):
+		return Text(c.Name), nil
+	case string(TypeNumber):
+		return Float(c.Name), nil
+	case string(TypeInteger):
+		return Int(c.Name), nil
+	case string(TypeBoolean):
+		return Bool(c.Name), nil
+	case "date":
+		return Date(c.Name), nil
+	case "datetime":
+		return DateTime(c.Name), nil
+	case "email":
+		return Email(c.Name), nil
+	case "uri":
+		return URI(c.Name), nil
+	case "uuid":
+		return UUID(c.Name), nil
+	case string(TypeObject):
+		subFields, err := configFields(c.Fields, path)
+		if err != nil {
+			return nil, err
+		}
+		return Object(c.Name, subFields), nil
+	case string(TypeArray):
+		if c.Items != "" {
+			itemType, err := primitiveConfigType(c.Items, path)
+			if err != nil {
+				return nil, err
+			}
+			return ArrayOf(c.Name, itemType), nil
+		}
+		if len(c.Fields) == 0 {
+			return nil, fmt.Errorf("field %s: arrays must set items or fields", path)
+		}
+		subFields, err := configFields(c.Fields, path)
+		if err != nil {
+			return nil, err
+		}
+		return Array(c.Name, subFields), nil
+	case "map":
+		if c.Values != "" {
+			valueType, err := primitiveConfigType(c.Values, path)
+			if err != nil {
+				return nil, err
+			}
+			return Map(c.Name, valueType), nil
+		}
+		if len(c.Fields) == 0 {
+			// Maps without a value schema allow any value
+			return MapOf(c.Name, &Field{ValueType: TypeObject}), nil
+		}
+		subFields, err := configFields(c.Fields, path)
+		if err != nil {
+			return nil, err
+		}
+		return MapOf(c.Name, Object("", subFields)), nil
+	case "":
+		return nil, fmt.Errorf("field %s: type must not be empty", path)
+	default:
+		return nil, fmt.Errorf("field %s: unknown type %q", path, c.Type)
+	}
+}
+
+func primitiveConfigType(name string, path string) (DataType, error) {
+	switch DataType(name) {
+	case TypeString, TypeNumber, TypeInteger, TypeBoolean:
+		return DataType(name), nil
+	default:
+		return "", fmt.Errorf("field %s: unsupported item or value type %q", path, name)
+	}
+}
@@ -0,0 +1,133 @@
+package jobj
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSchemaFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write schema file: %v", err)
+	}
+	return path
+}
+
+func TestLoadSchemaFileYAML(t *testing.T) {
+	path := writeSchemaFile(t, "press.yaml", `
+name: PressRelease
+description: Press release extraction
+id: https://example.com/schemas/press-release.json
+version: 1.2.0
+fields:
+  - name: headline
+    type: string
+    description: The exact headline
+    required: true
+  - name: tags
+    type: array
+    items: string
+  - name: severity
+    enum:
+      - const: 1
+        description: low
+      - const: 2
+        description: high
+  - name: quotes
+    type: array
+    fields:
+      - name: speaker
+        type: string
+        required: true
+  - name: counts
+    type: map
+    values: integer
+`)
+
+	s, err := LoadSchemaFile(path)
+	if err != nil {
+		t.Fatalf("LoadSchemaFile failed: %v", err)
+	}
+
+	if s.Name != "PressRelease" || s.Description != "Press release extraction" || len(s.Fields) != 5 {
+		t.Fatalf("Unexpected schema: %+v", s)
+	}
+	if s.SchemaID != "https://example.com/schemas/press-release.json" || s.Version != "1.2.0" {
+		t.Errorf("Unexpected schema metadata: %q %q", s.SchemaID, s.Version)
+	}
+	if !s.Fields[0].ValueRequired || s.Fields[0].ValueDescription != "The exact headline" {
+		t.Errorf("Unexpected headline field: %+v", s.Fields[0])
+	}
+	if s.Fields[1].ArrayItemType != TypeString {
+		t.Errorf("Unexpected tags field: %+v", s.Fields[1])
+	}
+	if s.Fields[2].ValueAnyOf[0].Const != 1 {
+		t.Errorf("Expected integer const, got %#v", s.Fields[2].ValueAnyOf[0].Const)
+	}
+	if len(s.Fields[3].SubFields) != 1 || !s.Fields[3].SubFields[0].ValueRequired {
+		t.Errorf("Unexpected quotes field: %+v", s.Fields[3])
+	}
+	if s.Fields[4].AdditionalPropertiesType != TypeInteger {
+		t.Errorf("Unexpected counts field: %+v", s.Fields[4])
+	}
+}
+
+func TestLoadSchemaFileJSON(t *testing.T) {
+	path := writeSchemaFile(t, "press.json", `{
+  "name": "PressRelease",
+  "fields": [
+    {"name": "headline", "type": "string", "required": true},
+    {"name": "source", "type": "object", "fields": [{"name": "name", "type": "string"}]}
+  ]
+}`)
+
+	s, err := LoadSchemaFile(path)
+	if err != nil {
+		t.Fatalf("LoadSchemaFile failed: %v", err)
+	}
+	if len(s.Fields) != 2 || s.Fields[1].ValueType != TypeObject || len(s.Fields[1].SubFields) != 1 {
+		t.Errorf("Unexpected schema: %+v", s)
+	}
+}
+
+func TestLoadSchemaFileDSL(t *testing.T) {
+	path := writeSchemaFile(t, "Headlines.jobj", `headline: string! "The exact headline"`)
+
+	s, err := LoadSchemaFile(path)
+	if err != nil {
+		t.Fatalf("LoadSchemaFile failed: %v", err)
+	}
+	if s.Name != "Headlines" || len(s.Fields) != 1 {
+		t.Errorf("Unexpected schema: %+v", s)
+	}
+}
+
+func TestLoadSchemaFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"Missing name", "a.json", `{"fields": [{"name": "a", "type": "string"}]}`},
+		{"No fields", "a.json", `{"name": "A"}`},
+		{"Unknown type", "a.yaml", "name: A\nfields:\n  - name: a\n    type: text\n"},
+		{"Missing type", "a.yaml", "name: A\nfields:\n  - name: a\n"},
+		{"Array without items", "a.yaml", "name: A\nfields:\n  - name: a\n    type: array\n"},
+		{"Enum with type", "a.yaml", "name: A\nfields:\n  - name: a\n    type: string\n    enum:\n      - const: x\n"},
+		{"Malformed JSON", "a.json", `{"name": `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadSchemaFile(writeSchemaFile(t, tt.file, tt.content)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	if _, err := LoadSchemaFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !tinygo
+
+package jobj
+
+import "log/slog"
+
+// logError reports errors that the calling API has no way to return. TinyGo builds, where
+// log/slog is too heavy for browser bundles, use the version in log_tinygo.go.
+func logError(msg string, args ...any) {
+	slog.Error(msg, args...)
+}
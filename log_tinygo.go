@@ -0,0 +1,8 @@
+//go:build tinygo
+
+package jobj
+
+// logError reports errors that the calling API has no way to return, without log/slog.
+func logError(msg string, args ...any) {
+	println("jobj:", msg)
+}
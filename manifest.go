@@ -0,0 +1,199 @@
+package jobj
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidSignature is returned by ImportSigned when a snapshot's signature does not verify.
+var ErrInvalidSignature = errors.New("invalid registry snapshot signature")
+
+// Signer signs registry snapshots written by ExportSigned.
+type Signer interface {
+	// Algorithm names the signature scheme, e.g. "ed25519". It is recorded in the snapshot.
+	Algorithm() string
+	Sign(message []byte) ([]byte, error)
+}
+
+// Verifier checks the signatures of snapshots read by ImportSigned.
+type Verifier interface {
+	// Algorithm names the signature scheme, which must match the one recorded in the snapshot.
+	Algorithm() string
+	Verify(message []byte, signature []byte) error
+}
+
+// signedSnapshot is the document written by ExportSigned: a snapshot as written by Export, and a
+// signature of its compact JSON encoding. Verifying the compact encoding lets the document be
+// re-indented in transit without invalidating it.
+type signedSnapshot struct {
+	Format    int             `json:"format"`
+	Algorithm string          `json:"algorithm"`
+	Signature []byte          `json:"signature"`
+	Snapshot  json.RawMessage `json:"snapshot"`
+}
+
+// NewHMACSigner returns a Signer using HMAC-SHA256 with a shared secret key.
+func NewHMACSigner(key []byte) Signer {
+	return hmacKey(key)
+}
+
+// NewHMACVerifier returns a Verifier for snapshots signed by NewHMACSigner with the same key.
+func NewHMACVerifier(key []byte) Verifier {
+	return hmacKey(key)
+}
+
+type hmacKey []byte
+
+func (k hmacKey) Algorithm() string {
+	return "hmac-sha256"
+}
+
+func (k hmacKey) Sign(message []byte) ([]byte, error) {
+	if len(k) == 0 {
+		return nil, fmt.Errorf("HMAC key must not be empty")
+	}
+	mac := hmac.New(sha256.New, k)
+	mac.Write(message)
+	return mac.Sum(nil), nil
+}
+
+func (k hmacKey) Verify(message []byte, signature []byte) error {
+	expected, err := k.Sign(message)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(expected, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// NewEd25519Signer returns a Signer using an ed25519 private key. Workers only need the public
+// key, so they cannot sign snapshots themselves.
+func NewEd25519Signer(key ed25519.PrivateKey) Signer {
+	return ed25519Signer(key)
+}
+
+// NewEd25519Verifier returns a Verifier for snapshots signed with the private key matching key.
+func NewEd25519Verifier(key ed25519.PublicKey) Verifier {
+	return ed25519Verifier(key)
+}
+
+type ed25519Signer ed25519.PrivateKey
+
+func (k ed25519Signer) Algorithm() string {
+	return "ed25519"
+}
+
+func (k ed25519Signer) Sign(message []byte) ([]byte, error) {
+	if len(k) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("ed25519 private key must be %d bytes, got %d", ed25519.PrivateKeySize, len(k))
+	}
+	return ed25519.Sign(ed25519.PrivateKey(k), message), nil
+}
+
+type ed25519Verifier ed25519.PublicKey
+
+func (k ed25519Verifier) Algorithm() string {
+	return "ed25519"
+}
+
+func (k ed25519Verifier) Verify(message []byte, signature []byte) error {
+	if len(k) != ed25519.PublicKeySize {
+		return fmt.Errorf("ed25519 public key must be %d bytes, got %d", ed25519.PublicKeySize, len(k))
+	}
+	if !ed25519.Verify(ed25519.PublicKey(k), message, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ExportSigned writes the registry's catalog as Export does, wrapped in a manifest signed by
+// signer, so workers loading it with ImportSigned can check it came from the control plane
+// unmodified.
+func (r *Registry) ExportSigned(w io.Writer, signer Signer) error {
+	return r.exportSigned(w, signer, "")
+}
+
+// ImportSigned verifies a manifest written by ExportSigned with verifier and, if the signature is
+// valid, imports its catalog as Import does. A manifest with an invalid signature is rejected with
+// ErrInvalidSignature and nothing is added.
+func (r *Registry) ImportSigned(reader io.Reader, verifier Verifier) error {
+	return r.importSigned(reader, verifier, "")
+}
+
+// ExportSigned writes the namespace's catalog as Export does, signed by signer. See
+// Registry.ExportSigned.
+func (n *Namespace) ExportSigned(w io.Writer, signer Signer) error {
+	return n.registry.exportSigned(w, signer, n.name)
+}
+
+// ImportSigned verifies a manifest written by ExportSigned and imports its catalog into the
+// namespace. See Registry.ImportSigned.
+func (n *Namespace) ImportSigned(reader io.Reader, verifier Verifier) error {
+	return n.registry.importSigned(reader, verifier, n.name)
+}
+
+func (r *Registry) exportSigned(w io.Writer, signer Signer, namespace string) error {
+	snapshot, err := r.snapshot(namespace)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode registry snapshot: %w", err)
+	}
+	signature, err := signer.Sign(encoded)
+	if err != nil {
+		return fmt.Errorf("failed to sign registry snapshot: %w", err)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(signedSnapshot{
+		Format:    snapshotFormat,
+		Algorithm: signer.Algorithm(),
+		Signature: signature,
+		Snapshot:  encoded,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write registry snapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *Registry) importSigned(reader io.Reader, verifier Verifier, namespace string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read registry snapshot: %w", err)
+	}
+
+	var signed signedSnapshot
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return fmt.Errorf("failed to parse registry snapshot: %w", err)
+	}
+	if signed.Snapshot == nil || signed.Signature == nil {
+		return fmt.Errorf("registry snapshot is not signed")
+	}
+	if signed.Format != snapshotFormat {
+		return fmt.Errorf("unsupported registry snapshot format %d", signed.Format)
+	}
+	if signed.Algorithm != verifier.Algorithm() {
+		return fmt.Errorf("%w: signed with %q, expected %q", ErrInvalidSignature, signed.Algorithm, verifier.Algorithm())
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, signed.Snapshot); err != nil {
+		return fmt.Errorf("failed to parse registry snapshot: %w", err)
+	}
+	if err := verifier.Verify(compact.Bytes(), signed.Signature); err != nil {
+		return err
+	}
+	return r.importData(compact.Bytes(), namespace)
+}
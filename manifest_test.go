@@ -0,0 +1,119 @@
+package jobj
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newManifestSource() *Registry {
+	source := NewRegistry()
+	_ = source.Register("Counting", &countingSchema{})
+	_ = source.RegisterSchema(&Schema{
+		Name:   "Headlines",
+		Fields: []*Field{Text("headline").Desc("Use <b> & </b> sparingly").Required()},
+	})
+	_ = source.Override("staging", "Headlines", []byte(`{"description": "Staging headlines"}`))
+	return source
+}
+
+func TestRegistrySignedExportImport(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	otherPublic, _, _ := ed25519.GenerateKey(nil)
+
+	tests := []struct {
+		name   string
+		signer Signer
+		valid  Verifier
+		wrong  Verifier
+	}{
+		{"hmac", NewHMACSigner([]byte("secret")), NewHMACVerifier([]byte("secret")), NewHMACVerifier([]byte("guess"))},
+		{"ed25519", NewEd25519Signer(private), NewEd25519Verifier(public), NewEd25519Verifier(otherPublic)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var manifest bytes.Buffer
+			if err := newManifestSource().ExportSigned(&manifest, tt.signer); err != nil {
+				t.Fatalf("ExportSigned failed: %v", err)
+			}
+
+			// Re-indenting the manifest in transit does not invalidate it.
+			var reindented bytes.Buffer
+			_ = json.Indent(&reindented, manifest.Bytes(), "", "\t")
+
+			target := NewRegistry()
+			if err := target.ImportSigned(&reindented, tt.valid); err != nil {
+				t.Fatalf("ImportSigned failed: %v", err)
+			}
+			if got := strings.Join(target.Names(), ","); got != "Counting,Headlines" {
+				t.Errorf("Expected both schemas, got %s", got)
+			}
+			if staging, err := target.BuildProfile("staging", "Headlines"); err != nil || staging.Description != "Staging headlines" {
+				t.Errorf("Expected the staging override to be imported, got %v, %v", staging, err)
+			}
+
+			if err := NewRegistry().ImportSigned(bytes.NewReader(manifest.Bytes()), tt.wrong); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Expected ErrInvalidSignature for the wrong key, got %v", err)
+			}
+
+			tampered := strings.Replace(manifest.String(), "Staging headlines", "Tampered headlines", 1)
+			empty := NewRegistry()
+			if err := empty.ImportSigned(strings.NewReader(tampered), tt.valid); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Expected ErrInvalidSignature for a tampered manifest, got %v", err)
+			}
+			if len(empty.Names()) != 0 {
+				t.Errorf("Expected nothing to be imported from a tampered manifest")
+			}
+
+			if err := NewRegistry().Import(bytes.NewReader(manifest.Bytes())); err == nil {
+				t.Errorf("Expected Import to refuse a signed manifest")
+			}
+		})
+	}
+}
+
+func TestRegistryImportSignedErrors(t *testing.T) {
+	var unsigned bytes.Buffer
+	_ = newManifestSource().Export(&unsigned)
+	if err := NewRegistry().ImportSigned(&unsigned, NewHMACVerifier([]byte("secret"))); err == nil {
+		t.Errorf("Expected ImportSigned to reject an unsigned snapshot")
+	}
+
+	var manifest bytes.Buffer
+	_ = newManifestSource().ExportSigned(&manifest, NewHMACSigner([]byte("secret")))
+	public, _, _ := ed25519.GenerateKey(nil)
+	if err := NewRegistry().ImportSigned(&manifest, NewEd25519Verifier(public)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for a mismatched algorithm, got %v", err)
+	}
+
+	if err := newManifestSource().ExportSigned(&bytes.Buffer{}, NewHMACSigner(nil)); err == nil {
+		t.Errorf("Expected ExportSigned to reject an empty HMAC key")
+	}
+}
+
+func TestNamespaceSignedExportImport(t *testing.T) {
+	source := NewRegistry()
+	acme, _ := source.Namespace("acme")
+	_ = acme.Register("Counting", &countingSchema{})
+
+	signer := NewHMACSigner([]byte("secret"))
+	var manifest bytes.Buffer
+	if err := acme.ExportSigned(&manifest, signer); err != nil {
+		t.Fatalf("ExportSigned failed: %v", err)
+	}
+
+	target := NewRegistry()
+	initech, _ := target.Namespace("initech")
+	if err := initech.ImportSigned(&manifest, NewHMACVerifier([]byte("secret"))); err != nil {
+		t.Fatalf("ImportSigned failed: %v", err)
+	}
+	if names := target.Names(); len(names) != 1 || names[0] != "initech/Counting" {
+		t.Errorf("Unexpected names after import: %v", names)
+	}
+}
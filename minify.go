@@ -0,0 +1,74 @@
+package jobj
+
+import (
+	"encoding/json"
+)
+
+// CompactOptions controls what GetCompactSchemaString leaves out.
+type CompactOptions struct {
+	StripDescriptions bool // Drop every description, including enum option descriptions
+	StripExamples     bool // Drop examples keywords
+}
+
+// GetCompactSchemaString returns the same document as GetSchemaString without indentation and
+// without empty descriptions, for token-sensitive deployments. Options strip further keywords;
+// the pretty output of GetSchemaString remains the one to read when debugging.
+func (r *Schema) GetCompactSchemaString(options CompactOptions) string {
+	shared, definitions := r.ShareDefinitions(definitionsPath)
+	definition := shared.definitionJson()
+	compactKeywords(definition, options)
+	for _, sharedDefinition := range definitions {
+		compactKeywords(sharedDefinition, options)
+	}
+
+	schemaJson, err := json.Marshal(shared.schemaDocument(definition, definitions))
+	if err != nil {
+		logError("Error marshalling JSON schema", "err", err)
+		return ""
+	}
+	return string(schemaJson)
+}
+
+// compactKeywords removes the keywords selected by options from every schema object in node, in place.
+func compactKeywords(node interface{}, options CompactOptions) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for keyword, child := range value {
+			switch keyword {
+			case "description":
+				if description, _ := child.(string); description == "" || options.StripDescriptions {
+					delete(value, keyword)
+				}
+				continue
+			case "examples":
+				if options.StripExamples {
+					delete(value, keyword)
+				}
+				continue
+			case "const", "required":
+				// Values, not schemas
+				continue
+			case "properties":
+				if properties, ok := child.(map[string]interface{}); ok {
+					for _, property := range properties {
+						compactKeywords(property, options)
+					}
+				}
+				continue
+			}
+			compactKeywords(child, options)
+		}
+	case map[string]string:
+		if value["description"] == "" || options.StripDescriptions {
+			delete(value, "description")
+		}
+	case []interface{}:
+		for _, item := range value {
+			compactKeywords(item, options)
+		}
+	case []map[string]interface{}:
+		for _, item := range value {
+			compactKeywords(item, options)
+		}
+	}
+}
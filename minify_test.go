@@ -0,0 +1,55 @@
+package jobj
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newCompactTestSchema() *Schema {
+	return &Schema{
+		Name:        "Compact",
+		Description: "A compact schema",
+		Fields: []*Field{
+			Text("headline").Desc("The headline").Required(),
+			Text("summary"),
+			AnyOf("sentiment", []ConstDescription{
+				{Const: "positive", Description: "Good news"},
+				{Const: "negative"},
+			}),
+			Array("quotes", []*Field{
+				Text("speaker").Desc("Who said it"),
+			}),
+		},
+	}
+}
+
+func TestGetCompactSchemaString(t *testing.T) {
+	s := newCompactTestSchema()
+
+	compact := s.GetCompactSchemaString(CompactOptions{})
+	if strings.Contains(compact, "\n") || strings.Contains(compact, `"description":""`) {
+		t.Errorf("Expected unindented output without empty descriptions, got %s", compact)
+	}
+	for _, want := range []string{`"description":"The headline"`, `"description":"Good news"`, `"description":"A compact schema"`} {
+		if !strings.Contains(compact, want) {
+			t.Errorf("Expected compact schema to contain %s, got %s", want, compact)
+		}
+	}
+	if len(compact) >= len(s.GetSchemaString()) {
+		t.Error("Expected compact output to be shorter than pretty output")
+	}
+
+	stripped := s.GetCompactSchemaString(CompactOptions{StripDescriptions: true})
+	if strings.Contains(stripped, `"description"`) {
+		t.Errorf("Expected all descriptions to be stripped, got %s", stripped)
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(stripped), &document); err != nil {
+		t.Fatalf("Compact output is not valid JSON: %v", err)
+	}
+	if document["$ref"] != "#/definitions/Compact" {
+		t.Errorf("Expected document structure to be preserved, got %v", document)
+	}
+}
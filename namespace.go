@@ -0,0 +1,161 @@
+package jobj
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// namespaceSeparator separates the namespace from the name in a qualified schema name.
+const namespaceSeparator = "/"
+
+// Namespace is a view of a Registry holding one tenant's or project's schemas. Names passed to
+// its methods are local to the namespace, so two namespaces can register schemas of the same name
+// without colliding, and a namespace never sees another's schemas or overrides. In the underlying
+// registry its schemas are registered under qualified names of the form "namespace/name".
+type Namespace struct {
+	registry *Registry
+	name     string
+}
+
+// Namespace returns the namespace called name. Namespaces need not be created before use: one
+// exists as long as it holds schemas. It returns an error if name is empty or contains "/".
+func (r *Registry) Namespace(name string) (*Namespace, error) {
+	if name == "" {
+		return nil, fmt.Errorf("namespace must not be empty")
+	}
+	if strings.Contains(name, namespaceSeparator) {
+		return nil, fmt.Errorf("namespace %q must not contain %q", name, namespaceSeparator)
+	}
+	return &Namespace{registry: r, name: name}, nil
+}
+
+// Namespaces returns the names of the namespaces holding at least one schema, in sorted order.
+func (r *Registry) Namespaces() []string {
+	seen := make(map[string]bool)
+	var namespaces []string
+	for _, name := range r.Names() {
+		namespace, _, ok := strings.Cut(name, namespaceSeparator)
+		if ok && !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// Name returns the namespace's name.
+func (n *Namespace) Name() string {
+	return n.name
+}
+
+// Register adds a CreatableSchema under name in the namespace. See Registry.Register.
+func (n *Namespace) Register(name string, creatable CreatableSchema) error {
+	qualified, err := qualifiedName(n.name, name)
+	if err != nil {
+		return err
+	}
+	return n.registry.register(qualified, creatable)
+}
+
+// RegisterSchema adds an already built Schema under its Name in the namespace. See
+// Registry.RegisterSchema.
+func (n *Namespace) RegisterSchema(schema *Schema) error {
+	if schema == nil {
+		return fmt.Errorf("received nil schema")
+	}
+	qualified, err := qualifiedName(n.name, schema.Name)
+	if err != nil {
+		return err
+	}
+	return n.registry.registerSchema(qualified, schema)
+}
+
+// Build returns the schema registered under name in the namespace. See Registry.Build.
+func (n *Namespace) Build(name string) (*Schema, error) {
+	qualified, err := qualifiedName(n.name, name)
+	if err != nil {
+		return nil, err
+	}
+	return n.registry.Build(qualified)
+}
+
+// Override sets the merge patch applied to the namespace's schema called name when it is built
+// for profile. See Registry.Override.
+func (n *Namespace) Override(profile string, name string, mergePatch []byte) error {
+	qualified, err := qualifiedName(n.name, name)
+	if err != nil {
+		return err
+	}
+	return n.registry.Override(profile, qualified, mergePatch)
+}
+
+// BuildProfile returns the namespace's schema called name as seen by profile. See
+// Registry.BuildProfile.
+func (n *Namespace) BuildProfile(profile string, name string) (*Schema, error) {
+	qualified, err := qualifiedName(n.name, name)
+	if err != nil {
+		return nil, err
+	}
+	return n.registry.BuildProfile(profile, qualified)
+}
+
+// Names returns the names of the schemas in the namespace, without the namespace, in sorted order.
+func (n *Namespace) Names() []string {
+	names := []string{}
+	for _, name := range n.registry.Names() {
+		if inNamespace(name, n.name) {
+			names = append(names, withinNamespace(name, n.name))
+		}
+	}
+	return names
+}
+
+// Export writes the namespace's schemas and overrides to w in the format of Registry.Export, under
+// their names within the namespace, so the snapshot can be imported into any namespace.
+func (n *Namespace) Export(w io.Writer) error {
+	return n.registry.export(w, n.name)
+}
+
+// Import adds the schemas and overrides of a snapshot written by Export to the namespace. See
+// Registry.Import. Snapshot names must not be qualified.
+func (n *Namespace) Import(reader io.Reader) error {
+	return n.registry.importSnapshot(reader, n.name)
+}
+
+// qualifiedName returns the registry name of the schema called name in namespace. Names in a
+// namespace must not contain "/", so they cannot reach into another namespace.
+func qualifiedName(namespace string, name string) (string, error) {
+	if namespace == "" {
+		return name, nil
+	}
+	if name == "" {
+		return "", fmt.Errorf("schema name must not be empty")
+	}
+	if strings.Contains(name, namespaceSeparator) {
+		return "", fmt.Errorf("schema name %q in namespace %q must not contain %q", name, namespace, namespaceSeparator)
+	}
+	return namespace + namespaceSeparator + name, nil
+}
+
+// localName returns name without its namespace.
+func localName(name string) string {
+	if _, local, ok := strings.Cut(name, namespaceSeparator); ok {
+		return local
+	}
+	return name
+}
+
+// inNamespace reports whether the registry name belongs to namespace. Every name belongs to the
+// empty namespace.
+func inNamespace(name string, namespace string) bool {
+	return namespace == "" || strings.HasPrefix(name, namespace+namespaceSeparator)
+}
+
+// withinNamespace returns the registry name as seen from namespace.
+func withinNamespace(name string, namespace string) string {
+	if namespace == "" {
+		return name
+	}
+	return strings.TrimPrefix(name, namespace+namespaceSeparator)
+}
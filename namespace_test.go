@@ -0,0 +1,138 @@
+package jobj
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegistryNamespaces(t *testing.T) {
+	registry := NewRegistry()
+	acme, err := registry.Namespace("acme")
+	if err != nil {
+		t.Fatalf("Namespace failed: %v", err)
+	}
+	globex, err := registry.Namespace("globex")
+	if err != nil {
+		t.Fatalf("Namespace failed: %v", err)
+	}
+
+	if err := acme.Register("Counting", &countingSchema{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := globex.RegisterSchema(&Schema{Name: "Counting", Fields: []*Field{Int("count")}}); err != nil {
+		t.Fatalf("RegisterSchema in another namespace failed: %v", err)
+	}
+	if err := registry.Register("Counting", &countingSchema{}); err != nil {
+		t.Fatalf("Register outside namespaces failed: %v", err)
+	}
+	if err := acme.Register("Counting", &countingSchema{}); err == nil {
+		t.Error("Expected error for duplicate name within a namespace")
+	}
+	if err := acme.Register("globex/Counting", &countingSchema{}); err == nil {
+		t.Error("Expected error for name reaching into another namespace")
+	}
+
+	acmeSchema, err := acme.Build("Counting")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if acmeSchema.Name != "Counting" || acmeSchema.Fields[0].ValueName != "name" {
+		t.Errorf("Unexpected schema in acme: %+v", acmeSchema)
+	}
+	globexSchema, err := registry.Build("globex/Counting")
+	if err != nil {
+		t.Fatalf("Build by qualified name failed: %v", err)
+	}
+	if globexSchema.Fields[0].ValueName != "count" {
+		t.Errorf("Expected globex's own schema, got %+v", globexSchema)
+	}
+
+	if err := acme.Override("staging", "Counting", []byte(`{"description": "Acme staging"}`)); err != nil {
+		t.Fatalf("Override failed: %v", err)
+	}
+	staged, err := globex.BuildProfile("staging", "Counting")
+	if err != nil {
+		t.Fatalf("BuildProfile failed: %v", err)
+	}
+	if staged != globexSchema {
+		t.Error("Expected acme's override not to apply to globex")
+	}
+
+	if names := acme.Names(); len(names) != 1 || names[0] != "Counting" {
+		t.Errorf("Unexpected acme names: %v", names)
+	}
+	if names := registry.Names(); strings.Join(names, ",") != "Counting,acme/Counting,globex/Counting" {
+		t.Errorf("Unexpected registry names: %v", names)
+	}
+	if namespaces := registry.Namespaces(); strings.Join(namespaces, ",") != "acme,globex" {
+		t.Errorf("Unexpected namespaces: %v", namespaces)
+	}
+
+	if _, err := registry.Namespace(""); err == nil {
+		t.Error("Expected error for empty namespace")
+	}
+	if _, err := registry.Namespace("acme/prod"); err == nil {
+		t.Error("Expected error for namespace containing a separator")
+	}
+}
+
+func TestNamespaceExportImport(t *testing.T) {
+	source := NewRegistry()
+	acme, _ := source.Namespace("acme")
+	globex, _ := source.Namespace("globex")
+	if err := acme.Register("Counting", &countingSchema{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := acme.Override("staging", "Counting", []byte(`{"description": "Acme staging"}`)); err != nil {
+		t.Fatalf("Override failed: %v", err)
+	}
+	if err := globex.RegisterSchema(&Schema{Name: "Secret", Fields: []*Field{Text("token")}}); err != nil {
+		t.Fatalf("RegisterSchema failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := acme.Export(&buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "Secret") || strings.Contains(buf.String(), "acme/") {
+		t.Errorf("Expected only acme's schemas under local names, got %s", buf.String())
+	}
+
+	target := NewRegistry()
+	initech, _ := target.Namespace("initech")
+	if err := initech.Import(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	staged, err := target.BuildProfile("staging", "initech/Counting")
+	if err != nil {
+		t.Fatalf("BuildProfile failed: %v", err)
+	}
+	if staged.Name != "Counting" || staged.Description != "Acme staging" {
+		t.Errorf("Unexpected imported schema: %+v", staged)
+	}
+	if err := initech.Import(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Error("Expected error importing into a namespace that already holds the schemas")
+	}
+
+	// A full export keeps qualified names and round-trips into another registry.
+	buf.Reset()
+	if err := source.Export(&buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	copied := NewRegistry()
+	if err := copied.Import(&buf); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if names := copied.Names(); strings.Join(names, ",") != "acme/Counting,globex/Secret" {
+		t.Errorf("Unexpected names after import: %v", names)
+	}
+
+	buf.Reset()
+	if err := source.Export(&buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if err := initech.Import(&buf); err == nil {
+		t.Error("Expected error importing qualified names into a namespace")
+	}
+}
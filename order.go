@@ -0,0 +1,143 @@
+package jobj
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// ListOrder controls how list-valued keywords are ordered when a schema is serialized.
+type ListOrder int
+
+const (
+	// DeclarationOrder keeps lists in the order fields and enum values were declared. This is the default.
+	DeclarationOrder ListOrder = iota
+	// SortedOrder sorts lists, so that reordering declarations does not change the serialized schema.
+	SortedOrder
+)
+
+// FinishDocument applies the schema's serialization options to document, a schema rendered from
+// its fields, in place: RequiredOrder and EnumOrder, and the omission of "required" on objects
+// without required fields. Packages that assemble their own documents from FieldsJson, such as
+// funcschema's GetPropertiesMap, call it so their output matches GetSchemaString.
+func (r *Schema) FinishDocument(document interface{}) {
+	r.orderLists(document)
+	omitNilRequired(document)
+}
+
+// orderLists applies the schema's RequiredOrder and EnumOrder to every "required" and "anyOf" list
+// in document, in place. Property maps need no handling: encoding/json always sorts map keys.
+func (r *Schema) orderLists(document interface{}) {
+	if r.RequiredOrder == DeclarationOrder && r.EnumOrder == DeclarationOrder {
+		return
+	}
+
+	switch node := document.(type) {
+	case map[string]interface{}:
+		for key, value := range node {
+			switch {
+			case key == "required" && r.RequiredOrder == SortedOrder:
+				if required, ok := value.([]string); ok {
+					sorted := append([]string(nil), required...)
+					sort.Strings(sorted)
+					node[key] = sorted
+					continue
+				}
+			case key == "anyOf" && r.EnumOrder == SortedOrder:
+				node[key] = sortedAnyOf(value)
+			}
+			r.orderLists(node[key])
+		}
+	case []interface{}:
+		for _, item := range node {
+			r.orderLists(item)
+		}
+	case []map[string]interface{}:
+		for _, item := range node {
+			r.orderLists(item)
+		}
+	}
+}
+
+// sortedAnyOf returns a copy of an anyOf list sorted by the JSON encoding of each option's const.
+// Options without a const, such as the null option added by Strict, keep their relative order after
+// the const options.
+func sortedAnyOf(value interface{}) interface{} {
+	var options []map[string]interface{}
+	switch list := value.(type) {
+	case []map[string]interface{}:
+		options = append(options, list...)
+	case []interface{}:
+		for _, item := range list {
+			option, ok := item.(map[string]interface{})
+			if !ok {
+				return value
+			}
+			options = append(options, option)
+		}
+	default:
+		return value
+	}
+
+	key := func(option map[string]interface{}) (string, bool) {
+		constValue, ok := option["const"]
+		if !ok {
+			return "", false
+		}
+		encoded, _ := json.Marshal(constValue)
+		return string(encoded), true
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		a, aHasConst := key(options[i])
+		b, bHasConst := key(options[j])
+		if aHasConst != bHasConst {
+			return aHasConst
+		}
+		return a < b
+	})
+
+	if _, ok := value.([]interface{}); ok {
+		sorted := make([]interface{}, len(options))
+		for i, option := range options {
+			sorted[i] = option
+		}
+		return sorted
+	}
+	return options
+}
+
+// omitNilRequired removes the "required" keyword from every object schema in document that has
+// no required fields as a nil list, in place, so it is omitted rather than encoded as null.
+// Explicit empty lists, such as the one on array items, are kept.
+func omitNilRequired(document interface{}) {
+	switch node := document.(type) {
+	case map[string]interface{}:
+		if required, ok := node["required"]; ok && isObjectType(node["type"]) {
+			if list, isList := required.([]string); required == nil || (isList && list == nil) {
+				delete(node, "required")
+			}
+		}
+		for _, value := range node {
+			omitNilRequired(value)
+		}
+	case []interface{}:
+		for _, item := range node {
+			omitNilRequired(item)
+		}
+	case []map[string]interface{}:
+		for _, item := range node {
+			omitNilRequired(item)
+		}
+	}
+}
+
+// isObjectType reports whether a "type" value, which the emitters set as either a string or a
+// DataType, is "object".
+func isObjectType(value interface{}) bool {
+	switch t := value.(type) {
+	case string:
+		return t == string(TypeObject)
+	case DataType:
+		return t == TypeObject
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package jobj
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func orderedSchema(requiredOrder ListOrder, enumOrder ListOrder) *Schema {
+	return &Schema{
+		Name:          "Ordered",
+		RequiredOrder: requiredOrder,
+		EnumOrder:     enumOrder,
+		Fields: []*Field{
+			Text("zeta").Required(),
+			Text("alpha").Required(),
+			AnyOf("status", []ConstDescription{
+				{Const: "open"},
+				{Const: "closed"},
+			}).Required(),
+		},
+	}
+}
+
+func schemaLists(t *testing.T, s *Schema) ([]string, []string) {
+	t.Helper()
+
+	var document struct {
+		Definitions map[string]struct {
+			Required   []string `json:"required"`
+			Properties map[string]struct {
+				AnyOf []struct {
+					Const string `json:"const"`
+				} `json:"anyOf"`
+			} `json:"properties"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(s.GetSchemaString()), &document); err != nil {
+		t.Fatalf("Failed to decode schema: %v", err)
+	}
+
+	definition := document.Definitions[s.Name]
+	enums := make([]string, 0)
+	for _, option := range definition.Properties["status"].AnyOf {
+		enums = append(enums, option.Const)
+	}
+	return definition.Required, enums
+}
+
+func TestListOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		requiredOrder ListOrder
+		enumOrder     ListOrder
+		wantRequired  []string
+		wantEnums     []string
+	}{
+		{"declaration", DeclarationOrder, DeclarationOrder, []string{"zeta", "alpha", "status"}, []string{"open", "closed"}},
+		{"sorted", SortedOrder, SortedOrder, []string{"alpha", "status", "zeta"}, []string{"closed", "open"}},
+		{"sorted required only", SortedOrder, DeclarationOrder, []string{"alpha", "status", "zeta"}, []string{"open", "closed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := orderedSchema(tt.requiredOrder, tt.enumOrder)
+			required, enums := schemaLists(t, s)
+			if !reflect.DeepEqual(required, tt.wantRequired) {
+				t.Errorf("Expected required %v, got %v", tt.wantRequired, required)
+			}
+			if !reflect.DeepEqual(enums, tt.wantEnums) {
+				t.Errorf("Expected enums %v, got %v", tt.wantEnums, enums)
+			}
+			if s.Fields[0].ValueName != "zeta" {
+				t.Error("Expected ordering to leave the schema fields untouched")
+			}
+		})
+	}
+}
+
+func TestListOrderStrict(t *testing.T) {
+	s := &Schema{
+		Name:      "Ordered",
+		EnumOrder: SortedOrder,
+		Fields: []*Field{
+			AnyOf("status", []ConstDescription{
+				{Const: "open"},
+				{Const: "closed"},
+			}),
+		},
+	}
+
+	anyOf := s.Strict()["properties"].(map[string]interface{})["status"].(map[string]interface{})["anyOf"].([]interface{})
+	if len(anyOf) != 3 {
+		t.Fatalf("Expected two options and null, got %v", anyOf)
+	}
+	if anyOf[0].(map[string]interface{})["const"] != "closed" || anyOf[1].(map[string]interface{})["const"] != "open" {
+		t.Errorf("Expected sorted const options, got %v", anyOf)
+	}
+	if anyOf[2].(map[string]interface{})["type"] != "null" {
+		t.Errorf("Expected null option last, got %v", anyOf)
+	}
+}
+
+func TestEmptyRequired(t *testing.T) {
+	s := &Schema{
+		Name: "Optional",
+		Fields: []*Field{
+			Text("note"),
+			Object("source", []*Field{Text("name")}),
+			Array("tags", []*Field{Text("label")}),
+		},
+	}
+
+	var document struct {
+		Definitions map[string]map[string]json.RawMessage `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(s.GetSchemaString()), &document); err != nil {
+		t.Fatalf("Failed to decode schema: %v", err)
+	}
+	definition := document.Definitions[s.Name]
+	var properties map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(definition["properties"], &properties); err != nil {
+		t.Fatalf("Failed to decode properties: %v", err)
+	}
+	if _, ok := definition["required"]; ok {
+		t.Errorf("Expected required to be omitted, got %s", definition["required"])
+	}
+	if _, ok := properties["source"]["required"]; ok {
+		t.Errorf("Expected nested required to be omitted, got %s", properties["source"]["required"])
+	}
+	var items map[string]json.RawMessage
+	if err := json.Unmarshal(properties["tags"]["items"], &items); err != nil {
+		t.Fatalf("Failed to decode items: %v", err)
+	}
+	if string(items["required"]) != "[]" {
+		t.Errorf("Expected array items to keep an empty required list, got %s", items["required"])
+	}
+
+	applied, _, err := ProviderOpenAI.Apply(s)
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	source := applied["properties"].(map[string]interface{})["source"].(map[string]interface{})
+	if required, ok := applied["required"].([]interface{}); !ok || len(required) != 0 {
+		t.Errorf("Expected an empty required list for OpenAI, got %v", applied["required"])
+	}
+	if required, ok := source["required"].([]interface{}); !ok || len(required) != 0 {
+		t.Errorf("Expected an empty nested required list for OpenAI, got %v", source["required"])
+	}
+
+	applied, _, err = ProviderAnthropic.Apply(s)
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if _, ok := applied["required"]; ok {
+		t.Errorf("Expected required to be omitted for Anthropic, got %v", applied["required"])
+	}
+}
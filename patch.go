@@ -0,0 +1,837 @@
+package jobj
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MergePatch returns a copy of the schema with an RFC 7386 JSON Merge Patch applied. The receiver
+// is not modified.
+//
+// The patch is applied to the schema's JSON Schema object form:
+//
+//	{
+//	  "description": "...",
+//	  "properties": {"headline": {"type": "string", "description": "..."}},
+//	  "required": ["headline"]
+//	}
+//
+// so {"properties": {"summary": {"type": "string"}}} adds a field and
+// {"properties": {"headline": null}} removes one. Existing fields keep their position; new fields
+// are appended in name order. Merge patches replace arrays wholesale, so a patch that changes
+// "required" or an "anyOf" list must restate it in full. For non-struct schemas (RootField set) the
+// document is the root field's schema.
+func (r *Schema) MergePatch(patch []byte) (*Schema, error) {
+	patchValue, err := decodeDocument(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	document, err := r.document()
+	if err != nil {
+		return nil, err
+	}
+	return r.fromDocument(mergePatch(document, patchValue))
+}
+
+// ApplyPatch returns a copy of the schema with a patch applied, so external systems can adjust a
+// schema (tighten an enum, change a description) without Go code changes. A JSON array is applied
+// as an RFC 6902 JSON Patch and a JSON object as an RFC 7386 JSON Merge Patch (see MergePatch,
+// which also describes the document being patched). For example:
+//
+//	[
+//	  {"op": "replace", "path": "/properties/headline/description", "value": "The exact headline"},
+//	  {"op": "remove", "path": "/properties/severity/anyOf/2"}
+//	]
+//
+// The receiver is not modified, and a JSON Patch whose operations fail leaves no partial changes.
+func (r *Schema) ApplyPatch(patch []byte) (*Schema, error) {
+	patchValue, err := decodeDocument(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+
+	document, err := r.document()
+	if err != nil {
+		return nil, err
+	}
+
+	switch operations := patchValue.(type) {
+	case map[string]interface{}:
+		return r.fromDocument(mergePatch(document, operations))
+	case []interface{}:
+		patched, err := jsonPatch(document, operations)
+		if err != nil {
+			return nil, err
+		}
+		return r.fromDocument(patched)
+	default:
+		return nil, fmt.Errorf("invalid patch: expected a JSON Patch array or a JSON Merge Patch object")
+	}
+}
+
+// jsonPatch implements RFC 6902 on a document of generic JSON values.
+func jsonPatch(document interface{}, operations []interface{}) (interface{}, error) {
+	for i, raw := range operations {
+		operation, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("patch operation %d must be a JSON object", i)
+		}
+		op, _ := operation["op"].(string)
+		path, ok := operation["path"].(string)
+		if !ok {
+			return nil, fmt.Errorf("patch operation %d: missing path", i)
+		}
+		value, hasValue := operation["value"]
+		from, _ := operation["from"].(string)
+
+		var err error
+		switch op {
+		case "add", "replace", "test":
+			if !hasValue {
+				return nil, fmt.Errorf("patch operation %d (%s): missing value", i, op)
+			}
+		case "move", "copy":
+			if _, ok := operation["from"].(string); !ok {
+				return nil, fmt.Errorf("patch operation %d (%s): missing from", i, op)
+			}
+		}
+
+		switch op {
+		case "add":
+			document, err = pointerAdd(document, path, deepCopy(value))
+		case "remove":
+			document, _, err = pointerRemove(document, path)
+		case "replace":
+			if document, _, err = pointerRemove(document, path); err == nil {
+				document, err = pointerAdd(document, path, deepCopy(value))
+			}
+		case "move":
+			var moved interface{}
+			if document, moved, err = pointerRemove(document, from); err == nil {
+				document, err = pointerAdd(document, path, moved)
+			}
+		case "copy":
+			var copied interface{}
+			if copied, err = pointerGet(document, from); err == nil {
+				document, err = pointerAdd(document, path, deepCopy(copied))
+			}
+		case "test":
+			var current interface{}
+			if current, err = pointerGet(document, path); err == nil && !jsonEqual(current, value) {
+				err = fmt.Errorf("test failed at %s", path)
+			}
+		default:
+			err = fmt.Errorf("unknown op %q", op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("patch operation %d (%s): %w", i, op, err)
+		}
+	}
+	return document, nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON Pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func pointerGet(document interface{}, pointer string) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	current := document
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path %s does not exist", pointer)
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, fmt.Errorf("path %s: %w", pointer, err)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("path %s does not exist", pointer)
+		}
+	}
+	return current, nil
+}
+
+// pointerAdd adds value at pointer and returns the updated document. Arrays are rebuilt, so the
+// returned document must replace the one passed in.
+func pointerAdd(document interface{}, pointer string, value interface{}) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return updateParent(document, tokens, pointer, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			if token == "-" {
+				return append(node, value), nil
+			}
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		default:
+			return nil, fmt.Errorf("parent is not an object or array")
+		}
+	})
+}
+
+// pointerRemove removes the value at pointer and returns the updated document and the removed value.
+func pointerRemove(document interface{}, pointer string) (interface{}, interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the document root")
+	}
+
+	var removed interface{}
+	updated, err := updateParent(document, tokens, pointer, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path does not exist")
+			}
+			removed = value
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[index]
+			return append(node[:index:index], node[index+1:]...), nil
+		default:
+			return nil, fmt.Errorf("parent is not an object or array")
+		}
+	})
+	return updated, removed, err
+}
+
+// updateParent walks to the parent of the last token, applies update to it and stores the result
+// back into its own parent, returning the (possibly new) document root.
+func updateParent(document interface{}, tokens []string, pointer string, update func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		updated, err := update(document, tokens[0])
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", pointer, err)
+		}
+		return updated, nil
+	}
+
+	switch node := document.(type) {
+	case map[string]interface{}:
+		child, ok := node[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("path %s does not exist", pointer)
+		}
+		updated, err := updateParent(child, tokens[1:], pointer, update)
+		if err != nil {
+			return nil, err
+		}
+		node[tokens[0]] = updated
+		return node, nil
+	case []interface{}:
+		index, err := arrayIndex(tokens[0], len(node)-1)
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", pointer, err)
+		}
+		updated, err := updateParent(node[index], tokens[1:], pointer, update)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = updated
+		return node, nil
+	default:
+		return nil, fmt.Errorf("path %s does not exist", pointer)
+	}
+}
+
+// arrayIndex parses an array reference token, which must be between 0 and max inclusive.
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return index, nil
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+func jsonEqual(a interface{}, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+// mergePatch implements the RFC 7386 MergePatch algorithm.
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// document returns the schema in the JSON Schema object form that patches are applied to,
+// decoded into generic JSON values.
+func (r *Schema) document() (interface{}, error) {
+	var document map[string]interface{}
+	if r.RootField != nil {
+		document = fieldDocument(r.RootField)
+	} else {
+		document = objectDocument(r.Fields)
+		if r.Description != "" {
+			document["description"] = r.Description
+		}
+	}
+
+	// Round-trip through JSON so the document holds only generic JSON values
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema %q: %w", r.Name, err)
+	}
+	return decodeDocument(encoded)
+}
+
+// fromDocument builds a new Schema from a patched document, reusing the receiver's name and
+// field order.
+func (r *Schema) fromDocument(document interface{}) (*Schema, error) {
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("patched schema %q must be a JSON object", r.Name)
+	}
+
+	if r.RootField != nil {
+		root, err := documentField(r.RootField.ValueName, object, r.RootField.ValueRequired, r.RootField)
+		if err != nil {
+			return nil, err
+		}
+		return r.withFields(r.Description, nil, root), nil
+	}
+
+	description, _ := object["description"].(string)
+	fields, err := documentFields(object, r.Fields, "")
+	if err != nil {
+		return nil, err
+	}
+	return r.withFields(description, fields, nil), nil
+}
+
+// withFields returns a copy of the schema's metadata and serialization options with new content.
+func (r *Schema) withFields(description string, fields []*Field, root *Field) *Schema {
+	return &Schema{
+		Name:          r.Name,
+		Description:   description,
+		Fields:        fields,
+		RootField:     root,
+		SchemaID:      r.SchemaID,
+		Version:       r.Version,
+		RequiredOrder: r.RequiredOrder,
+		EnumOrder:     r.EnumOrder,
+
+		AdditionalProperties:      r.AdditionalProperties,
+		AdditionalPropertiesField: r.AdditionalPropertiesField,
+	}
+}
+
+func objectDocument(fields []*Field) map[string]interface{} {
+	properties := make(map[string]interface{}, len(fields))
+	required := make([]interface{}, 0, len(fields))
+	for _, field := range fields {
+		properties[field.ValueName] = fieldDocument(field)
+		if field.ValueRequired {
+			required = append(required, field.ValueName)
+		}
+	}
+	return map[string]interface{}{
+		"type":       string(TypeObject),
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func fieldDocument(field *Field) map[string]interface{} {
+	var document map[string]interface{}
+
+	switch {
+	case field.ValueAnyOf != nil:
+		anyOf := make([]interface{}, 0, len(field.ValueAnyOf))
+		for _, enum := range field.ValueAnyOf {
+			anyOf = append(anyOf, map[string]interface{}{
+				"const":       enum.Const,
+				"description": enum.Description,
+			})
+		}
+		document = map[string]interface{}{"anyOf": anyOf}
+	case field.ValueVariants != nil:
+		variants := make([]interface{}, 0, len(field.ValueVariants))
+		for _, variant := range field.ValueVariants {
+			variants = append(variants, fieldDocument(variant))
+		}
+		document = map[string]interface{}{variantsKeyword(field): variants}
+		addDiscriminator(field, document)
+	case field.ValueType == "":
+		document = map[string]interface{}{}
+	case field.ValueType == TypeArray && field.ArrayItemField != nil:
+		document = map[string]interface{}{
+			"type":  string(TypeArray),
+			"items": fieldDocument(field.ArrayItemField),
+		}
+	case field.ValueType == TypeArray && field.ArrayItemType != "":
+		document = map[string]interface{}{
+			"type":  string(TypeArray),
+			"items": map[string]interface{}{"type": string(field.ArrayItemType)},
+		}
+	case field.ValueType == TypeArray:
+		document = map[string]interface{}{
+			"type":  string(TypeArray),
+			"items": objectDocument(field.SubFields),
+		}
+	case field.ValueType == TypeObject && field.AdditionalProperties && field.AdditionalPropertiesType != "":
+		document = map[string]interface{}{
+			"type":                 string(TypeObject),
+			"additionalProperties": map[string]interface{}{"type": string(field.AdditionalPropertiesType)},
+		}
+		if field.AdditionalPropertiesField != nil {
+			document["additionalProperties"] = fieldDocument(field.AdditionalPropertiesField)
+		}
+	case field.ValueType == TypeObject && field.AdditionalProperties && field.AdditionalPropertiesField != nil:
+		document = map[string]interface{}{"type": string(TypeObject)}
+		if field.AdditionalPropertiesField.SubFields == nil {
+			document["additionalProperties"] = true
+		} else {
+			document["additionalProperties"] = objectDocument(field.AdditionalPropertiesField.SubFields)
+		}
+	case field.ValueType == TypeObject:
+		document = objectDocument(field.SubFields)
+	default:
+		document = map[string]interface{}{"type": string(field.ValueType)}
+		if field.ValueFormat != "" {
+			document["format"] = field.ValueFormat
+		}
+		if field.ValueContentEncoding != "" {
+			document["contentEncoding"] = field.ValueContentEncoding
+		}
+	}
+
+	if field.ValueDescription != "" {
+		document["description"] = field.ValueDescription
+	}
+	if field.ValueTitle != "" {
+		document["title"] = field.ValueTitle
+	}
+	if field.ValueDefault != nil {
+		document["default"] = field.ValueDefault
+	}
+	if len(field.ValueExamples) > 0 {
+		document["examples"] = field.ValueExamples
+	}
+	if field.ValueDeprecated {
+		document["deprecated"] = true
+	}
+	addCardinality(field, document)
+	addBounds(field, document)
+	addItemsDescription(field, document)
+	addNullable(field, document)
+	return document
+}
+
+// previousVariant returns the i-th variant of previous, which may be nil, so a variant keeps the
+// order of its properties through a patch.
+func previousVariant(previous *Field, i int) *Field {
+	if previous == nil || i >= len(previous.ValueVariants) {
+		return nil
+	}
+	return previous.ValueVariants[i]
+}
+
+// withoutNull returns property without what makes it nullable, a "null" entry in a type list or
+// a {"type": "null"} option in anyOf or oneOf, and whether it had one.
+func withoutNull(property map[string]interface{}) (map[string]interface{}, bool) {
+	var rest []interface{}
+	switch typeNames := property["type"].(type) {
+	case []interface{}:
+		rest = typeNames
+	case []string:
+		for _, typeName := range typeNames {
+			rest = append(rest, typeName)
+		}
+	}
+	if rest != nil {
+		kept := make([]interface{}, 0, len(rest))
+		for _, typeName := range rest {
+			if typeName != "null" {
+				kept = append(kept, typeName)
+			}
+		}
+		if len(kept) == 1 && len(rest) == 2 {
+			copied := copyProperty(property)
+			copied["type"] = kept[0]
+			return copied, true
+		}
+		return property, false
+	}
+
+	keyword := "anyOf"
+	if _, ok := property["oneOf"]; ok {
+		keyword = "oneOf"
+	}
+	anyOf, ok := property[keyword].([]interface{})
+	if !ok {
+		return property, false
+	}
+	kept := make([]interface{}, 0, len(anyOf))
+	for _, option := range anyOf {
+		if optionObject, ok := option.(map[string]interface{}); ok && len(optionObject) == 1 && optionObject["type"] == "null" {
+			continue
+		}
+		kept = append(kept, option)
+	}
+	if len(kept) == len(anyOf) || len(kept) == 0 {
+		return property, false
+	}
+	copied := copyProperty(property)
+	copied[keyword] = kept
+	return copied, true
+}
+
+func copyProperty(property map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(property))
+	for key, value := range property {
+		copied[key] = value
+	}
+	return copied
+}
+
+// documentFields converts the properties of an object document back into Fields. Fields present
+// in previous keep their order; new fields follow in name order.
+func documentFields(object map[string]interface{}, previous []*Field, path string) ([]*Field, error) {
+	properties, _ := object["properties"].(map[string]interface{})
+
+	required := make(map[string]bool)
+	if list, ok := object["required"].([]interface{}); ok {
+		for _, name := range list {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	previousByName := make(map[string]*Field, len(previous))
+	for _, field := range previous {
+		previousByName[field.ValueName] = field
+		if _, ok := properties[field.ValueName]; ok {
+			names = append(names, field.ValueName)
+		}
+	}
+	added := make([]string, 0)
+	for name := range properties {
+		if _, ok := previousByName[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	names = append(names, added...)
+
+	fields := make([]*Field, 0, len(names))
+	for _, name := range names {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("property %s/%s must be a JSON object", path, name)
+		}
+		field, err := documentField(name, property, required[name], previousByName[name])
+		if err != nil {
+			return nil, fmt.Errorf("property %s/%s: %w", path, name, err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func documentField(name string, property map[string]interface{}, required bool, previous *Field) (*Field, error) {
+	var previousSubFields []*Field
+	if previous != nil {
+		previousSubFields = previous.SubFields
+	}
+
+	property, nullable := withoutNull(property)
+
+	var field *Field
+	if oneOf, ok := property["oneOf"].([]interface{}); ok {
+		variants := make([]*Field, 0, len(oneOf))
+		for i, option := range oneOf {
+			optionObject, ok := option.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("oneOf entries must be JSON objects")
+			}
+			variant, err := documentField("", optionObject, false, previousVariant(previous, i))
+			if err != nil {
+				return nil, err
+			}
+			variants = append(variants, variant)
+		}
+		field = Variants(name, variants...)
+		if discriminator, ok := property["discriminator"].(map[string]interface{}); ok {
+			field.ValueDiscriminator, _ = discriminator["propertyName"].(string)
+		}
+	} else if anyOf, ok := property["anyOf"].([]interface{}); ok {
+		enums := make([]ConstDescription, 0, len(anyOf))
+		var variants []*Field
+		for _, option := range anyOf {
+			optionObject, ok := option.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("anyOf entries must be JSON objects")
+			}
+			// Entries without a const are the schemas of Variants
+			if _, isConst := optionObject["const"]; !isConst {
+				variant, err := documentField("", optionObject, false, previousVariant(previous, len(variants)))
+				if err != nil {
+					return nil, err
+				}
+				variants = append(variants, variant)
+				continue
+			}
+			description, _ := optionObject["description"].(string)
+			enums = append(enums, ConstDescription{Const: documentConst(optionObject["const"]), Description: description})
+		}
+		switch {
+		case variants == nil:
+			field = AnyOf(name, enums)
+		case len(enums) == 0:
+			field = Variants(name, variants...)
+		default:
+			return nil, fmt.Errorf("anyOf must list either consts or schemas, not both")
+		}
+	} else {
+		dataType, _ := property["type"].(string)
+		switch DataType(dataType) {
+		case "":
+			field = Any(name)
+		case TypeString, TypeNumber, TypeInteger, TypeBoolean:
+			field = &Field{ValueName: name, ValueType: DataType(dataType)}
+			field.ValueFormat, _ = property["format"].(string)
+			field.ValueContentEncoding, _ = property["contentEncoding"].(string)
+		case TypeArray:
+			items, ok := property["items"].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("array must define items")
+			}
+			itemType, _ := items["type"].(string)
+			// Items with nothing but a primitive type map to ArrayOf; anything richer keeps a full items field
+			if itemType != string(TypeObject) && (itemType == string(TypeArray) || len(items) > 1 || itemType == "") {
+				var previousItems *Field
+				if previous != nil {
+					previousItems = previous.ArrayItemField
+				}
+				itemField, err := documentField("", items, false, previousItems)
+				if err != nil {
+					return nil, err
+				}
+				field = ArrayOfField(name, itemField)
+			} else if itemType == string(TypeObject) {
+				subFields, err := documentFields(items, previousSubFields, "/"+name)
+				if err != nil {
+					return nil, err
+				}
+				field = Array(name, subFields)
+			} else {
+				field = ArrayOf(name, DataType(itemType))
+			}
+		case TypeObject:
+			switch additional := property["additionalProperties"].(type) {
+			case bool:
+				if additional {
+					field = MapOf(name, &Field{ValueType: TypeObject})
+				}
+			case map[string]interface{}:
+				valueType, _ := additional["type"].(string)
+				if valueType == "" {
+					return nil, fmt.Errorf("additionalProperties must define a type")
+				}
+				if valueType != string(TypeObject) && len(additional) == 1 {
+					field = Map(name, DataType(valueType))
+				} else if valueType != string(TypeObject) {
+					// Primitive values with keywords beyond their type, e.g. a minimum
+					var previousValue *Field
+					if previous != nil {
+						previousValue = previous.AdditionalPropertiesField
+					}
+					valueField, err := documentField("", additional, false, previousValue)
+					if err != nil {
+						return nil, err
+					}
+					field = MapOf(name, valueField)
+				} else {
+					var previousValueFields []*Field
+					if previous != nil && previous.AdditionalPropertiesField != nil {
+						previousValueFields = previous.AdditionalPropertiesField.SubFields
+					}
+					subFields, err := documentFields(additional, previousValueFields, "/"+name)
+					if err != nil {
+						return nil, err
+					}
+					valueField := Object("", subFields)
+					if previous != nil {
+						keepFieldState(valueField, previous.AdditionalPropertiesField)
+					}
+					field = MapOf(name, valueField)
+				}
+			}
+			if field == nil {
+				subFields, err := documentFields(property, previousSubFields, "/"+name)
+				if err != nil {
+					return nil, err
+				}
+				field = Object(name, subFields)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported type %q", dataType)
+		}
+	}
+
+	field.ValueDescription, _ = property["description"].(string)
+	field.ValueTitle, _ = property["title"].(string)
+	field.ValueDeprecated, _ = property["deprecated"].(bool)
+	field.ValueNullable = nullable
+	field.ValueDefault = documentConst(property["default"])
+	if examples, ok := property["examples"].([]interface{}); ok {
+		field.ValueExamples = make([]interface{}, len(examples))
+		for i, example := range examples {
+			field.ValueExamples[i] = documentConst(example)
+		}
+	}
+	if minItems, ok := documentConst(property["minItems"]).(int); ok {
+		field.MinItems(minItems)
+	}
+	if maxItems, ok := documentConst(property["maxItems"]).(int); ok {
+		field.MaxItems(maxItems)
+	}
+	field.ValueUniqueItems, _ = property["uniqueItems"].(bool)
+	if minLength, ok := documentConst(property["minLength"]).(int); ok {
+		field.MinLength(minLength)
+	}
+	if maxLength, ok := documentConst(property["maxLength"]).(int); ok {
+		field.MaxLength(maxLength)
+	}
+	if minimum, ok := property["minimum"].(json.Number); ok {
+		if value, err := minimum.Float64(); err == nil {
+			field.Minimum(value)
+		}
+	}
+	if maximum, ok := property["maximum"].(json.Number); ok {
+		if value, err := maximum.Float64(); err == nil {
+			field.Maximum(value)
+		}
+	}
+	if items, ok := property["items"].(map[string]interface{}); ok && field.ValueType == TypeArray && field.ArrayItemField == nil {
+		field.ValueItemsDescription, _ = items["description"].(string)
+	}
+	field.ValueRequired = required
+	keepFieldState(field, previous)
+	return field, nil
+}
+
+// keepFieldState copies the state of previous that has no JSON form, such as AsXMLAttribute and
+// Definition, onto field rebuilt from its document, so a patch only changes what it names.
+func keepFieldState(field *Field, previous *Field) {
+	if previous == nil {
+		return
+	}
+	field.Value = previous.Value
+	field.ValueXMLAttribute = previous.ValueXMLAttribute
+	field.ValueDefinition = previous.ValueDefinition
+	field.ValueRef = previous.ValueRef
+}
+
+// documentConst converts a decoded JSON number back to an int or float64 so patched consts keep
+// the same Go types as hand-written ones.
+func documentConst(value interface{}) interface{} {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	if i, err := number.Int64(); err == nil {
+		return int(i)
+	}
+	f, _ := number.Float64()
+	return f
+}
+
+func decodeDocument(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,210 @@
+package jobj
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newPatchTestSchema() *Schema {
+	return &Schema{
+		Name:        "PatchTest",
+		Description: "Original description",
+		SchemaID:    "https://example.com/schemas/patch-test.json",
+		Version:     "1.0.0",
+		Fields: []*Field{
+			Text("headline").Desc("The headline").Required(),
+			AnyOf("severity", []ConstDescription{
+				{Const: 1, Description: "low"},
+				{Const: 2, Description: "high"},
+			}),
+			Array("quotes", []*Field{
+				Text("speaker").Required(),
+				Email("contact"),
+			}),
+			Map("counts", TypeInteger),
+			Float("confidence"),
+		},
+	}
+}
+
+func TestMergePatchRoundTrip(t *testing.T) {
+	s := newPatchTestSchema()
+
+	patched, err := s.MergePatch([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("MergePatch failed: %v", err)
+	}
+	if patched.GetSchemaString() != s.GetSchemaString() {
+		t.Errorf("Expected empty patch to preserve schema.\nwant: %s\ngot:  %s", s.GetSchemaString(), patched.GetSchemaString())
+	}
+	if patched.Fields[1].ValueAnyOf[0].Const != 1 {
+		t.Errorf("Expected integer const to survive round trip, got %#v", patched.Fields[1].ValueAnyOf[0].Const)
+	}
+}
+
+func TestApplyPatchKeepsFieldState(t *testing.T) {
+	id := Text("id").AsXMLAttribute().Required()
+	id.Value = "A-1"
+	s := &Schema{
+		Name: "PatchState",
+		Fields: []*Field{
+			id,
+			Text("legacy_code").Deprecated(),
+			Object("address", []*Field{Text("city").AsXMLAttribute()}).Definition("Address"),
+			MapOf("offices", Object("", []*Field{Text("city")}).Definition("Office")),
+			MapOf("quotas", Int("").Minimum(0)),
+		},
+	}
+
+	patched, err := s.ApplyPatch([]byte(`[]`))
+	if err != nil {
+		t.Fatalf("ApplyPatch failed: %v", err)
+	}
+	for i, field := range s.Fields {
+		if !reflect.DeepEqual(patched.Fields[i], field) {
+			t.Errorf("Expected empty patch to keep field %q.\nwant: %+v\ngot:  %+v", field.ValueName, field, patched.Fields[i])
+		}
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	s := newPatchTestSchema()
+
+	patched, err := s.MergePatch([]byte(`{
+		"description": "Patched description",
+		"properties": {
+			"headline": {"description": "The exact headline"},
+			"confidence": null,
+			"summary": {"type": "string", "description": "A short summary"}
+		},
+		"required": ["headline", "summary"]
+	}`))
+	if err != nil {
+		t.Fatalf("MergePatch failed: %v", err)
+	}
+
+	if patched.Description != "Patched description" {
+		t.Errorf("Expected patched description, got %q", patched.Description)
+	}
+
+	names := make([]string, 0, len(patched.Fields))
+	for _, field := range patched.Fields {
+		names = append(names, field.ValueName)
+	}
+	want := []string{"headline", "severity", "quotes", "counts", "summary"}
+	if len(names) != len(want) {
+		t.Fatalf("Expected fields %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("Expected fields %v, got %v", want, names)
+		}
+	}
+
+	if patched.Fields[0].ValueDescription != "The exact headline" || !patched.Fields[0].ValueRequired {
+		t.Errorf("Unexpected headline field: %+v", patched.Fields[0])
+	}
+	if !patched.Fields[4].ValueRequired || patched.Fields[4].ValueType != TypeString {
+		t.Errorf("Unexpected summary field: %+v", patched.Fields[4])
+	}
+	if patched.Fields[2].SubFields[1].ValueFormat != "email" {
+		t.Errorf("Expected nested format to be preserved, got %+v", patched.Fields[2].SubFields[1])
+	}
+
+	// The original schema is untouched
+	if s.Description != "Original description" || len(s.Fields) != 5 {
+		t.Errorf("MergePatch modified the receiver: %+v", s)
+	}
+}
+
+func TestMergePatchErrors(t *testing.T) {
+	s := newPatchTestSchema()
+
+	for _, patch := range []string{
+		`{`,
+		`[]`,
+		`{"properties": {"headline": {"type": "text"}}}`,
+		`{"properties": {"tags": {"type": "array"}}}`,
+	} {
+		if _, err := s.MergePatch([]byte(patch)); err == nil {
+			t.Errorf("Expected error for patch %s", patch)
+		}
+	}
+}
+
+func TestApplyPatchJSONPatch(t *testing.T) {
+	s := newPatchTestSchema()
+
+	patched, err := s.ApplyPatch([]byte(`[
+		{"op": "test", "path": "/properties/headline/type", "value": "string"},
+		{"op": "replace", "path": "/properties/headline/description", "value": "The exact headline"},
+		{"op": "remove", "path": "/properties/severity/anyOf/1"},
+		{"op": "add", "path": "/properties/quotes/items/properties/role", "value": {"type": "string"}},
+		{"op": "add", "path": "/required/-", "value": "confidence"},
+		{"op": "copy", "from": "/properties/confidence", "path": "/properties/relevance"},
+		{"op": "move", "from": "/properties/counts", "path": "/properties/totals"}
+	]`))
+	if err != nil {
+		t.Fatalf("ApplyPatch failed: %v", err)
+	}
+
+	byName := make(map[string]*Field)
+	for _, field := range patched.Fields {
+		byName[field.ValueName] = field
+	}
+
+	if byName["headline"].ValueDescription != "The exact headline" {
+		t.Errorf("Expected replaced description, got %q", byName["headline"].ValueDescription)
+	}
+	if len(byName["severity"].ValueAnyOf) != 1 {
+		t.Errorf("Expected enum to be tightened to one value, got %v", byName["severity"].ValueAnyOf)
+	}
+	if len(byName["quotes"].SubFields) != 3 {
+		t.Errorf("Expected nested field to be added, got %+v", byName["quotes"].SubFields)
+	}
+	if !byName["confidence"].ValueRequired {
+		t.Error("Expected confidence to be required")
+	}
+	if byName["relevance"] == nil || byName["relevance"].ValueType != TypeNumber {
+		t.Errorf("Expected copied relevance field, got %+v", byName["relevance"])
+	}
+	if byName["counts"] != nil || byName["totals"] == nil || byName["totals"].AdditionalPropertiesType != TypeInteger {
+		t.Errorf("Expected counts to be moved to totals, got %+v", patched.Fields)
+	}
+}
+
+func TestApplyPatchMergePatch(t *testing.T) {
+	s := newPatchTestSchema()
+
+	patched, err := s.ApplyPatch([]byte(`{"properties": {"confidence": null}}`))
+	if err != nil {
+		t.Fatalf("ApplyPatch failed: %v", err)
+	}
+	if len(patched.Fields) != 4 {
+		t.Errorf("Expected merge patch to remove a field, got %d fields", len(patched.Fields))
+	}
+}
+
+func TestApplyPatchErrors(t *testing.T) {
+	s := newPatchTestSchema()
+
+	for _, patch := range []string{
+		`"replace"`,
+		`[{"op": "test", "path": "/properties/headline/type", "value": "integer"}]`,
+		`[{"op": "remove", "path": "/properties/missing"}]`,
+		`[{"op": "replace", "path": "/properties/headline/description"}]`,
+		`[{"op": "add", "path": "/required/5", "value": "x"}]`,
+		`[{"op": "move", "path": "/properties/x"}]`,
+		`[{"op": "frobnicate", "path": "/properties"}]`,
+		`[{"op": "remove", "path": "properties"}]`,
+	} {
+		if _, err := s.ApplyPatch([]byte(patch)); err == nil {
+			t.Errorf("Expected error for patch %s", patch)
+		}
+	}
+
+	// Failed patches leave the receiver unchanged
+	if s.Fields[0].ValueDescription != "The headline" {
+		t.Errorf("ApplyPatch modified the receiver: %+v", s.Fields[0])
+	}
+}
@@ -0,0 +1,298 @@
+package jobj
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ToPromptText renders the schema as a compact outline for prompts where a full JSON Schema is too
+// verbose. Each field takes one line, with nested fields indented below their parent:
+//
+//	HeadlinesResponse: Response schema for press release headline extraction
+//	- headline (string, required): The exact headline from the press release
+//	- sentiment (one of "positive", "negative")
+//	- quotes (array of object):
+//	  - speaker (string, required): Who said it
+func (r *Schema) ToPromptText() string {
+	var b strings.Builder
+	b.WriteString(r.Name)
+	if r.Description != "" {
+		b.WriteString(": ")
+		b.WriteString(r.Description)
+	}
+	b.WriteString("\n")
+
+	if r.RootField != nil {
+		writePromptFields(&b, []*Field{r.RootField}, 0)
+	} else {
+		writePromptFields(&b, r.Fields, 0)
+	}
+	return b.String()
+}
+
+// ToMarkdown renders the schema as a Markdown section: a heading with the schema name, the
+// description as a paragraph and the fields as the nested list used by ToPromptText.
+func (r *Schema) ToMarkdown() string {
+	var b strings.Builder
+	b.WriteString("## ")
+	b.WriteString(r.Name)
+	b.WriteString("\n\n")
+	if r.Description != "" {
+		b.WriteString(r.Description)
+		b.WriteString("\n\n")
+	}
+
+	if r.RootField != nil {
+		writePromptFields(&b, []*Field{r.RootField}, 0)
+	} else {
+		writePromptFields(&b, r.Fields, 0)
+	}
+	return b.String()
+}
+
+// ToXMLTagTemplate renders the schema as a tag-per-field answer template, the XML prompting
+// pattern where the model fills in each tag:
+//
+//	<!-- The exact headline from the press release -->
+//	<headline>...</headline>
+//	<!-- one of "positive", "negative" -->
+//	<sentiment>...</sentiment>
+//	<quotes>
+//	  <item>
+//	    <!-- Who said it -->
+//	    <speaker>...</speaker>
+//	  </item>
+//	</quotes>
+//
+// Fields become sibling tags with no root element, descriptions and enum options become XML
+// comments and nesting follows ToXMLExample: arrays hold one "item", maps one "key" entry and
+// fields marked with AsXMLAttribute are attributes. A schema with a RootField renders it as a
+// single tag named after the schema.
+func (r *Schema) ToXMLTagTemplate() string {
+	var b strings.Builder
+	if r.RootField != nil {
+		writeXMLTagTemplate(&b, r.RootField.renamed(r.Name), 0)
+		return b.String()
+	}
+	for _, field := range r.Fields {
+		writeXMLTagTemplate(&b, field, 0)
+	}
+	return b.String()
+}
+
+func writeXMLTagTemplate(b *strings.Builder, field *Field, depth int) {
+	indent := strings.Repeat("  ", depth)
+	name := xmlEscape(field.ValueName)
+
+	if comment := xmlTagComment(field); comment != "" {
+		b.WriteString(indent + "<!-- " + comment + " -->\n")
+	}
+	if xsdSimpleType(field) != "" {
+		b.WriteString(indent + "<" + name + ">...</" + name + ">\n")
+		return
+	}
+
+	attributes, children := xmlContent(field)
+	b.WriteString(indent + "<" + name)
+	for _, attribute := range attributes {
+		b.WriteString(" " + xmlEscape(attribute.ValueName) + `="..."`)
+	}
+	if len(children) == 0 {
+		b.WriteString("/>\n")
+		return
+	}
+	b.WriteString(">\n")
+	for _, child := range children {
+		writeXMLTagTemplate(b, child, depth+1)
+	}
+	b.WriteString(indent + "</" + name + ">\n")
+}
+
+// xmlTagComment returns the comment shown above a field's tag: its description, the options of
+// an enum and the descriptions of fields rendered as attributes.
+func xmlTagComment(field *Field) string {
+	var parts []string
+	if field.ValueDescription != "" {
+		parts = append(parts, field.ValueDescription)
+	}
+	if field.ValueAnyOf != nil {
+		parts = append(parts, promptType(field))
+	}
+	if xsdSimpleType(field) == "" {
+		attributes, _ := xmlContent(field)
+		for _, attribute := range attributes {
+			if comment := xmlTagComment(attribute); comment != "" {
+				parts = append(parts, attribute.ValueName+": "+comment)
+			}
+		}
+	}
+
+	// "--" may not appear inside an XML comment
+	return strings.ReplaceAll(strings.Join(parts, "; "), "--", "- -")
+}
+
+func writePromptFields(b *strings.Builder, fields []*Field, depth int) {
+	for _, field := range fields {
+		b.WriteString(strings.Repeat("  ", depth))
+		b.WriteString("- ")
+		b.WriteString(field.ValueName)
+		b.WriteString(" (")
+		b.WriteString(promptType(field))
+		if field.ValueRequired {
+			b.WriteString(", required")
+		}
+		if field.ValueDefault != nil {
+			b.WriteString(", default ")
+			b.WriteString(promptValue(field.ValueDefault))
+		}
+		if field.hasCardinality() {
+			b.WriteString(promptCardinality(field))
+		}
+		if field.hasBounds() {
+			b.WriteString(promptBounds(field))
+		}
+		if field.ValueDeprecated {
+			b.WriteString(", deprecated")
+		}
+		b.WriteString(")")
+
+		children := promptChildren(field)
+		switch {
+		case field.ValueDescription != "":
+			b.WriteString(": ")
+			b.WriteString(field.ValueDescription)
+		case len(children) > 0:
+			b.WriteString(":")
+		}
+		if len(field.ValueExamples) > 0 {
+			examples := make([]string, 0, len(field.ValueExamples))
+			for _, example := range field.ValueExamples {
+				examples = append(examples, promptValue(example))
+			}
+			if field.ValueDescription != "" {
+				b.WriteString(";")
+			} else if len(children) == 0 {
+				b.WriteString(":")
+			}
+			b.WriteString(" e.g. ")
+			b.WriteString(strings.Join(examples, ", "))
+		}
+		b.WriteString("\n")
+
+		writePromptFields(b, children, depth+1)
+	}
+}
+
+// promptType describes the type of a field in a few words, e.g. "array of string" or "string, date-time".
+func promptType(field *Field) string {
+	if field.ValueAnyOf != nil {
+		options := make([]string, 0, len(field.ValueAnyOf))
+		for _, option := range field.ValueAnyOf {
+			options = append(options, promptValue(option.Const))
+		}
+		return "one of " + strings.Join(options, ", ")
+	}
+	if field.ValueVariants != nil {
+		variants := make([]string, 0, len(field.ValueVariants))
+		for _, variant := range field.ValueVariants {
+			variants = append(variants, promptType(variant))
+		}
+		return strings.Join(variants, " or ")
+	}
+
+	switch {
+	case field.ValueType == "":
+		return "any"
+	case field.ValueType == TypeArray && field.ArrayItemField != nil:
+		return "array of " + promptType(field.ArrayItemField)
+	case field.ValueType == TypeArray && field.SubFields != nil:
+		return "array of object"
+	case field.ValueType == TypeArray && field.ArrayItemType != "":
+		return "array of " + string(field.ArrayItemType)
+	case field.ValueType == TypeObject && field.AdditionalProperties && field.AdditionalPropertiesField != nil:
+		return "map of " + promptType(field.AdditionalPropertiesField)
+	case field.ValueType == TypeObject && field.AdditionalProperties && field.AdditionalPropertiesType != "":
+		return "map of " + string(field.AdditionalPropertiesType)
+	case field.ValueType == TypeObject && field.AdditionalProperties:
+		return "map"
+	}
+
+	label := string(field.ValueType)
+	if field.ValueFormat != "" {
+		label += ", " + field.ValueFormat
+	}
+	if field.ValueContentEncoding != "" {
+		label += ", " + field.ValueContentEncoding
+	}
+	return label
+}
+
+// promptCardinality describes the item count and uniqueness constraints of an array field, e.g.
+// ", 3 to 5 items, unique".
+func promptCardinality(field *Field) string {
+	var label string
+	switch {
+	case field.ValueMinItems != nil && field.ValueMaxItems != nil && *field.ValueMinItems == *field.ValueMaxItems:
+		label = fmt.Sprintf(", exactly %d items", *field.ValueMinItems)
+	case field.ValueMinItems != nil && field.ValueMaxItems != nil:
+		label = fmt.Sprintf(", %d to %d items", *field.ValueMinItems, *field.ValueMaxItems)
+	case field.ValueMinItems != nil:
+		label = fmt.Sprintf(", at least %d items", *field.ValueMinItems)
+	case field.ValueMaxItems != nil:
+		label = fmt.Sprintf(", at most %d items", *field.ValueMaxItems)
+	}
+	if field.ValueUniqueItems {
+		label += ", unique"
+	}
+	return label
+}
+
+// promptBounds describes the length constraints of a string field, e.g. ", at most 80 characters",
+// or the range of a number field, e.g. ", 1 to 10".
+func promptBounds(field *Field) string {
+	if field.ValueType == TypeString {
+		switch {
+		case field.ValueMinLength != nil && field.ValueMaxLength != nil:
+			return fmt.Sprintf(", %d to %d characters", *field.ValueMinLength, *field.ValueMaxLength)
+		case field.ValueMinLength != nil:
+			return fmt.Sprintf(", at least %d characters", *field.ValueMinLength)
+		case field.ValueMaxLength != nil:
+			return fmt.Sprintf(", at most %d characters", *field.ValueMaxLength)
+		}
+		return ""
+	}
+	switch {
+	case field.ValueMinimum != nil && field.ValueMaximum != nil:
+		return fmt.Sprintf(", %s to %s", formatBound(*field.ValueMinimum), formatBound(*field.ValueMaximum))
+	case field.ValueMinimum != nil:
+		return ", at least " + formatBound(*field.ValueMinimum)
+	case field.ValueMaximum != nil:
+		return ", at most " + formatBound(*field.ValueMaximum)
+	}
+	return ""
+}
+
+// promptValue renders a value for people and models to read as JSON, e.g. "fast" with its quotes.
+func promptValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
+
+// promptChildren returns the fields listed below field in the outline.
+func promptChildren(field *Field) []*Field {
+	switch {
+	case field.ValueAnyOf != nil, field.ValueVariants != nil:
+		return nil
+	case field.ValueType == TypeArray && field.ArrayItemField != nil:
+		return promptChildren(field.ArrayItemField)
+	case field.ValueType == TypeObject && field.AdditionalProperties && field.AdditionalPropertiesField != nil:
+		return field.AdditionalPropertiesField.SubFields
+	case field.ValueType == TypeObject && field.AdditionalProperties:
+		return nil
+	}
+	return field.SubFields
+}
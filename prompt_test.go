@@ -0,0 +1,102 @@
+package jobj
+
+import (
+	"testing"
+)
+
+func TestToPromptText(t *testing.T) {
+	s := &Schema{
+		Name:        "PressRelease",
+		Description: "Press release extraction",
+		Fields: []*Field{
+			Text("headline").Desc("The exact headline").Required(),
+			AnyOf("severity", []ConstDescription{
+				{Const: 1, Description: "low"},
+				{Const: "high", Description: "high"},
+			}),
+			ArrayOf("tags", TypeString),
+			Array("quotes", []*Field{
+				Text("speaker").Desc("Who said it").Required(),
+				DateTime("said_at"),
+			}).Desc("Quotes from the release"),
+			Map("counts", TypeInteger),
+			Bytes("logo").Deprecated(),
+		},
+	}
+
+	expected := `PressRelease: Press release extraction
+- headline (string, required): The exact headline
+- severity (one of 1, "high")
+- tags (array of string)
+- quotes (array of object): Quotes from the release
+  - speaker (string, required): Who said it
+  - said_at (string, date-time)
+- counts (map of integer)
+- logo (string, base64, deprecated)
+`
+	if got := s.ToPromptText(); got != expected {
+		t.Errorf("Unexpected prompt text.\nwant:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestToPromptTextNested(t *testing.T) {
+	s := &Schema{
+		Name: "Matrix",
+		Fields: []*Field{
+			ArrayOfField("rows", ArrayOf("", TypeNumber)).Required(),
+			MapOf("people", Object("", []*Field{Text("name")})),
+		},
+	}
+
+	expected := `Matrix
+- rows (array of array of number, required)
+- people (map of object):
+  - name (string)
+`
+	if got := s.ToPromptText(); got != expected {
+		t.Errorf("Unexpected prompt text.\nwant:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestToXMLTagTemplate(t *testing.T) {
+	s := &Schema{
+		Name: "PressRelease",
+		Fields: []*Field{
+			Text("headline").Desc("The exact headline").Required(),
+			AnyOf("sentiment", []ConstDescription{{Const: "positive"}, {Const: "negative"}}).Desc("Overall tone"),
+			ArrayOf("tags", TypeString),
+			Array("quotes", []*Field{
+				Int("id").AsXMLAttribute().Desc("Quote number"),
+				Text("speaker").Desc("Who said it -- exactly"),
+			}),
+			Map("counts", TypeInteger),
+		},
+	}
+
+	expected := `<!-- The exact headline -->
+<headline>...</headline>
+<!-- Overall tone; one of "positive", "negative" -->
+<sentiment>...</sentiment>
+<tags>
+  <item>...</item>
+</tags>
+<quotes>
+  <!-- id: Quote number -->
+  <item id="...">
+    <!-- Who said it - - exactly -->
+    <speaker>...</speaker>
+  </item>
+</quotes>
+<counts>
+  <key>...</key>
+</counts>
+`
+	if got := s.ToXMLTagTemplate(); got != expected {
+		t.Errorf("Unexpected tag template.\nwant:\n%s\ngot:\n%s", expected, got)
+	}
+
+	root := &Schema{Name: "Labels", RootField: ArrayOf("result", TypeString)}
+	if got := root.ToXMLTagTemplate(); got != "<Labels>\n  <item>...</item>\n</Labels>\n" {
+		t.Errorf("Unexpected root template: %s", got)
+	}
+}
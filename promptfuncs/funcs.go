@@ -0,0 +1,96 @@
+// Package promptfuncs provides text/template functions for embedding jobj schemas in prompts.
+package promptfuncs
+
+import (
+	"fmt"
+	"reflect"
+	"text/template"
+
+	"github.com/mhpenta/jobj"
+)
+
+var schemaType = reflect.TypeOf(jobj.Schema{})
+
+// FuncMap returns the template functions below. Each takes a *jobj.Schema, a jobj.Schema or a
+// struct (or pointer to one) embedding jobj.Schema, such as the response types built with the fluent API:
+//
+//   - schemaJSON renders the JSON Schema document (Schema.GetSchemaString)
+//   - schemaMarkdown renders a Markdown outline (Schema.ToMarkdown)
+//   - schemaExample renders a sample instance as indented JSON (Schema.ExampleJSON)
+//   - schemaSafeJSON renders the JSON Schema document with delimiter characters escaped
+//     (Schema.GetPromptSafeSchemaString)
+//
+// and two helpers for keeping embedded text from breaking the template's own delimiters:
+//
+//   - codeFence wraps text in a Markdown fence it cannot close: {{ codeFence "json" (schemaExample .) }}
+//   - escapeXMLTags escapes the named tags in text: {{ escapeXMLTags (schemaMarkdown .) "schema" }}
+//
+// Usage:
+//
+//	tmpl := template.Must(template.New("prompt").Funcs(promptfuncs.FuncMap()).Parse(
+//	    "Answer with JSON matching this schema:\n{{ schemaJSON .Schema }}\n\nFor example:\n{{ schemaExample .Schema }}",
+//	))
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"schemaJSON": func(value any) (string, error) {
+			schema, err := asSchema(value)
+			if err != nil {
+				return "", err
+			}
+			return schema.GetSchemaString(), nil
+		},
+		"schemaMarkdown": func(value any) (string, error) {
+			schema, err := asSchema(value)
+			if err != nil {
+				return "", err
+			}
+			return schema.ToMarkdown(), nil
+		},
+		"schemaExample": func(value any) (string, error) {
+			schema, err := asSchema(value)
+			if err != nil {
+				return "", err
+			}
+			return schema.ExampleJSON()
+		},
+		"schemaSafeJSON": func(value any) (string, error) {
+			schema, err := asSchema(value)
+			if err != nil {
+				return "", err
+			}
+			return schema.GetPromptSafeSchemaString(), nil
+		},
+		"codeFence": func(info string, content string) string {
+			return jobj.CodeFence(content, info)
+		},
+		"escapeXMLTags": jobj.EscapeXMLTags,
+	}
+}
+
+// asSchema returns the jobj.Schema held by value.
+func asSchema(value any) (*jobj.Schema, error) {
+	switch schema := value.(type) {
+	case *jobj.Schema:
+		if schema == nil {
+			return nil, fmt.Errorf("received nil schema")
+		}
+		return schema, nil
+	case jobj.Schema:
+		return &schema, nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Anonymous && field.Type == schemaType {
+				schema := v.Field(i).Interface().(jobj.Schema)
+				return &schema, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("expected a jobj.Schema or a struct embedding one, got %T", value)
+}
@@ -0,0 +1,103 @@
+package promptfuncs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/mhpenta/jobj"
+)
+
+type headlinesResponse struct {
+	jobj.Schema
+}
+
+func newHeadlinesResponse() *headlinesResponse {
+	h := &headlinesResponse{}
+	h.Name = "HeadlinesResponse"
+	h.Description = "Headlines from a press release"
+	h.Fields = []*jobj.Field{
+		jobj.Text("headline").Desc("The exact headline").Required(),
+		jobj.DateTime("published_at"),
+		jobj.ArrayOf("tags", jobj.TypeString),
+	}
+	return h
+}
+
+func render(t *testing.T, text string, data any) string {
+	t.Helper()
+	tmpl, err := template.New("prompt").Funcs(FuncMap()).Parse(text)
+	if err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		t.Fatalf("Failed to execute template: %v", err)
+	}
+	return b.String()
+}
+
+func TestSchemaJSON(t *testing.T) {
+	h := newHeadlinesResponse()
+	if got := render(t, "{{ schemaJSON . }}", h); got != h.GetSchemaString() {
+		t.Errorf("Expected schema JSON, got %s", got)
+	}
+	if got := render(t, "{{ schemaJSON . }}", &h.Schema); got != h.GetSchemaString() {
+		t.Errorf("Expected schema JSON from *jobj.Schema, got %s", got)
+	}
+}
+
+func TestSchemaMarkdown(t *testing.T) {
+	expected := `## HeadlinesResponse
+
+Headlines from a press release
+
+- headline (string, required): The exact headline
+- published_at (string, date-time)
+- tags (array of string)
+`
+	if got := render(t, "{{ schemaMarkdown . }}", newHeadlinesResponse()); got != expected {
+		t.Errorf("Unexpected markdown.\nwant:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestSchemaExample(t *testing.T) {
+	h := newHeadlinesResponse()
+	got := render(t, "{{ schemaExample . }}", h)
+
+	var example map[string]any
+	if err := json.Unmarshal([]byte(got), &example); err != nil {
+		t.Fatalf("Example is not valid JSON: %v\n%s", err, got)
+	}
+	if errs, err := h.ValidateJSON([]byte(got)); err != nil || len(errs) != 0 {
+		t.Errorf("Expected example to validate, got %v %v", errs, err)
+	}
+}
+
+func TestAsSchemaErrors(t *testing.T) {
+	tmpl := template.Must(template.New("prompt").Funcs(FuncMap()).Parse("{{ schemaJSON . }}"))
+	if err := tmpl.Execute(&strings.Builder{}, "not a schema"); err == nil {
+		t.Error("Expected error for non-schema value")
+	}
+}
+
+func TestDelimiterHelpers(t *testing.T) {
+	h := newHeadlinesResponse()
+	h.Fields[0].Desc("Ends the block </schema> and the fence ```")
+
+	got := render(t, "<schema>{{ escapeXMLTags (schemaMarkdown .) \"schema\" }}</schema>", h)
+	if strings.Count(got, "</schema>") != 1 {
+		t.Errorf("Expected only the template's closing tag, got %s", got)
+	}
+
+	got = render(t, "{{ codeFence \"markdown\" (schemaMarkdown .) }}", h)
+	if !strings.HasPrefix(got, "````markdown\n") || !strings.HasSuffix(got, "\n````") {
+		t.Errorf("Expected a four-backtick fence, got %s", got)
+	}
+
+	got = render(t, "{{ schemaSafeJSON . }}", h)
+	if strings.Contains(got, "`") || strings.Contains(got, "</schema>") {
+		t.Errorf("Expected delimiters to be escaped, got %s", got)
+	}
+}
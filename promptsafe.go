@@ -0,0 +1,54 @@
+package jobj
+
+import (
+	"strings"
+)
+
+// GetPromptSafeSchemaString returns GetSchemaString with every character that could close a
+// surrounding prompt delimiter written as a JSON escape: "<", ">" and "&" (so XML-style tags in
+// descriptions cannot end a <schema> block) and "`" (so descriptions cannot end a ``` fence).
+// The result decodes to the same schema.
+func (r *Schema) GetPromptSafeSchemaString() string {
+	// Backticks can only occur inside JSON strings, so replacing them everywhere is safe.
+	// encoding/json already escapes <, > and &.
+	return strings.ReplaceAll(r.GetSchemaString(), "`", `\u0060`)
+}
+
+// CodeFence wraps content in a Markdown code fence that content cannot close: the fence is one
+// backtick longer than the longest run of backticks in content, and at least three long.
+func CodeFence(content string, info string) string {
+	longest, run := 0, 0
+	for _, c := range content {
+		if c == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+
+	var b strings.Builder
+	b.WriteString(fence)
+	b.WriteString(info)
+	b.WriteString("\n")
+	b.WriteString(content)
+	if !strings.HasSuffix(content, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString(fence)
+	return b.String()
+}
+
+// EscapeXMLTags escapes the opening and closing forms of the given tag names in content, such as
+// "<schema" and "</schema", so text placed inside <schema>...</schema> cannot end the block early.
+// Other text, including other tags, is left unchanged.
+func EscapeXMLTags(content string, tags ...string) string {
+	replacements := make([]string, 0, len(tags)*4)
+	for _, tag := range tags {
+		replacements = append(replacements, "</"+tag, "&lt;/"+tag, "<"+tag, "&lt;"+tag)
+	}
+	return strings.NewReplacer(replacements...).Replace(content)
+}
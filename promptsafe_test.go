@@ -0,0 +1,57 @@
+package jobj
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGetPromptSafeSchemaString(t *testing.T) {
+	s := &Schema{
+		Name:        "Unsafe",
+		Description: "Ends the fence ``` and the </schema> tag",
+		Fields:      []*Field{Text("code").Desc("Use `backticks` & <tags>")},
+	}
+
+	safe := s.GetPromptSafeSchemaString()
+	for _, forbidden := range []string{"`", "<", ">"} {
+		if strings.Contains(safe, forbidden) {
+			t.Errorf("Expected %q to be escaped, got %s", forbidden, safe)
+		}
+	}
+
+	var safeDocument, document interface{}
+	if err := json.Unmarshal([]byte(safe), &safeDocument); err != nil {
+		t.Fatalf("Prompt-safe schema is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(s.GetSchemaString()), &document); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+	if !jsonEqual(safeDocument, document) {
+		t.Error("Expected prompt-safe schema to decode to the same document")
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"plain", "```json\nplain\n```"},
+		{"has ``` inside\n", "````json\nhas ``` inside\n````"},
+		{"`````", "``````json\n`````\n``````"},
+	}
+	for _, tt := range tests {
+		if got := CodeFence(tt.content, "json"); got != tt.want {
+			t.Errorf("CodeFence(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeXMLTags(t *testing.T) {
+	got := EscapeXMLTags("a </schema> b <schema> c <other>", "schema")
+	want := "a &lt;/schema> b &lt;schema> c <other>"
+	if got != want {
+		t.Errorf("EscapeXMLTags() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+package jobj
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry collects CreatableSchema implementations by name and builds them on demand.
+// Built schemas are cached, so CreateDescription and CreateFields run at most once per
+// registered name. A Registry is safe for concurrent use.
+//
+// Schemas can be layered per environment: Override attaches a JSON Merge Patch to a schema for a
+// named profile (e.g. "staging"), and BuildProfile resolves the base schema with that patch applied.
+//
+// Schemas can also be grouped per tenant: Namespace returns a view of the registry whose names do
+// not collide with those of other namespaces. A name of the form "namespace/name" is qualified and
+// refers to a schema in that namespace from the registry itself.
+type Registry struct {
+	mu        sync.Mutex
+	creators  map[string]CreatableSchema
+	built     map[string]*Schema
+	overrides map[profileKey][]byte
+	resolved  map[profileKey]*Schema
+}
+
+type profileKey struct {
+	profile string
+	name    string
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		creators:  make(map[string]CreatableSchema),
+		built:     make(map[string]*Schema),
+		overrides: make(map[profileKey][]byte),
+		resolved:  make(map[profileKey]*Schema),
+	}
+}
+
+// Register adds a CreatableSchema under name. It returns an error if name is empty or
+// already registered.
+func (r *Registry) Register(name string, creatable CreatableSchema) error {
+	if name == "" {
+		return fmt.Errorf("schema name must not be empty")
+	}
+	return r.register(name, creatable)
+}
+
+// register implements Register for an already checked name.
+func (r *Registry) register(name string, creatable CreatableSchema) error {
+	if creatable == nil {
+		return fmt.Errorf("schema %q: received nil CreatableSchema", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.registered(name) {
+		return fmt.Errorf("schema %q is already registered", name)
+	}
+	r.creators[name] = creatable
+	return nil
+}
+
+// RegisterSchema adds an already built Schema under its Name. Build returns it as is. It returns
+// an error if the name is empty or taken, or if Finalize rejects the schema.
+func (r *Registry) RegisterSchema(schema *Schema) error {
+	if schema == nil {
+		return fmt.Errorf("received nil schema")
+	}
+	if schema.Name == "" {
+		return fmt.Errorf("schema name must not be empty")
+	}
+	return r.registerSchema(schema.Name, schema)
+}
+
+// registerSchema implements RegisterSchema, registering schema under name.
+func (r *Registry) registerSchema(name string, schema *Schema) error {
+	if err := schema.Finalize(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.registered(name) {
+		return fmt.Errorf("schema %q is already registered", name)
+	}
+	r.built[name] = schema
+	return nil
+}
+
+// registered reports whether name is taken. The caller must hold r.mu.
+func (r *Registry) registered(name string) bool {
+	_, isCreator := r.creators[name]
+	_, isBuilt := r.built[name]
+	return isCreator || isBuilt
+}
+
+// Build returns the Schema registered under name, calling CreateDescription and
+// CreateFields on first use and returning the cached result afterwards. A created schema
+// that Finalize rejects is returned as an error and not cached. Schemas created for a qualified
+// name are named without their namespace.
+func (r *Registry) Build(name string) (*Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.build(name)
+}
+
+// build implements Build. The caller must hold r.mu.
+func (r *Registry) build(name string) (*Schema, error) {
+	if schema, ok := r.built[name]; ok {
+		return schema, nil
+	}
+
+	creatable, ok := r.creators[name]
+	if !ok {
+		return nil, fmt.Errorf("schema %q is not registered", name)
+	}
+
+	created := creatable.CreateDescription().CreateFields()
+	schema := &Schema{
+		Name:        localName(name),
+		Description: created.GetDescription(),
+		Fields:      created.GetFields(),
+	}
+	if err := schema.Finalize(); err != nil {
+		return nil, err
+	}
+	r.built[name] = schema
+	return schema, nil
+}
+
+// Override sets the JSON Merge Patch (RFC 7386, see Schema.MergePatch) applied to the schema
+// named name when it is built for profile. Setting an override replaces any earlier one for the
+// same profile and name.
+func (r *Registry) Override(profile string, name string, mergePatch []byte) error {
+	if profile == "" {
+		return fmt.Errorf("profile must not be empty")
+	}
+	if _, err := decodeDocument(mergePatch); err != nil {
+		return fmt.Errorf("override for schema %q in profile %q: invalid merge patch: %w", name, profile, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := profileKey{profile: profile, name: name}
+	r.overrides[key] = append([]byte(nil), mergePatch...)
+	delete(r.resolved, key)
+	return nil
+}
+
+// BuildProfile returns the schema named name as seen by profile: the base schema from Build with
+// the profile's override applied, if one is set. Profiles without an override share the base schema.
+func (r *Registry) BuildProfile(profile string, name string) (*Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	base, err := r.build(name)
+	if err != nil {
+		return nil, err
+	}
+
+	key := profileKey{profile: profile, name: name}
+	patch, ok := r.overrides[key]
+	if !ok {
+		return base, nil
+	}
+	if schema, ok := r.resolved[key]; ok {
+		return schema, nil
+	}
+
+	schema, err := base.MergePatch(patch)
+	if err != nil {
+		return nil, fmt.Errorf("override for schema %q in profile %q: %w", name, profile, err)
+	}
+	r.resolved[key] = schema
+	return schema, nil
+}
+
+// Names returns the registered schema names in sorted order. Schemas in a namespace are listed
+// by their qualified name.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.creators)+len(r.built))
+	for name := range r.creators {
+		names = append(names, name)
+	}
+	for name := range r.built {
+		if _, isCreator := r.creators[name]; !isCreator {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
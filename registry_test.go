@@ -0,0 +1,126 @@
+package jobj
+
+import (
+	"testing"
+)
+
+// countingSchema is a CreatableSchema that records how often it is built
+type countingSchema struct {
+	Schema
+	builds int
+}
+
+func (c *countingSchema) CreateDescription() CreatableSchema {
+	c.Description = "A counting schema"
+	return c
+}
+
+func (c *countingSchema) CreateFields() CreatableSchema {
+	c.builds++
+	c.Fields = []*Field{
+		Text("name").Desc("The name field").Required(),
+	}
+	return c
+}
+
+func TestRegistryBuildCaches(t *testing.T) {
+	registry := NewRegistry()
+	creatable := &countingSchema{}
+
+	if err := registry.Register("Counting", creatable); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	first, err := registry.Build("Counting")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	second, err := registry.Build("Counting")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if first != second {
+		t.Error("Expected cached schema to be returned on second Build")
+	}
+	if creatable.builds != 1 {
+		t.Errorf("Expected CreateFields to run once, ran %d times", creatable.builds)
+	}
+	if first.Name != "Counting" || first.Description != "A counting schema" || len(first.Fields) != 1 {
+		t.Errorf("Unexpected built schema: %+v", first)
+	}
+}
+
+func TestRegistryErrors(t *testing.T) {
+	registry := NewRegistry()
+
+	if err := registry.Register("", &countingSchema{}); err == nil {
+		t.Error("Expected error for empty name")
+	}
+	if err := registry.Register("A", &countingSchema{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := registry.Register("A", &countingSchema{}); err == nil {
+		t.Error("Expected error for duplicate name")
+	}
+	if _, err := registry.Build("missing"); err == nil {
+		t.Error("Expected error for unregistered name")
+	}
+
+	names := registry.Names()
+	if len(names) != 1 || names[0] != "A" {
+		t.Errorf("Unexpected names: %v", names)
+	}
+}
+
+func TestRegistryProfiles(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.RegisterSchema(&Schema{
+		Name:   "Headlines",
+		Fields: []*Field{Text("headline").Required()},
+	}); err != nil {
+		t.Fatalf("RegisterSchema failed: %v", err)
+	}
+
+	if err := registry.Override("staging", "Headlines", []byte(`{"properties": {"summary": {"type": "string"}}}`)); err != nil {
+		t.Fatalf("Override failed: %v", err)
+	}
+	if err := registry.Override("staging", "Headlines", []byte(`{`)); err == nil {
+		t.Error("Expected error for invalid merge patch")
+	}
+
+	production, err := registry.BuildProfile("production", "Headlines")
+	if err != nil {
+		t.Fatalf("BuildProfile failed: %v", err)
+	}
+	if len(production.Fields) != 1 {
+		t.Errorf("Expected production to use the base schema, got %d fields", len(production.Fields))
+	}
+
+	staging, err := registry.BuildProfile("staging", "Headlines")
+	if err != nil {
+		t.Fatalf("BuildProfile failed: %v", err)
+	}
+	if len(staging.Fields) != 2 || staging.Fields[1].ValueName != "summary" {
+		t.Errorf("Expected staging to add a summary field, got %+v", staging.Fields)
+	}
+
+	again, _ := registry.BuildProfile("staging", "Headlines")
+	if again != staging {
+		t.Error("Expected resolved profile schema to be cached")
+	}
+}
+
+func TestRegistryRejectsDuplicateFields(t *testing.T) {
+	registry := NewRegistry()
+	err := registry.RegisterSchema(&Schema{
+		Name:   "Duplicated",
+		Fields: []*Field{Text("name"), Text("name")},
+	})
+	if err == nil {
+		t.Error("Expected error for schema with duplicate fields")
+	}
+	if len(registry.Names()) != 0 {
+		t.Errorf("Expected rejected schema not to be registered, got %v", registry.Names())
+	}
+}
@@ -0,0 +1,71 @@
+package safeunmarshal
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mhpenta/jobj"
+)
+
+// ToWithDefaults works like To, but first backfills the keys the response leaves out with the
+// defaults declared in schema, e.g. through funcschema's default tag, using Schema.FillDefaults.
+// Models often omit optional fields; with ToWithDefaults those fields decode to their documented
+// defaults instead of Go zero values. The JSON is cleaned and repaired as in To.
+//
+// Usage:
+//
+//	type SearchParams struct {
+//	    Query string `json:"query" desc:"Search query" required:"true"`
+//	    Limit int    `json:"limit" desc:"Maximum results" default:"10"`
+//	}
+//	schema, _ := funcschema.SchemaFromStruct[SearchParams]()
+//	params, err := safeunmarshal.ToWithDefaults[SearchParams]([]byte(`{"query": "jobj"}`), &schema)
+//	// params.Limit == 10
+func ToWithDefaults[T any](raw []byte, schema *jobj.Schema) (T, error) {
+	var zero T
+
+	filled, err := RepairWithDefaults(raw, schema)
+	if err != nil {
+		return zero, err
+	}
+	return To[T](filled)
+}
+
+// RepairWithDefaults cleans and repairs raw as To does and backfills the keys it leaves out with
+// the defaults declared in schema, returning the resulting JSON. It is the first step of
+// ToWithDefaults, for callers that check the arguments, e.g. with Schema.ValidateJSON, before
+// decoding them.
+func RepairWithDefaults(raw []byte, schema *jobj.Schema) ([]byte, error) {
+	data, err := Repair(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err := unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse repaired JSON: %w", err)
+	}
+	schema.FillDefaults(document)
+	filled, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON with defaults: %w", err)
+	}
+	return filled, nil
+}
+
+// Repair cleans and repairs raw as To does, removing surrounding text such as code fences and
+// fixing slightly malformed JSON, and returns the resulting JSON without decoding it.
+func Repair(raw []byte) ([]byte, error) {
+	data := prepareJSONForUnmarshalling(raw)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty input string")
+	}
+	if !json.Valid(data) {
+		repairedData, err := repairJSON(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to repair JSON: %w", err)
+		}
+		data = []byte(repairedData)
+	}
+	return data, nil
+}
@@ -0,0 +1,48 @@
+package safeunmarshal
+
+import (
+	"testing"
+
+	"github.com/mhpenta/jobj"
+)
+
+type searchParams struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+	Mode  string `json:"mode"`
+}
+
+func TestToWithDefaults(t *testing.T) {
+	schema := &jobj.Schema{Name: "Search", Fields: []*jobj.Field{
+		jobj.Text("query").Required(),
+		jobj.Int("limit").Default(10),
+		jobj.Text("mode").Default("fast"),
+	}}
+
+	params, err := ToWithDefaults[searchParams]([]byte("Here you go: {\"query\": \"jobj\", \"mode\": \"exact\",}"), schema)
+	if err != nil {
+		t.Fatalf("ToWithDefaults failed: %v", err)
+	}
+	if params != (searchParams{Query: "jobj", Limit: 10, Mode: "exact"}) {
+		t.Errorf("Unexpected params: %+v", params)
+	}
+
+	if _, err := ToWithDefaults[searchParams]([]byte("  "), schema); err == nil {
+		t.Errorf("Expected an error for empty input")
+	}
+}
+
+func TestRepairWithDefaults(t *testing.T) {
+	schema := &jobj.Schema{Name: "Search", Fields: []*jobj.Field{
+		jobj.Text("query").Required(),
+		jobj.Int("limit").Default(10),
+	}}
+
+	filled, err := RepairWithDefaults([]byte("```json\n{\"query\": \"jobj\",}\n```"), schema)
+	if err != nil {
+		t.Fatalf("RepairWithDefaults failed: %v", err)
+	}
+	if string(filled) != `{"limit":10,"query":"jobj"}` {
+		t.Errorf("Unexpected JSON: %s", filled)
+	}
+}
@@ -9,4 +9,7 @@ var (
 
 	// ErrJSONRepairFailed is returned when JSON repair attempts fail
 	ErrJSONRepairFailed = errors.New("JSON repair failed")
+
+	// ErrNoMatchingCandidate is returned by ToOneOf when the input matches none of the candidate schemas
+	ErrNoMatchingCandidate = errors.New("no matching candidate")
 )
@@ -0,0 +1,174 @@
+package safeunmarshal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mhpenta/jobj"
+)
+
+// TypeSchema is a candidate shape for ToOneOf: a Go type to decode into and the schema the JSON
+// must satisfy. Create one with Candidate.
+type TypeSchema struct {
+	Schema *jobj.Schema
+	decode func(data []byte) (any, error)
+}
+
+// Candidate returns a TypeSchema that validates against schema and decodes into a T.
+func Candidate[T any](schema *jobj.Schema) TypeSchema {
+	return TypeSchema{
+		Schema: schema,
+		decode: func(data []byte) (any, error) {
+			return To[T](data)
+		},
+	}
+}
+
+// Match is the result of trying one candidate in Rank.
+type Match struct {
+	Index int // Position of the candidate in the arguments
+	Value any // The decoded value, or nil if Err is set
+
+	// Score rates how well the input fits the candidate, from 0 to 1. It averages the share of
+	// the candidate's required fields that are present, the share of input properties the
+	// candidate declares and the share of the candidate's fields the input uses.
+	Score float64
+
+	Problems []jobj.ValidationError // Validation problems; a match is only usable without any
+	Err      error                  // Set if the input could not be validated or decoded
+}
+
+// Valid reports whether the candidate validated and decoded without problems.
+func (m Match) Valid() bool {
+	return m.Err == nil && len(m.Problems) == 0
+}
+
+// Rank tries raw against every candidate and returns one Match per candidate, best first: valid
+// matches before invalid ones, then by descending Score, then in argument order. The input is
+// cleaned and repaired as in To.
+func Rank(raw []byte, candidates ...TypeSchema) ([]Match, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no candidates given")
+	}
+
+	data := prepareJSONForUnmarshalling(raw)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty input string")
+	}
+	if !json.Valid(data) {
+		repairedData, err := repairJSON(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to repair JSON: %w", err)
+		}
+		data = []byte(repairedData)
+	}
+
+	var object map[string]any
+	_ = json.Unmarshal(data, &object)
+
+	matches := make([]Match, 0, len(candidates))
+	for i, candidate := range candidates {
+		match := Match{Index: i, Score: matchScore(candidate.Schema, object)}
+		match.Problems, match.Err = candidate.Schema.ValidateJSON(data)
+		if match.Err == nil {
+			match.Value, match.Err = candidate.decode(data)
+			if match.Err != nil {
+				match.Value = nil
+			}
+		}
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Valid() != matches[j].Valid() {
+			return matches[i].Valid()
+		}
+		return matches[i].Score > matches[j].Score
+	})
+	return matches, nil
+}
+
+// ToOneOf decodes raw into whichever candidate it matches best, for agents where the model may
+// legitimately answer with one of several shapes. It returns the decoded value (a T, as given to
+// Candidate) and the index of the chosen candidate.
+//
+// Candidates are ranked with Rank: the input is validated against each candidate's schema with
+// ValidateJSON, since decoding alone is not enough when encoding/json ignores unknown and missing
+// fields, and the valid candidate with the highest Score wins, ties going to the earlier candidate.
+// If no candidate is valid, the error wraps ErrNoMatchingCandidate and describes why each
+// candidate was rejected. Use Rank directly to see the scores.
+//
+// Usage:
+//
+//	value, index, err := safeunmarshal.ToOneOf(raw,
+//	    safeunmarshal.Candidate[Answer](answerSchema),
+//	    safeunmarshal.Candidate[Clarification](clarificationSchema),
+//	)
+//	switch v := value.(type) {
+//	case Answer:
+//	    // ...
+//	case Clarification:
+//	    // ...
+//	}
+func ToOneOf(raw []byte, candidates ...TypeSchema) (any, int, error) {
+	matches, err := Rank(raw, candidates...)
+	if err != nil {
+		return nil, -1, err
+	}
+	if matches[0].Valid() {
+		return matches[0].Value, matches[0].Index, nil
+	}
+
+	rejections := make([]string, 0, len(matches))
+	for _, match := range matches {
+		reason := match.Err
+		if reason == nil {
+			reason = jobj.ValidationErrors(match.Problems)
+		}
+		rejections = append(rejections, fmt.Sprintf("candidate %d (%s): %v", match.Index, candidates[match.Index].Schema.Name, reason))
+	}
+	return nil, -1, fmt.Errorf("%w: %s", ErrNoMatchingCandidate, strings.Join(rejections, "; "))
+}
+
+// matchScore implements Match.Score. Schemas with a RootField, which have no properties to
+// compare, score 1.
+func matchScore(schema *jobj.Schema, object map[string]any) float64 {
+	if schema.RootField != nil {
+		return 1
+	}
+
+	declared := make(map[string]bool, len(schema.Fields))
+	required, requiredPresent, present := 0, 0, 0
+	for _, field := range schema.Fields {
+		declared[field.ValueName] = true
+		_, ok := object[field.ValueName]
+		if ok {
+			present++
+		}
+		if field.ValueRequired {
+			required++
+			if ok {
+				requiredPresent++
+			}
+		}
+	}
+
+	known := 0
+	for key := range object {
+		if declared[key] {
+			known++
+		}
+	}
+
+	return (ratio(requiredPresent, required) + ratio(known, len(object)) + ratio(present, len(schema.Fields))) / 3
+}
+
+// ratio returns part/whole, or 1 when whole is zero.
+func ratio(part int, whole int) float64 {
+	if whole == 0 {
+		return 1
+	}
+	return float64(part) / float64(whole)
+}
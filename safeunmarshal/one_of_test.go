@@ -0,0 +1,145 @@
+package safeunmarshal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mhpenta/jobj"
+)
+
+type answer struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+}
+
+type clarification struct {
+	Question string `json:"question"`
+}
+
+type note struct {
+	Text   string `json:"text"`
+	Author string `json:"author"`
+	Tags   string `json:"tags"`
+}
+
+var (
+	answerSchema = &jobj.Schema{Name: "Answer", Fields: []*jobj.Field{
+		jobj.Text("text").Required(),
+		jobj.Float("confidence"),
+	}}
+	clarificationSchema = &jobj.Schema{Name: "Clarification", Fields: []*jobj.Field{
+		jobj.Text("question").Required(),
+	}}
+	noteSchema = &jobj.Schema{Name: "Note", Fields: []*jobj.Field{
+		jobj.Text("text").Required(),
+		jobj.Text("author"),
+		jobj.Text("tags"),
+	}}
+)
+
+func TestToOneOf(t *testing.T) {
+	candidates := []TypeSchema{
+		Candidate[note](noteSchema),
+		Candidate[answer](answerSchema),
+		Candidate[clarification](clarificationSchema),
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		wantIndex int
+		want      any
+	}{
+		{
+			name:      "clarification",
+			input:     `{"question": "Which year?"}`,
+			wantIndex: 2,
+			want:      clarification{Question: "Which year?"},
+		},
+		{
+			name:      "answer covers more of its schema than note",
+			input:     `{"text": "42", "confidence": 0.9}`,
+			wantIndex: 1,
+			want:      answer{Text: "42", Confidence: 0.9},
+		},
+		{
+			name:      "partial input prefers the smaller schema",
+			input:     `{"text": "hello"}`,
+			wantIndex: 1,
+			want:      answer{Text: "hello"},
+		},
+		{
+			name:      "repaired input",
+			input:     `Sure: {"question": "Which year?",}`,
+			wantIndex: 2,
+			want:      clarification{Question: "Which year?"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, index, err := ToOneOf([]byte(tt.input), candidates...)
+			if err != nil {
+				t.Fatalf("ToOneOf() error = %v", err)
+			}
+			if index != tt.wantIndex || got != tt.want {
+				t.Errorf("ToOneOf() = %#v, %d, want %#v, %d", got, index, tt.want, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestToOneOf_Tie(t *testing.T) {
+	_, index, err := ToOneOf([]byte(`{"question": "Which year?"}`),
+		Candidate[clarification](clarificationSchema),
+		Candidate[clarification](clarificationSchema),
+	)
+	if err != nil || index != 0 {
+		t.Errorf("ToOneOf() = %d, %v, want the earlier candidate", index, err)
+	}
+}
+
+func TestToOneOf_NoMatch(t *testing.T) {
+	_, index, err := ToOneOf([]byte(`{"unknown": true}`),
+		Candidate[answer](answerSchema),
+		Candidate[clarification](clarificationSchema),
+	)
+	if !errors.Is(err, ErrNoMatchingCandidate) {
+		t.Errorf("ToOneOf() error = %v, want ErrNoMatchingCandidate", err)
+	}
+	if index != -1 {
+		t.Errorf("ToOneOf() index = %d, want -1", index)
+	}
+
+	if _, _, err := ToOneOf([]byte(`{}`)); err == nil {
+		t.Error("ToOneOf() expected error without candidates")
+	}
+}
+
+func TestRank(t *testing.T) {
+	matches, err := Rank([]byte(`{"text": "hello", "author": "ann"}`),
+		Candidate[answer](answerSchema),
+		Candidate[note](noteSchema),
+		Candidate[clarification](clarificationSchema),
+	)
+	if err != nil {
+		t.Fatalf("Rank() error = %v", err)
+	}
+
+	order := []int{matches[0].Index, matches[1].Index, matches[2].Index}
+	if order[0] != 1 || order[1] != 0 || order[2] != 2 {
+		t.Errorf("Rank() order = %v, want [1 0 2]", order)
+	}
+	if !matches[0].Valid() || matches[0].Value != (note{Text: "hello", Author: "ann"}) {
+		t.Errorf("Rank() best match = %+v", matches[0])
+	}
+	if matches[1].Valid() || len(matches[1].Problems) == 0 {
+		t.Errorf("Rank() expected answer to be rejected for the unknown author property, got %+v", matches[1])
+	}
+	if matches[1].Score <= matches[2].Score {
+		t.Errorf("Rank() expected partial answer match to outscore clarification, got %v <= %v", matches[1].Score, matches[2].Score)
+	}
+	if score := matches[0].Score; score < 0.88 || score > 0.89 {
+		t.Errorf("Rank() note score = %v, want 8/9", score)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"reflect"
 )
@@ -33,6 +34,9 @@ import (
 //     If not, it returns ErrExpectedJSONArray.
 //  6. If repair is needed, attempts to repair and unmarshal the repaired JSON.
 //
+// As with json.Unmarshal, numbers decoded into interface values are float64; use ToWithNumbers
+// to keep them exact.
+//
 // Usage:
 //
 //	type MyStruct struct {
@@ -48,6 +52,26 @@ import (
 //	    }
 //	}
 func To[T any](raw []byte) (T, error) {
+	return to[T](raw, json.Unmarshal)
+}
+
+// ToWithNumbers works like To, but decodes numbers in interface values, e.g. the items of []any
+// fields or the values of map[string]any fields, as json.Number rather than float64, so large
+// integers and exact decimals survive the decoding.
+//
+// Usage:
+//
+//	type Row struct {
+//	    Cells []any `json:"cells"`
+//	}
+//	row, err := safeunmarshal.ToWithNumbers[Row]([]byte(`{"cells": [12345678901234567890, "a"]}`))
+//	// row.Cells[0] == json.Number("12345678901234567890")
+func ToWithNumbers[T any](raw []byte) (T, error) {
+	return to[T](raw, unmarshal)
+}
+
+// to implements To and ToWithNumbers, decoding with decode.
+func to[T any](raw []byte, decode func(data []byte, v interface{}) error) (T, error) {
 	var zero T // original zero value to return in case of error
 
 	data := prepareJSONForUnmarshalling(raw)
@@ -58,7 +82,7 @@ func To[T any](raw []byte) (T, error) {
 	}
 
 	var response T
-	err := json.Unmarshal(data, &response)
+	err := decode(data, &response)
 	if err != nil {
 
 		valueType := reflect.TypeOf((*T)(nil)).Elem()
@@ -77,7 +101,7 @@ func To[T any](raw []byte) (T, error) {
 			return zero, fmt.Errorf("JSON repair resulted in empty string")
 		}
 
-		err = json.Unmarshal([]byte(repairedData), &response)
+		err = decode([]byte(repairedData), &response)
 		if err != nil {
 			return zero, fmt.Errorf("failed to parse repaired JSON into struct: %w", err)
 		}
@@ -85,6 +109,19 @@ func To[T any](raw []byte) (T, error) {
 	return response, nil
 }
 
+// unmarshal works like json.Unmarshal, but decodes numbers in interface values as json.Number.
+func unmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid data after top-level value")
+	}
+	return nil
+}
+
 // isJSONArray checks if the input byte slice represents a JSON array.
 //
 // This function scans the input byte slice, skipping any leading whitespace,
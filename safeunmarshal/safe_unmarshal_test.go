@@ -1,7 +1,9 @@
 package safeunmarshal
 
 import (
+	"encoding/json"
 	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -390,3 +392,34 @@ func TestIsJSONArray(t *testing.T) {
 		})
 	}
 }
+
+func TestToInterfaceSlices(t *testing.T) {
+	type row struct {
+		Cells []any `json:"cells"`
+	}
+
+	raw := []byte("Here you go: {\"cells\": [12345678901234567890, 0.1, \"a\", true, {\"n\": 2}]}")
+	got, err := ToWithNumbers[row](raw)
+	if err != nil {
+		t.Fatalf("ToWithNumbers() error = %v", err)
+	}
+	want := []any{
+		json.Number("12345678901234567890"),
+		json.Number("0.1"),
+		"a",
+		true,
+		map[string]any{"n": json.Number("2")},
+	}
+	if !reflect.DeepEqual(got.Cells, want) {
+		t.Errorf("ToWithNumbers() = %#v, want %#v", got.Cells, want)
+	}
+
+	// To decodes numbers as float64, like json.Unmarshal
+	plain, err := To[row](raw)
+	if err != nil {
+		t.Fatalf("To() error = %v", err)
+	}
+	if _, ok := plain.Cells[1].(float64); !ok {
+		t.Errorf("To() decoded %#v, want a float64", plain.Cells[1])
+	}
+}
@@ -0,0 +1,11 @@
+package safexml
+
+import "errors"
+
+var (
+	// ErrNoXMLElement is returned when the input contains no XML element
+	ErrNoXMLElement = errors.New("no XML element found")
+
+	// ErrXMLRepairFailed is returned when the input could not be decoded even after repair
+	ErrXMLRepairFailed = errors.New("XML repair failed")
+)
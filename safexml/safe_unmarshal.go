@@ -0,0 +1,92 @@
+// Package safexml provides utilities for safely unmarshalling XML produced by large language
+// models. It is the XML counterpart of safeunmarshal, for prompts built with
+// jobj.Schema.GetXMLSchemaString, ToXMLExample or ToXMLTagTemplate.
+package safexml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+// To attempts to unmarshal an XML byte slice into a value of type T.
+//
+// Models often wrap their answer in explanations, code fences or an XML declaration, leave tags
+// unclosed when they run out of tokens, and write HTML entities or bare ampersands that XML does
+// not allow. To first extracts the outermost element from the surrounding text and decodes it.
+// If that fails, it repairs the element (see repairXML) and decodes again.
+//
+// Usage:
+//
+//	type PressRelease struct {
+//	    Headline string   `xml:"headline"`
+//	    Tags     []string `xml:"tags>item"`
+//	}
+//	release, err := safexml.To[PressRelease]([]byte(response))
+//	if errors.Is(err, safexml.ErrNoXMLElement) {
+//	    // The model did not answer in XML
+//	}
+func To[T any](raw []byte) (T, error) {
+	var zero T
+
+	data, err := extractElement(raw)
+	if err != nil {
+		return zero, err
+	}
+
+	var response T
+	if err := decode(data, &response); err == nil {
+		return response, nil
+	}
+
+	repaired := repairXML(data)
+	response = zero
+	if err := decode(repaired, &response); err != nil {
+		return zero, fmt.Errorf("%w: %v", ErrXMLRepairFailed, err)
+	}
+	return response, nil
+}
+
+// decode unmarshals a single element, accepting HTML entities such as &nbsp;.
+func decode(data []byte, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Entity = xml.HTMLEntity
+	return decoder.Decode(v)
+}
+
+// extractElement returns the outermost element in raw: from the first start tag to the last
+// matching end tag, or to the end of the input if the element was never closed. XML
+// declarations, comments and processing instructions before it are skipped.
+func extractElement(raw []byte) ([]byte, error) {
+	start := -1
+	for i := 0; i < len(raw)-1; i++ {
+		if raw[i] == '<' && isNameStart(raw[i+1]) {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return nil, ErrNoXMLElement
+	}
+
+	name := raw[start+1:]
+	end := 0
+	for end < len(name) && isNameChar(name[end]) {
+		end++
+	}
+	name = name[:end]
+
+	closing := append(append([]byte("</"), name...), '>')
+	if last := bytes.LastIndex(raw, closing); last > start {
+		return raw[start : last+len(closing)], nil
+	}
+	return bytes.TrimSpace(raw[start:]), nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || c == '-' || c == '.' || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,114 @@
+package safexml
+
+import (
+	"errors"
+	"testing"
+)
+
+type quote struct {
+	Speaker string `xml:"speaker"`
+	Text    string `xml:"text"`
+}
+
+type pressRelease struct {
+	ID       int      `xml:"id,attr"`
+	Headline string   `xml:"headline"`
+	Tags     []string `xml:"tags>item"`
+	Quotes   []quote  `xml:"quotes>item"`
+}
+
+func TestTo(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  pressRelease
+	}{
+		{
+			name:  "clean element",
+			input: `<release id="7"><headline>Launch</headline><tags><item>a</item><item>b</item></tags></release>`,
+			want:  pressRelease{ID: 7, Headline: "Launch", Tags: []string{"a", "b"}},
+		},
+		{
+			name: "surrounded by chatter and a declaration",
+			input: "Sure! Here is the XML:\n```xml\n<?xml version=\"1.0\"?>\n" +
+				"<release id=\"1\"><headline>Launch</headline></release>\n```\nLet me know if you need more.",
+			want: pressRelease{ID: 1, Headline: "Launch"},
+		},
+		{
+			name:  "truncated output",
+			input: `<release><headline>Launch</headline><quotes><item><speaker>Ann</speaker><text>We are thrilled`,
+			want:  pressRelease{Headline: "Launch", Quotes: []quote{{Speaker: "Ann", Text: "We are thrilled"}}},
+		},
+		{
+			name:  "unclosed inner element",
+			input: `<release><quotes><item><speaker>Ann</quotes><headline>Launch</headline></release>`,
+			want:  pressRelease{Headline: "Launch", Quotes: []quote{{Speaker: "Ann"}}},
+		},
+		{
+			name:  "stray end tag",
+			input: `<release><headline>Launch</title></headline></release>`,
+			want:  pressRelease{Headline: "Launch"},
+		},
+		{
+			name:  "entities and bare ampersands",
+			input: `<release><headline>Smith &amp; Sons&nbsp;&mdash; AT&T&#39;s &lt;best&gt; 3 < 4</headline></release>`,
+			want:  pressRelease{Headline: "Smith & Sons — AT&T's <best> 3 < 4"},
+		},
+		{
+			name:  "unterminated CDATA",
+			input: `<release><headline><![CDATA[A <b>bold</b> launch`,
+			want:  pressRelease{Headline: "A <b>bold</b> launch"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := To[pressRelease]([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("To() error = %v", err)
+			}
+			if got.ID != tt.want.ID || got.Headline != tt.want.Headline || len(got.Tags) != len(tt.want.Tags) || len(got.Quotes) != len(tt.want.Quotes) {
+				t.Fatalf("To() = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want.Tags {
+				if got.Tags[i] != tt.want.Tags[i] {
+					t.Errorf("Tags[%d] = %q, want %q", i, got.Tags[i], tt.want.Tags[i])
+				}
+			}
+			for i := range tt.want.Quotes {
+				if got.Quotes[i] != tt.want.Quotes[i] {
+					t.Errorf("Quotes[%d] = %+v, want %+v", i, got.Quotes[i], tt.want.Quotes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTo_NoElement(t *testing.T) {
+	for _, input := range []string{"", "I could not find a press release.", "1 < 2"} {
+		if _, err := To[pressRelease]([]byte(input)); !errors.Is(err, ErrNoXMLElement) {
+			t.Errorf("To(%q) error = %v, want ErrNoXMLElement", input, err)
+		}
+	}
+}
+
+func TestRepairXML(t *testing.T) {
+	tests := map[string]string{
+		`<a><b>x</a>`:               `<a><b>x</b></a>`,
+		`<a><b>x`:                   `<a><b>x</b></a>`,
+		`<a>x</b></a>`:              `<a>x</a>`,
+		`<a href="?x=1&y=2">`:       `<a href="?x=1&amp;y=2"></a>`,
+		`<a>&hellip;&foo;</a>`:      `<a>…&amp;foo;</a>`,
+		`<a><br/>x</a>`:             `<a><br/>x</a>`,
+		`<a><!-- note --><b>`:       `<a><!-- note --><b></b></a>`,
+		`<a><!-- unterminated`:      `<a></a>`,
+		`<a title="1 > 0">x</a>`:    `<a title="1 > 0">x</a>`,
+		`<a><b`:                     `<a></a>`,
+		`<a>&#x27;&#8212;&amp;</a>`: `<a>&#x27;&#8212;&amp;</a>`,
+	}
+	for input, expected := range tests {
+		if got := string(repairXML([]byte(input))); got != expected {
+			t.Errorf("repairXML(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package safexml
+
+import (
+	"bytes"
+	"html"
+	"strings"
+)
+
+// xmlEntities are the entities XML defines itself; every other named entity must be decoded.
+var xmlEntities = map[string]bool{
+	"amp":  true,
+	"lt":   true,
+	"gt":   true,
+	"quot": true,
+	"apos": true,
+}
+
+// maxEntityLength bounds the search for the ';' ending an entity reference.
+const maxEntityLength = 32
+
+// repairXML fixes the mistakes models commonly make when writing XML:
+//   - Unclosed elements are closed, both inside their parent and at the end of truncated output
+//   - End tags without a matching start tag are dropped
+//   - HTML entities such as &nbsp; or &rsquo; are decoded to the characters they stand for
+//   - Bare '&' and '<' characters in text are escaped
+//   - Unterminated comments are dropped and unterminated CDATA sections are closed
+func repairXML(src []byte) []byte {
+	var out bytes.Buffer
+	var open []string
+
+	for i := 0; i < len(src); {
+		switch {
+		case bytes.HasPrefix(src[i:], []byte("<!--")):
+			end := bytes.Index(src[i+4:], []byte("-->"))
+			if end == -1 {
+				i = len(src)
+				continue
+			}
+			out.Write(src[i : i+4+end+3])
+			i += 4 + end + 3
+
+		case bytes.HasPrefix(src[i:], []byte("<![CDATA[")):
+			end := bytes.Index(src[i+9:], []byte("]]>"))
+			if end == -1 {
+				out.Write(src[i:])
+				out.WriteString("]]>")
+				i = len(src)
+				continue
+			}
+			out.Write(src[i : i+9+end+3])
+			i += 9 + end + 3
+
+		case bytes.HasPrefix(src[i:], []byte("<?")), bytes.HasPrefix(src[i:], []byte("<!")):
+			// Declarations and processing instructions have no place inside the element
+			end := bytes.IndexByte(src[i:], '>')
+			if end == -1 {
+				i = len(src)
+				continue
+			}
+			i += end + 1
+
+		case bytes.HasPrefix(src[i:], []byte("</")):
+			tag, length := scanTag(src[i:])
+			if length == 0 {
+				// Truncated end tag
+				i = len(src)
+				continue
+			}
+			name := tagName(tag[2:])
+			if index := lastIndex(open, name); index != -1 {
+				for len(open) > index+1 {
+					out.WriteString("</" + open[len(open)-1] + ">")
+					open = open[:len(open)-1]
+				}
+				out.WriteString("</" + name + ">")
+				open = open[:index]
+			}
+			i += length
+
+		case src[i] == '<' && i+1 < len(src) && isNameStart(src[i+1]):
+			tag, length := scanTag(src[i:])
+			if length == 0 {
+				// Truncated start tag
+				i = len(src)
+				continue
+			}
+			out.WriteString(escapeAmpersands(string(tag)))
+			if !bytes.HasSuffix(tag, []byte("/>")) {
+				open = append(open, tagName(tag[1:]))
+			}
+			i += length
+
+		case src[i] == '<':
+			out.WriteString("&lt;")
+			i++
+
+		case src[i] == '&':
+			replacement, length := repairEntity(src[i:])
+			out.WriteString(replacement)
+			i += length
+
+		default:
+			out.WriteByte(src[i])
+			i++
+		}
+	}
+
+	for len(open) > 0 {
+		out.WriteString("</" + open[len(open)-1] + ">")
+		open = open[:len(open)-1]
+	}
+	return out.Bytes()
+}
+
+// scanTag returns the tag starting at src[0] and its length, or a zero length if the tag is not
+// terminated. A '>' inside a quoted attribute value does not end the tag.
+func scanTag(src []byte) ([]byte, int) {
+	var quote byte
+	for i := 1; i < len(src); i++ {
+		switch {
+		case quote != 0:
+			if src[i] == quote {
+				quote = 0
+			}
+		case src[i] == '"' || src[i] == '\'':
+			quote = src[i]
+		case src[i] == '>':
+			return src[:i+1], i + 1
+		}
+	}
+	return nil, 0
+}
+
+// tagName returns the element name at the start of src.
+func tagName(src []byte) string {
+	end := 0
+	for end < len(src) && isNameChar(src[end]) {
+		end++
+	}
+	return string(src[:end])
+}
+
+func lastIndex(names []string, name string) int {
+	for i := len(names) - 1; i >= 0; i-- {
+		if names[i] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// repairEntity handles the '&' at src[0] and returns its replacement and the number of bytes it
+// replaces. XML and numeric character references are kept, HTML entities are decoded and
+// anything else is a bare ampersand.
+func repairEntity(src []byte) (string, int) {
+	end := bytes.IndexByte(src[:min(len(src), maxEntityLength)], ';')
+	if end == -1 {
+		return "&amp;", 1
+	}
+	name := string(src[1:end])
+	reference := string(src[:end+1])
+
+	if xmlEntities[name] {
+		return reference, end + 1
+	}
+	if strings.HasPrefix(name, "#") && html.UnescapeString(reference) != reference {
+		return reference, end + 1
+	}
+	if decoded := html.UnescapeString(reference); decoded != reference {
+		return escapeText(decoded), end + 1
+	}
+	return "&amp;", 1
+}
+
+// escapeAmpersands escapes the bare ampersands in a tag's attribute values.
+func escapeAmpersands(tag string) string {
+	if !strings.Contains(tag, "&") {
+		return tag
+	}
+	var out strings.Builder
+	for i := 0; i < len(tag); {
+		if tag[i] != '&' {
+			out.WriteByte(tag[i])
+			i++
+			continue
+		}
+		replacement, length := repairEntity([]byte(tag[i:]))
+		out.WriteString(replacement)
+		i += length
+	}
+	return out.String()
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -3,11 +3,14 @@ package jobj
 import (
 	"encoding/json"
 	"fmt"
-	"log/slog"
 	"reflect"
+	"sort"
 	"strings"
 )
 
+// CreatableSchema is implemented by types that construct their schema lazily. CreateDescription
+// and CreateFields populate the receiver and return it so calls can be chained; GetDescription and
+// GetFields read the result. Register implementations with a Registry to have them built and cached.
 type CreatableSchema interface {
 	CreateDescription() CreatableSchema
 	CreateFields() CreatableSchema
@@ -20,6 +23,81 @@ type Schema struct {
 	Description string
 	Fields      []*Field
 	RootField   *Field // For non-struct return types (arrays, maps, primitives)
+
+	// SchemaID and Version identify the contract a schema describes. They are emitted by
+	// GetSchemaString as "$id" and "x-version" when set.
+	SchemaID string
+	Version  string
+
+	// RequiredOrder and EnumOrder control the order of "required" and anyOf lists in
+	// GetSchemaString and Strict output. Both default to DeclarationOrder.
+	RequiredOrder ListOrder
+	EnumOrder     ListOrder
+
+	// AdditionalProperties and AdditionalPropertiesField control the root object's
+	// additionalProperties keyword; see AllowAdditionalProperties.
+	AdditionalProperties      bool
+	AdditionalPropertiesField *Field
+}
+
+// FromMap builds a Schema from field name/type pairs, for scripts and tests where the fluent
+// API is overkill. Fields are sorted by name and marked required.
+func FromMap(name string, fields map[string]DataType) *Schema {
+	return FromMapWithDescriptions(name, fields, nil)
+}
+
+// FromMapWithDescriptions is like FromMap but also sets field descriptions from descriptions,
+// keyed by field name. Descriptions for names not present in fields are ignored.
+func FromMapWithDescriptions(name string, fields map[string]DataType, descriptions map[string]string) *Schema {
+	names := make([]string, 0, len(fields))
+	for fieldName := range fields {
+		names = append(names, fieldName)
+	}
+	sort.Strings(names)
+
+	schema := &Schema{
+		Name:   name,
+		Fields: make([]*Field, 0, len(names)),
+	}
+	for _, fieldName := range names {
+		field := &Field{
+			ValueName: fieldName,
+			ValueType: fields[fieldName],
+		}
+		schema.Fields = append(schema.Fields, field.Desc(descriptions[fieldName]).Required())
+	}
+	return schema
+}
+
+// AllowAdditionalProperties lets the root object accept properties beyond Fields, emitting
+// "additionalProperties": true. Use it for exploratory tools that take arbitrary parameters.
+func (r *Schema) AllowAdditionalProperties() *Schema {
+	r.AdditionalProperties = true
+	r.AdditionalPropertiesField = nil
+	return r
+}
+
+// AllowAdditionalPropertiesOf is like AllowAdditionalProperties, but additional properties must
+// match valueField, as the values of a MapOf field do. A valueField with nil SubFields allows any value.
+func (r *Schema) AllowAdditionalPropertiesOf(valueField *Field) *Schema {
+	r.AdditionalProperties = true
+	r.AdditionalPropertiesField = valueField
+	return r
+}
+
+// RootAdditionalProperties returns the value of the root object's additionalProperties keyword:
+// false by default, true or a value schema after AllowAdditionalProperties(Of).
+func (r *Schema) RootAdditionalProperties() interface{} {
+	if !r.AdditionalProperties {
+		return false
+	}
+	if r.AdditionalPropertiesField == nil || r.AdditionalPropertiesField.SubFields == nil {
+		return true
+	}
+	return map[string]interface{}{
+		"type":       string(r.AdditionalPropertiesField.ValueType),
+		"properties": processObjectFields(r.AdditionalPropertiesField.SubFields),
+	}
 }
 
 func (r *Schema) GetDescription() string {
@@ -30,40 +108,76 @@ func (r *Schema) GetFields() []*Field {
 	return r.Fields
 }
 
+// GetSchemaString returns the schema as a draft-07 JSON Schema document. The schema name is used as
+// the top-level title and the definition key, and Description, when set, becomes the definition's description.
+// Object shapes shared by several fields are emitted once alongside it; see Field.Definition.
 func (r *Schema) GetSchemaString() string {
-	schema := struct {
-		Schema      string                 `json:"$schema"`
-		Definitions map[string]interface{} `json:"definitions"`
-		Reference   string                 `json:"$ref"`
-	}{
-		Schema: "http://json-schema.org/draft-07/schema#",
-		Definitions: map[string]interface{}{
-			r.Name: map[string]interface{}{
-				"properties":           r.FieldsJson(),
-				"type":                 "object",
-				"required":             r.RequiredFields(),
-				"additionalProperties": false,
-			},
-		},
-		Reference: "#/definitions/" + r.Name,
-	}
-
-	schemaJson, err := json.MarshalIndent(schema, "", "  ")
+	shared, definitions := r.ShareDefinitions(definitionsPath)
+	schemaJson, err := json.MarshalIndent(shared.schemaDocument(shared.definitionJson(), definitions), "", "  ")
 	if err != nil {
 		// In theory, this could be problematic - in practice, however, there are very few ways we could experience
 		// an error: (1) the system ran out of memory, (2) a field values contained invalid UTF-8 characters
 		// or (3) a type was added that implements a custom MarshalJSON method that returns an error.
 		//
 		// Since these are unlikely, we return an empty string and log the error.
-		slog.Error("Error marshalling JSON schema", "err", err)
+		logError("Error marshalling JSON schema", "err", err)
 		return ""
 	}
 	return string(schemaJson)
 }
 
+// definitionJson returns the root object definition with the schema's serialization options applied.
+func (r *Schema) definitionJson() map[string]interface{} {
+	definition := map[string]interface{}{
+		"properties":           r.FieldsJson(),
+		"type":                 "object",
+		"required":             r.RequiredFields(),
+		"additionalProperties": r.RootAdditionalProperties(),
+	}
+	if r.Description != "" {
+		definition["description"] = r.Description
+	}
+	r.FinishDocument(definition)
+	return definition
+}
+
+// definitionsPath is the prefix of references to the definitions of a GetSchemaString document.
+const definitionsPath = "#/definitions/"
+
+// schemaDocument wraps definition in the draft-07 document emitted by GetSchemaString, along with
+// the shared definitions returned by ShareDefinitions.
+func (r *Schema) schemaDocument(definition map[string]interface{}, shared map[string]interface{}) interface{} {
+	definitions := make(map[string]interface{}, len(shared)+1)
+	for name, sharedDefinition := range shared {
+		definitions[name] = sharedDefinition
+	}
+	definitions[r.Name] = definition
+
+	return struct {
+		Schema      string                 `json:"$schema"`
+		ID          string                 `json:"$id,omitempty"`
+		Version     string                 `json:"x-version,omitempty"`
+		Title       string                 `json:"title,omitempty"`
+		Definitions map[string]interface{} `json:"definitions"`
+		Reference   string                 `json:"$ref"`
+	}{
+		Schema:      "http://json-schema.org/draft-07/schema#",
+		ID:          r.SchemaID,
+		Version:     r.Version,
+		Title:       r.Name,
+		Definitions: definitions,
+		Reference:   definitionsPath + r.Name,
+	}
+}
+
 func (r *Schema) FieldsJson() map[string]interface{} {
 	properties := make(map[string]interface{}, len(r.Fields))
 	for _, field := range r.Fields {
+		if field.ValueRef != "" {
+			properties[field.ValueName] = referenceProperties(field)
+			continue
+		}
+
 		if field.ValueAnyOf != nil {
 			anyOf := make([]map[string]interface{}, 0, len(field.ValueAnyOf))
 			for _, enum := range field.ValueAnyOf {
@@ -84,7 +198,18 @@ func (r *Schema) FieldsJson() map[string]interface{} {
 			continue
 		}
 
+		if field.ValueVariants != nil {
+			properties[field.ValueName] = variantProperties(field)
+			continue
+		}
+
 		if field.ValueType == "array" {
+			// Handle arrays whose items are a full field, e.g. nested arrays (when ArrayItemField is set)
+			if field.ArrayItemField != nil {
+				properties[field.ValueName] = fieldProperties(field)
+				continue
+			}
+
 			// Handle arrays of primitives (when ArrayItemType is set)
 			if field.ArrayItemType != "" {
 				fieldProps := map[string]interface{}{
@@ -100,51 +225,12 @@ func (r *Schema) FieldsJson() map[string]interface{} {
 
 			// Handle arrays of objects (when SubFields is set)
 			if field.SubFields != nil {
-				arrayFieldProperties := make(map[string]interface{})
-				requiredFields := []string{}
-
-				for _, subField := range field.SubFields {
-					if subField.ValueRequired {
-						requiredFields = append(requiredFields, subField.ValueName)
-					}
-				}
-
-				for _, subField := range field.SubFields {
-					if subField.ValueAnyOf != nil {
-						anyOf := make([]map[string]interface{}, 0, len(subField.ValueAnyOf))
-						for _, enum := range subField.ValueAnyOf {
-							anyOf = append(anyOf, map[string]interface{}{
-								"const":       enum.Const,
-								"description": enum.Description,
-							})
-						}
-
-						fieldProps := map[string]interface{}{
-							"anyOf": anyOf,
-						}
-						if subField.ValueDescription != "" {
-							fieldProps["description"] = subField.ValueDescription
-						}
-
-						arrayFieldProperties[subField.ValueName] = fieldProps
-						continue
-					}
-
-					if subField.ValueType == "object" && subField.SubFields != nil {
-						objectFieldProperties := processObjectFields(subField.SubFields)
-						arrayFieldProperties[subField.ValueName] = map[string]interface{}{
-							"type":        subField.ValueType,
-							"description": subField.ValueDescription,
-							"properties":  objectFieldProperties,
-							"required":    subField.getRequiredFields(),
-						}
-						continue
-					}
-
-					arrayFieldProperties[subField.ValueName] = map[string]string{
-						"type":        string(subField.ValueType),
-						"description": subField.ValueDescription,
-					}
+				// Item properties render like those of any object, so arrays and objects nested in
+				// the items keep their own items and required lists at every depth
+				arrayFieldProperties := processObjectFields(field.SubFields)
+				requiredFields := field.getRequiredFields()
+				if requiredFields == nil {
+					requiredFields = []string{}
 				}
 
 				properties[field.ValueName] = map[string]interface{}{
@@ -171,9 +257,7 @@ func (r *Schema) FieldsJson() map[string]interface{} {
 
 				if field.AdditionalPropertiesType != "" {
 					// Map with primitive values
-					objectSchema["additionalProperties"] = map[string]interface{}{
-						"type": string(field.AdditionalPropertiesType),
-					}
+					objectSchema["additionalProperties"] = mapValueProperties(field)
 				} else if field.AdditionalPropertiesField != nil {
 					// Map with complex values (struct or interface{})
 					if field.AdditionalPropertiesField.SubFields == nil {
@@ -203,11 +287,9 @@ func (r *Schema) FieldsJson() map[string]interface{} {
 			continue
 		}
 
-		properties[field.ValueName] = map[string]string{
-			"type":        string(field.ValueType),
-			"description": field.ValueDescription,
-		}
+		properties[field.ValueName] = primitiveProperties(field)
 	}
+	addAnnotations(r.Fields, properties)
 	return properties
 }
 
@@ -230,6 +312,50 @@ func (r *Schema) RequiredFields() []string {
 	return required
 }
 
+// Finalize checks the schema for mistakes that the fluent API cannot catch while it is being
+// built. It returns an error listing every field name that is declared more than once in the same
+// object, including within SubFields, array items and map values; without the check the later
+// field would silently replace the earlier one in the emitted properties.
+func (r *Schema) Finalize() error {
+	var duplicates []string
+	if r.RootField != nil {
+		duplicateChildren(r.RootField, "", &duplicates)
+	} else {
+		duplicateFields(r.Fields, "", &duplicates)
+	}
+
+	if len(duplicates) > 0 {
+		return fmt.Errorf("schema %q has duplicate fields: %s", r.Name, strings.Join(duplicates, ", "))
+	}
+	return nil
+}
+
+func duplicateFields(fields []*Field, path string, duplicates *[]string) {
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		fieldPath := path + "/" + field.ValueName
+		if seen[field.ValueName] {
+			*duplicates = append(*duplicates, fieldPath)
+		}
+		seen[field.ValueName] = true
+		duplicateChildren(field, fieldPath, duplicates)
+	}
+}
+
+// duplicateChildren checks the fields nested in field, using "*" for array items and map values.
+func duplicateChildren(field *Field, path string, duplicates *[]string) {
+	switch {
+	case field.ArrayItemField != nil:
+		duplicateChildren(field.ArrayItemField, path+"/*", duplicates)
+	case field.AdditionalPropertiesField != nil:
+		duplicateFields(field.AdditionalPropertiesField.SubFields, path+"/*", duplicates)
+	case field.ValueType == TypeArray:
+		duplicateFields(field.SubFields, path+"/*", duplicates)
+	default:
+		duplicateFields(field.SubFields, path, duplicates)
+	}
+}
+
 // Validate verifies that the schema fields match the struct fields.
 // It checks:
 // - Every schema field has a corresponding struct field with matching JSON tag
@@ -344,6 +470,11 @@ func (f *Field) getRequiredFields() []string {
 func processObjectFields(fields []*Field) map[string]interface{} {
 	objectFieldProperties := make(map[string]interface{})
 	for _, field := range fields {
+		if field.ValueRef != "" {
+			objectFieldProperties[field.ValueName] = referenceProperties(field)
+			continue
+		}
+
 		if field.ValueAnyOf != nil {
 			anyOf := make([]map[string]interface{}, 0, len(field.ValueAnyOf))
 			for _, enum := range field.ValueAnyOf {
@@ -364,6 +495,35 @@ func processObjectFields(fields []*Field) map[string]interface{} {
 			continue
 		}
 
+		if field.ValueVariants != nil {
+			objectFieldProperties[field.ValueName] = variantProperties(field)
+			continue
+		}
+
+		// Handle arrays whose items are a full field, e.g. nested arrays
+		if field.ValueType == TypeArray && field.ArrayItemField != nil {
+			objectFieldProperties[field.ValueName] = map[string]interface{}{
+				"type":        string(field.ValueType),
+				"description": field.ValueDescription,
+				"items":       fieldProperties(field.ArrayItemField),
+			}
+			continue
+		}
+
+		// Handle arrays of objects
+		if field.ValueType == TypeArray && field.ArrayItemType == "" && field.SubFields != nil {
+			objectFieldProperties[field.ValueName] = map[string]interface{}{
+				"type":        string(field.ValueType),
+				"description": field.ValueDescription,
+				"items": map[string]interface{}{
+					"type":       "object",
+					"properties": processObjectFields(field.SubFields),
+					"required":   field.getRequiredFields(),
+				},
+			}
+			continue
+		}
+
 		// Handle arrays with primitive item types
 		if field.ValueType == TypeArray && field.ArrayItemType != "" {
 			objectFieldProperties[field.ValueName] = map[string]interface{}{
@@ -385,9 +545,7 @@ func processObjectFields(fields []*Field) map[string]interface{} {
 
 			if field.AdditionalPropertiesType != "" {
 				// Map with primitive values
-				objectSchema["additionalProperties"] = map[string]interface{}{
-					"type": string(field.AdditionalPropertiesType),
-				}
+				objectSchema["additionalProperties"] = mapValueProperties(field)
 			} else if field.AdditionalPropertiesField != nil {
 				// Map with complex values (struct or interface{})
 				if field.AdditionalPropertiesField.SubFields == nil {
@@ -418,14 +576,76 @@ func processObjectFields(fields []*Field) map[string]interface{} {
 		}
 
 		// Default: primitive types
-		objectFieldProperties[field.ValueName] = map[string]string{
-			"type":        string(field.ValueType),
-			"description": field.ValueDescription,
-		}
+		objectFieldProperties[field.ValueName] = primitiveProperties(field)
 	}
+	addAnnotations(fields, objectFieldProperties)
 	return objectFieldProperties
 }
 
+// fieldProperties returns the schema of a single field, as it would appear in a properties map.
+func fieldProperties(field *Field) interface{} {
+	return processObjectFields([]*Field{field})[field.ValueName]
+}
+
+// mapValueProperties returns the schema of the values of a map field with primitive values: the
+// schema of its AdditionalPropertiesField, if set, or just their type.
+func mapValueProperties(field *Field) interface{} {
+	if field.AdditionalPropertiesField != nil {
+		return fieldProperties(field.AdditionalPropertiesField)
+	}
+	return map[string]interface{}{"type": string(field.AdditionalPropertiesType)}
+}
+
+// variantProperties returns the schema of a Variants field, an "anyOf" of the variants' schemas,
+// or a "oneOf" with a discriminator when the field has one.
+func variantProperties(field *Field) map[string]interface{} {
+	variants := make([]interface{}, 0, len(field.ValueVariants))
+	for _, variant := range field.ValueVariants {
+		variants = append(variants, fieldProperties(variant))
+	}
+	props := map[string]interface{}{
+		variantsKeyword(field): variants,
+	}
+	addDiscriminator(field, props)
+	if field.ValueDescription != "" {
+		props["description"] = field.ValueDescription
+	}
+	return props
+}
+
+// variantsKeyword returns the keyword listing the schemas of a Variants field.
+func variantsKeyword(field *Field) string {
+	if field.ValueDiscriminator != "" {
+		return "oneOf"
+	}
+	return "anyOf"
+}
+
+// addDiscriminator adds the discriminator object of a Variants field to props, if it has one.
+func addDiscriminator(field *Field, props map[string]interface{}) {
+	if field.ValueDiscriminator != "" {
+		props["discriminator"] = map[string]interface{}{"propertyName": field.ValueDiscriminator}
+	}
+}
+
+// primitiveProperties returns the schema of a primitive field. The format and contentEncoding
+// keywords are only emitted when the field sets them, and the type is left out for Any fields.
+func primitiveProperties(field *Field) map[string]string {
+	props := map[string]string{
+		"description": field.ValueDescription,
+	}
+	if field.ValueType != "" {
+		props["type"] = string(field.ValueType)
+	}
+	if field.ValueFormat != "" {
+		props["format"] = field.ValueFormat
+	}
+	if field.ValueContentEncoding != "" {
+		props["contentEncoding"] = field.ValueContentEncoding
+	}
+	return props
+}
+
 func isTypeCompatible(goType reflect.Type, schemaType DataType) bool {
 	if goType.Kind() == reflect.Ptr {
 		goType = goType.Elem()
@@ -433,7 +653,9 @@ func isTypeCompatible(goType reflect.Type, schemaType DataType) bool {
 
 	switch schemaType {
 	case "string":
-		return goType.Kind() == reflect.String
+		// []byte is encoded by encoding/json as a base64 string
+		return goType.Kind() == reflect.String ||
+			(goType.Kind() == reflect.Slice && goType.Elem().Kind() == reflect.Uint8)
 	case "number":
 		return goType.Kind() == reflect.Float32 || goType.Kind() == reflect.Float64
 	case "integer":
@@ -1,6 +1,8 @@
 package jobj
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -203,3 +205,191 @@ func NewEmbeddedFieldsTestResponse() *EmbeddedFieldsTestResponse {
 	}
 	return r
 }
+
+func TestFromMap(t *testing.T) {
+	s := FromMapWithDescriptions("Quick", map[string]DataType{
+		"name":  TypeString,
+		"count": TypeInteger,
+	}, map[string]string{
+		"name": "The name field",
+	})
+
+	if s.Name != "Quick" || len(s.Fields) != 2 {
+		t.Fatalf("Unexpected schema: %+v", s)
+	}
+	if s.Fields[0].ValueName != "count" || s.Fields[0].ValueType != TypeInteger {
+		t.Errorf("Expected fields sorted by name, got %q first", s.Fields[0].ValueName)
+	}
+	if s.Fields[1].ValueDescription != "The name field" {
+		t.Errorf("Expected description on name, got %q", s.Fields[1].ValueDescription)
+	}
+
+	required := FromMap("Quick", map[string]DataType{"a": TypeBoolean}).RequiredFields()
+	if len(required) != 1 || required[0] != "a" {
+		t.Errorf("Expected all fields required, got %v", required)
+	}
+}
+
+func TestTypedConstEmission(t *testing.T) {
+	s := &Schema{
+		Name: "Severity",
+		Fields: []*Field{
+			AnyOf("severity", []ConstDescription{
+				{Const: 1, Description: "low"},
+				{Const: "high", Description: "high"},
+				{Const: false, Description: "none"},
+			}),
+		},
+	}
+
+	schemaString := s.GetSchemaString()
+	for _, want := range []string{`"const": 1,`, `"const": "high",`, `"const": false,`} {
+		if !strings.Contains(schemaString, want) {
+			t.Errorf("Expected schema to contain %s, got %s", want, schemaString)
+		}
+	}
+}
+
+func TestSchemaIDAndVersion(t *testing.T) {
+	s := &Schema{
+		Name:     "Versioned",
+		SchemaID: "https://example.com/schemas/versioned.json",
+		Version:  "2.1.0",
+		Fields:   []*Field{Text("name").Required()},
+	}
+
+	schemaString := s.GetSchemaString()
+	for _, want := range []string{
+		`"$id": "https://example.com/schemas/versioned.json",`,
+		`"x-version": "2.1.0",`,
+	} {
+		if !strings.Contains(schemaString, want) {
+			t.Errorf("Expected schema to contain %s, got %s", want, schemaString)
+		}
+	}
+
+	s.SchemaID, s.Version = "", ""
+	if schemaString := s.GetSchemaString(); strings.Contains(schemaString, `"$id"`) || strings.Contains(schemaString, `"x-version"`) {
+		t.Errorf("Expected no metadata keywords when unset, got %s", schemaString)
+	}
+}
+
+func TestAllowAdditionalProperties(t *testing.T) {
+	closed := &Schema{Name: "Params", Fields: []*Field{Text("query").Required()}}
+	if !strings.Contains(closed.GetSchemaString(), `"additionalProperties": false`) {
+		t.Errorf("Expected closed root by default, got %s", closed.GetSchemaString())
+	}
+
+	open := (&Schema{Name: "Params", Fields: []*Field{Text("query").Required()}}).AllowAdditionalProperties()
+	if !strings.Contains(open.GetSchemaString(), `"additionalProperties": true`) {
+		t.Errorf("Expected open root, got %s", open.GetSchemaString())
+	}
+	if errs, err := open.ValidateJSON([]byte(`{"query":"q","extra":1}`)); err != nil || len(errs) != 0 {
+		t.Errorf("Expected extra property to be accepted, got %v %v", errs, err)
+	}
+
+	typed := (&Schema{Name: "Params", Fields: []*Field{Text("query").Required()}}).
+		AllowAdditionalPropertiesOf(Object("", []*Field{Int("weight").Required()}))
+	additional, ok := typed.RootAdditionalProperties().(map[string]interface{})
+	if !ok || additional["type"] != "object" {
+		t.Fatalf("Expected typed additionalProperties, got %#v", typed.RootAdditionalProperties())
+	}
+	errs, err := typed.ValidateJSON([]byte(`{"query":"q","extra":{"weight":"heavy"}}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON failed: %v", err)
+	}
+	if len(errs) != 1 || errs[0].Pointer != "/extra/weight" {
+		t.Errorf("Expected one error at /extra/weight, got %v", errs)
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	valid := &Schema{
+		Name: "Valid",
+		Fields: []*Field{
+			Text("name"),
+			Object("address", []*Field{Text("name")}),
+		},
+	}
+	if err := valid.Finalize(); err != nil {
+		t.Errorf("Expected same name in different objects to be allowed, got %v", err)
+	}
+
+	duplicated := &Schema{
+		Name: "Duplicated",
+		Fields: []*Field{
+			Text("name"),
+			Int("name"),
+			Array("quotes", []*Field{Text("speaker"), Text("speaker")}),
+			MapOf("people", Object("", []*Field{Text("id"), Text("id")})),
+		},
+	}
+	err := duplicated.Finalize()
+	if err == nil {
+		t.Fatal("Expected error for duplicate fields")
+	}
+	for _, want := range []string{"/name", "/quotes/*/speaker", "/people/*/id"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %s, got %v", want, err)
+		}
+	}
+}
+
+func TestExample(t *testing.T) {
+	s := &Schema{
+		Name: "Example",
+		Fields: []*Field{
+			Text("headline").Required(),
+			Text("status").SetValue("draft"),
+			Email("contact"),
+			UUID("id"),
+			Bytes("logo"),
+			Int("count"),
+			AnyOf("severity", []ConstDescription{{Const: 2}, {Const: 3}}),
+			ArrayOf("tags", TypeString),
+			Array("quotes", []*Field{Text("speaker"), DateTime("said_at")}),
+			ArrayOfField("matrix", ArrayOf("", TypeNumber)),
+			Map("counts", TypeInteger),
+			Object("source", []*Field{URI("url")}),
+		},
+	}
+
+	example, err := s.ExampleJSON()
+	if err != nil {
+		t.Fatalf("ExampleJSON failed: %v", err)
+	}
+	if errs, err := s.ValidateJSON([]byte(example)); err != nil || len(errs) != 0 {
+		t.Errorf("Expected example to validate, got %v %v\n%s", errs, err, example)
+	}
+	for _, want := range []string{`"status": "draft"`, `"severity": 2`, `"contact": "user@example.com"`} {
+		if !strings.Contains(example, want) {
+			t.Errorf("Expected example to contain %s, got %s", want, example)
+		}
+	}
+}
+
+func TestPrimitiveArrayItems(t *testing.T) {
+	s := &Schema{
+		Name: "Arrays",
+		Fields: []*Field{
+			ArrayOf("tags", TypeString),
+			Object("meta", []*Field{ArrayOf("ids", TypeInteger)}),
+			Array("rows", []*Field{ArrayOf("flags", TypeBoolean)}),
+			MapOf("scores", Object("", []*Field{ArrayOf("values", TypeNumber)})),
+		},
+	}
+
+	var document struct {
+		Definitions map[string]json.RawMessage `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(s.GetSchemaString()), &document); err != nil {
+		t.Fatalf("Failed to decode schema: %v", err)
+	}
+	definition := string(document.Definitions["Arrays"])
+	for _, itemType := range []string{"string", "integer", "boolean", "number"} {
+		want := `"items":{"type":"` + itemType + `"}`
+		if !strings.Contains(strings.Join(strings.Fields(definition), ""), want) {
+			t.Errorf("Expected %s array items in %s", itemType, definition)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package jobj
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metrics used by Score, reported in FieldScore.Metric.
+const (
+	MetricExact   = "exact"   // 1 if the values are equal, else 0
+	MetricNumeric = "numeric" // 1 if the numbers are within ScoreOptions.NumericTolerance, else 0
+	MetricSet     = "set"     // F1 overlap of two arrays of primitives, ignoring order
+)
+
+// ScoreOptions tunes how Score compares values.
+type ScoreOptions struct {
+	// NumericTolerance is the largest absolute difference at which two numbers still match.
+	NumericTolerance float64
+
+	// NormalizeStrings compares strings ignoring case and surrounding whitespace.
+	NormalizeStrings bool
+}
+
+// FieldScore is the score of one value of a model instance against the gold instance.
+type FieldScore struct {
+	Pointer   string      `json:"pointer"`   // JSON Pointer to the value
+	Field     string      `json:"field"`     // Pointer with array indices replaced by "*", for grouping across instances
+	Metric    string      `json:"metric"`    // MetricExact, MetricNumeric or MetricSet
+	Score     float64     `json:"score"`     // From 0 to 1
+	Gold      interface{} `json:"gold"`      // nil if the gold instance has no value here
+	Predicted interface{} `json:"predicted"` // nil if the model instance has no value here
+}
+
+// Scorecard is the result of Score.
+type Scorecard struct {
+	Fields []FieldScore `json:"fields"`
+	Score  float64      `json:"score"` // Mean of the field scores; 1 when there is nothing to score
+}
+
+// Score rates a model instance against a gold instance of the schema, for evaluating extraction
+// quality. Every leaf value is scored: primitives and enums exactly, numbers within
+// options.NumericTolerance, and arrays of primitives by set overlap, the F1 of their items
+// ignoring order. Objects and maps are scored property by property and arrays of objects item by
+// item. A value present in only one instance scores 0, while a value missing from both is not
+// scored. Numbers are decoded as json.Number. The error return is only used when either instance
+// is not well-formed JSON.
+//
+// Example:
+//
+//	card, err := schema.Score(gold, output, jobj.ScoreOptions{NumericTolerance: 0.01})
+//	fmt.Printf("%.2f\n", card.Score)
+//	for _, field := range card.Fields {
+//	    if field.Score < 1 {
+//	        fmt.Println(field.Pointer, field.Gold, field.Predicted)
+//	    }
+//	}
+func (r *Schema) Score(gold, predicted []byte, options ScoreOptions) (*Scorecard, error) {
+	goldValue, err := decodeInstance(gold)
+	if err != nil {
+		return nil, fmt.Errorf("gold instance: %w", err)
+	}
+	predictedValue, err := decodeInstance(predicted)
+	if err != nil {
+		return nil, fmt.Errorf("model instance: %w", err)
+	}
+
+	s := scorer{options: options}
+	root := r.RootField
+	if root == nil {
+		root = Object("", r.Fields)
+	}
+	s.value(root, goldValue, predictedValue, "", "")
+
+	card := &Scorecard{Fields: s.fields, Score: 1}
+	if len(s.fields) > 0 {
+		total := 0.0
+		for _, field := range s.fields {
+			total += field.Score
+		}
+		card.Score = total / float64(len(s.fields))
+	}
+	return card, nil
+}
+
+// FieldAccuracy averages the scores of every field across scorecards, keyed by FieldScore.Field,
+// e.g. "/officers/*/name", giving per-field accuracy over an evaluation set.
+func FieldAccuracy(cards ...*Scorecard) map[string]float64 {
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, card := range cards {
+		for _, field := range card.Fields {
+			totals[field.Field] += field.Score
+			counts[field.Field]++
+		}
+	}
+	accuracy := make(map[string]float64, len(totals))
+	for field, total := range totals {
+		accuracy[field] = total / float64(counts[field])
+	}
+	return accuracy
+}
+
+type scorer struct {
+	options ScoreOptions
+	fields  []FieldScore
+}
+
+func (s *scorer) add(metric string, score float64, gold, predicted interface{}, pointer, path string) {
+	s.fields = append(s.fields, FieldScore{
+		Pointer:   pointer,
+		Field:     path,
+		Metric:    metric,
+		Score:     score,
+		Gold:      gold,
+		Predicted: predicted,
+	})
+}
+
+// value scores predicted against gold, both present, as described by field.
+func (s *scorer) value(field *Field, gold, predicted interface{}, pointer, path string) {
+	// Values the schema does not describe further, e.g. generic objects, are scored as a whole
+	if field.ValueAnyOf == nil {
+		switch goldValue := gold.(type) {
+		case map[string]interface{}:
+			predictedValue, ok := predicted.(map[string]interface{})
+			if ok && (field.AdditionalProperties || field.SubFields != nil) {
+				s.object(field, goldValue, predictedValue, pointer, path)
+				return
+			}
+		case []interface{}:
+			predictedValue, ok := predicted.([]interface{})
+			if ok && (field.ArrayItemType != "" || field.ArrayItemField != nil || field.SubFields != nil) {
+				s.array(field, goldValue, predictedValue, pointer, path)
+				return
+			}
+		}
+	}
+
+	metric := scoreMetric(field)
+	score := 0.0
+	if s.matches(gold, predicted) {
+		score = 1
+	}
+	s.add(metric, score, gold, predicted, pointer, path)
+}
+
+func (s *scorer) object(field *Field, gold, predicted map[string]interface{}, pointer, path string) {
+	if field.AdditionalProperties {
+		values := field.AdditionalPropertiesField
+		if values == nil {
+			values = &Field{ValueType: field.AdditionalPropertiesType}
+		}
+		keys := make(map[string]bool, len(gold)+len(predicted))
+		for key := range gold {
+			keys[key] = true
+		}
+		for key := range predicted {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		for _, key := range sorted {
+			token := "/" + escapePointerToken(key)
+			s.property(values, key, gold, predicted, pointer+token, path+token)
+		}
+		return
+	}
+
+	for _, subField := range field.SubFields {
+		token := "/" + escapePointerToken(subField.ValueName)
+		s.property(subField, subField.ValueName, gold, predicted, pointer+token, path+token)
+	}
+}
+
+func (s *scorer) property(field *Field, name string, gold, predicted map[string]interface{}, pointer, path string) {
+	goldValue, inGold := gold[name]
+	predictedValue, inPredicted := predicted[name]
+	switch {
+	case inGold && inPredicted:
+		s.value(field, goldValue, predictedValue, pointer, path)
+	case inGold || inPredicted:
+		s.add(scoreMetric(field), 0, goldValue, predictedValue, pointer, path)
+	}
+}
+
+func (s *scorer) array(field *Field, gold, predicted []interface{}, pointer, path string) {
+	if field.ArrayItemType != "" && field.ArrayItemField == nil {
+		s.add(MetricSet, s.overlap(gold, predicted), gold, predicted, pointer, path)
+		return
+	}
+
+	items := field.ArrayItemField
+	if items == nil {
+		items = Object("", field.SubFields)
+	}
+	for i := 0; i < len(gold) || i < len(predicted); i++ {
+		itemPointer := pointer + "/" + strconv.Itoa(i)
+		switch {
+		case i < len(gold) && i < len(predicted):
+			s.value(items, gold[i], predicted[i], itemPointer, path+"/*")
+		case i < len(gold):
+			s.add(scoreMetric(items), 0, gold[i], nil, itemPointer, path+"/*")
+		default:
+			s.add(scoreMetric(items), 0, nil, predicted[i], itemPointer, path+"/*")
+		}
+	}
+}
+
+// overlap returns the F1 score of predicted against gold, matching each item at most once.
+func (s *scorer) overlap(gold, predicted []interface{}) float64 {
+	if len(gold) == 0 && len(predicted) == 0 {
+		return 1
+	}
+	used := make([]bool, len(predicted))
+	matched := 0
+	for _, goldItem := range gold {
+		for i, predictedItem := range predicted {
+			if !used[i] && s.matches(goldItem, predictedItem) {
+				used[i] = true
+				matched++
+				break
+			}
+		}
+	}
+	return 2 * float64(matched) / float64(len(gold)+len(predicted))
+}
+
+func (s *scorer) matches(gold, predicted interface{}) bool {
+	switch goldValue := gold.(type) {
+	case json.Number:
+		predictedValue, ok := predicted.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errX := goldValue.Float64()
+		y, errY := predictedValue.Float64()
+		if errX != nil || errY != nil {
+			return goldValue == predictedValue
+		}
+		return math.Abs(x-y) <= s.options.NumericTolerance
+	case string:
+		predictedValue, ok := predicted.(string)
+		if !ok {
+			return false
+		}
+		if s.options.NormalizeStrings {
+			return strings.EqualFold(strings.TrimSpace(goldValue), strings.TrimSpace(predictedValue))
+		}
+		return goldValue == predictedValue
+	}
+	return jsonEqual(gold, predicted)
+}
+
+// scoreMetric returns the metric Score uses for a value described by field.
+func scoreMetric(field *Field) string {
+	switch {
+	case field.ValueAnyOf != nil:
+		return MetricExact
+	case field.ValueType == TypeNumber || field.ValueType == TypeInteger:
+		return MetricNumeric
+	case field.ValueType == TypeArray && field.ArrayItemType != "" && field.ArrayItemField == nil:
+		return MetricSet
+	}
+	return MetricExact
+}
@@ -0,0 +1,70 @@
+package jobj
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	schema := &Schema{
+		Name: "Filing",
+		Fields: []*Field{
+			Text("company").Required(),
+			Float("revenue"),
+			AnyOf("form", []ConstDescription{{Const: "10-K"}, {Const: "10-Q"}}),
+			ArrayOf("tags", TypeString),
+			Array("officers", []*Field{Text("name"), Text("title")}),
+			Text("auditor"),
+		},
+	}
+
+	gold := `{"company": "Acme", "revenue": 100.0, "form": "10-K", "tags": ["tech", "small", "us"],
+		"officers": [{"name": "Ann", "title": "CEO"}, {"name": "Bob", "title": "CFO"}]}`
+	predicted := `{"company": " acme ", "revenue": 100.4, "form": "10-Q", "tags": ["us", "tech"],
+		"officers": [{"name": "Ann", "title": "CEO"}], "auditor": "KPMG"}`
+
+	card, err := schema.Score([]byte(gold), []byte(predicted), ScoreOptions{NumericTolerance: 0.5, NormalizeStrings: true})
+	if err != nil {
+		t.Fatalf("Score failed: %v", err)
+	}
+
+	expected := map[string]struct {
+		metric string
+		score  float64
+	}{
+		"/company":          {MetricExact, 1},
+		"/revenue":          {MetricNumeric, 1},
+		"/form":             {MetricExact, 0},
+		"/tags":             {MetricSet, 0.8},
+		"/officers/0/name":  {MetricExact, 1},
+		"/officers/0/title": {MetricExact, 1},
+		"/officers/1":       {MetricExact, 0},
+		"/auditor":          {MetricExact, 0},
+	}
+	if len(card.Fields) != len(expected) {
+		t.Fatalf("Expected %d field scores, got %v", len(expected), card.Fields)
+	}
+	for _, field := range card.Fields {
+		want, ok := expected[field.Pointer]
+		if !ok || field.Metric != want.metric || math.Abs(field.Score-want.score) > 1e-9 {
+			t.Errorf("Unexpected score for %s: %+v", field.Pointer, field)
+		}
+	}
+	if math.Abs(card.Score-4.8/8) > 1e-9 {
+		t.Errorf("Expected an aggregate score of 0.6, got %v", card.Score)
+	}
+
+	strict, err := schema.Score([]byte(gold), []byte(predicted), ScoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accuracy := FieldAccuracy(card, strict)
+	if accuracy["/company"] != 0.5 || accuracy["/revenue"] != 0.5 || accuracy["/officers/*/name"] != 1 {
+		t.Errorf("Unexpected field accuracy: %v", accuracy)
+	}
+
+	perfect, err := schema.Score([]byte(gold), []byte(gold), ScoreOptions{})
+	if err != nil || perfect.Score != 1 {
+		t.Errorf("Expected a perfect score for identical instances, got %v, %v", perfect, err)
+	}
+}
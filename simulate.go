@@ -0,0 +1,325 @@
+package jobj
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Loss describes one piece of a schema that did not survive the conversion for a provider.
+type Loss struct {
+	Pointer string `json:"pointer"` // JSON Pointer into the original schema definition
+	Keyword string `json:"keyword"`
+	Detail  string `json:"detail"`
+}
+
+// LossReport lists everything Provider.Apply dropped or rewrote, ordered by pointer.
+type LossReport struct {
+	Provider Provider `json:"provider"`
+	Losses   []Loss   `json:"losses"`
+}
+
+// Apply simulates what the provider receives for schema: the schema definition after the
+// provider's restrictions are applied, and a report of the constraints that were lost on the way.
+//
+// For ProviderOpenAIStrict the definition is converted with Strict, so map fields become closed
+// objects, and for providers without oneOf support the variants of discriminated fields are
+// emitted as anyOf. For providers without const support, anyOf lists of consts are flattened into an enum
+// when the consts share a type; ProviderGemini only accepts string enums, so its numeric and
+// boolean consts become strings. Any remaining keyword the provider does not support (see
+// Capabilities) is removed. For the OpenAI providers every object schema carries a "required"
+// list, empty when no property is required.
+func (p Provider) Apply(schema *Schema) (map[string]interface{}, LossReport, error) {
+	supported, ok := capabilities[p]
+	if !ok {
+		return nil, LossReport{}, fmt.Errorf("unknown provider %q", p)
+	}
+	report := LossReport{Provider: p, Losses: make([]Loss, 0)}
+
+	var definition interface{}
+	if p == ProviderOpenAIStrict {
+		definition = schema.Strict()
+		if schema.RootField != nil {
+			strictFieldLosses(schema.RootField, "", &report)
+		} else {
+			strictLosses(schema.Fields, "", &report)
+		}
+	} else {
+		definition = schema.definitionJson()
+	}
+
+	encoded, err := json.Marshal(definition)
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to encode schema %q: %w", schema.Name, err)
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		return nil, report, fmt.Errorf("failed to decode schema %q: %w", schema.Name, err)
+	}
+
+	if !supported["oneOf"] && supported["anyOf"] {
+		oneOfToAnyOf(document, "", &report)
+	}
+	if !supported["const"] && supported["enum"] {
+		flattenConstUnions(document, "", stringEnums[p], &report)
+	}
+	dropUnsupported(document, "", supported, &report)
+	if explicitRequired[p] {
+		addEmptyRequired(document)
+	}
+
+	sort.SliceStable(report.Losses, func(i, j int) bool {
+		if report.Losses[i].Pointer != report.Losses[j].Pointer {
+			return report.Losses[i].Pointer < report.Losses[j].Pointer
+		}
+		return report.Losses[i].Keyword < report.Losses[j].Keyword
+	})
+	return document, report, nil
+}
+
+// explicitRequired lists the providers that expect "required": [] on objects without required
+// properties rather than no keyword. Strict mode rejects objects without the list, and the
+// non-strict dialect is given the same shape so switching modes does not change it.
+var explicitRequired = map[Provider]bool{
+	ProviderOpenAI:       true,
+	ProviderOpenAIStrict: true,
+}
+
+// addEmptyRequired adds "required": [] to every object schema in document that has properties
+// but no "required" list, in place.
+func addEmptyRequired(document interface{}) {
+	switch node := document.(type) {
+	case map[string]interface{}:
+		if _, ok := node["properties"]; ok && isObjectType(node["type"]) {
+			if required, _ := node["required"].([]interface{}); required == nil {
+				node["required"] = []interface{}{}
+			}
+		}
+		for _, value := range node {
+			addEmptyRequired(value)
+		}
+	case []interface{}:
+		for _, item := range node {
+			addEmptyRequired(item)
+		}
+	}
+}
+
+// strictLosses records what Strict discards from fields: map value schemas, contentEncoding,
+// uniqueItems and string lengths.
+func strictLosses(fields []*Field, pointer string, report *LossReport) {
+	for _, field := range fields {
+		strictFieldLosses(field, pointer+"/properties/"+escapePointerToken(field.ValueName), report)
+	}
+}
+
+func strictFieldLosses(field *Field, pointer string, report *LossReport) {
+	if field.ValueAnyOf != nil {
+		return
+	}
+	if field.ValueDiscriminator != "" {
+		report.Losses = append(report.Losses, Loss{Pointer: pointer, Keyword: "oneOf", Detail: "emitted as anyOf in strict mode; discriminator dropped"})
+	}
+	if field.ValueContentEncoding != "" {
+		report.Losses = append(report.Losses, Loss{Pointer: pointer, Keyword: "contentEncoding", Detail: "dropped in strict mode"})
+	}
+	if field.ValueType == TypeString && field.ValueMinLength != nil {
+		report.Losses = append(report.Losses, Loss{Pointer: pointer, Keyword: "minLength", Detail: "dropped in strict mode"})
+	}
+	if field.ValueType == TypeString && field.ValueMaxLength != nil {
+		report.Losses = append(report.Losses, Loss{Pointer: pointer, Keyword: "maxLength", Detail: "dropped in strict mode"})
+	}
+
+	switch field.ValueType {
+	case TypeArray:
+		if field.ValueUniqueItems {
+			report.Losses = append(report.Losses, Loss{Pointer: pointer, Keyword: "uniqueItems", Detail: "dropped in strict mode"})
+		}
+		if field.ArrayItemField != nil {
+			strictFieldLosses(field.ArrayItemField, pointer+"/items", report)
+		} else if field.SubFields != nil {
+			strictLosses(field.SubFields, pointer+"/items", report)
+		}
+	case TypeObject:
+		if field.AdditionalProperties {
+			report.Losses = append(report.Losses, Loss{Pointer: pointer, Keyword: "additionalProperties", Detail: "map values are not allowed in strict mode; the object is closed"})
+		}
+		strictLosses(field.SubFields, pointer, report)
+	}
+}
+
+// oneOfToAnyOf rewrites every oneOf in node into anyOf, in place. The variants emitted as oneOf
+// are told apart by a discriminator property, so anyOf still accepts exactly one of them.
+func oneOfToAnyOf(node interface{}, pointer string, report *LossReport) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if oneOf, ok := value["oneOf"]; ok {
+			delete(value, "oneOf")
+			value["anyOf"] = oneOf
+			report.Losses = append(report.Losses, Loss{Pointer: pointer, Keyword: "oneOf", Detail: "rewritten to anyOf"})
+		}
+		for keyword, child := range value {
+			childPointer := pointer + "/" + escapePointerToken(keyword)
+			switch keyword {
+			case "properties":
+				if properties, ok := child.(map[string]interface{}); ok {
+					for name, property := range properties {
+						oneOfToAnyOf(property, childPointer+"/"+escapePointerToken(name), report)
+					}
+				}
+			case "const", "enum", "required":
+				// Values, not schemas
+			default:
+				oneOfToAnyOf(child, childPointer, report)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			oneOfToAnyOf(item, fmt.Sprintf("%s/%d", pointer, i), report)
+		}
+	}
+}
+
+// stringEnums lists the providers that accept enum only on strings. Const unions of other types
+// are flattened into string enums for them, so the model answers with e.g. "1" rather than 1.
+var stringEnums = map[Provider]bool{
+	ProviderGemini: true,
+}
+
+// flattenConstUnions rewrites anyOf lists whose options are all consts of one type into a typed
+// enum, or into a string enum of the consts' JSON text when stringsOnly is set.
+func flattenConstUnions(node interface{}, pointer string, stringsOnly bool, report *LossReport) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if options, ok := value["anyOf"].([]interface{}); ok {
+			if enum, enumType, describedOptions, ok := constEnum(options); ok {
+				delete(value, "anyOf")
+				detail := "flattened to enum"
+				if stringsOnly && enumType != string(TypeString) {
+					for i, constValue := range enum {
+						enum[i] = constText(constValue)
+					}
+					enumType = string(TypeString)
+					detail = "flattened to string enum"
+				}
+				value["type"] = enumType
+				value["enum"] = enum
+				if describedOptions {
+					detail += "; option descriptions dropped"
+				}
+				report.Losses = append(report.Losses, Loss{Pointer: pointer, Keyword: "anyOf", Detail: detail})
+			}
+		}
+		for keyword, child := range value {
+			childPointer := pointer + "/" + escapePointerToken(keyword)
+			switch keyword {
+			case "properties":
+				if properties, ok := child.(map[string]interface{}); ok {
+					for name, property := range properties {
+						flattenConstUnions(property, childPointer+"/"+escapePointerToken(name), stringsOnly, report)
+					}
+				}
+			case "const", "enum", "required":
+				// Values, not schemas
+			default:
+				flattenConstUnions(child, childPointer, stringsOnly, report)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			flattenConstUnions(item, fmt.Sprintf("%s/%d", pointer, i), stringsOnly, report)
+		}
+	}
+}
+
+// constEnum returns the consts of an anyOf list and their JSON type when every option is a const
+// and all consts share a type.
+func constEnum(options []interface{}) ([]interface{}, string, bool, bool) {
+	enum := make([]interface{}, 0, len(options))
+	enumType := ""
+	described := false
+	for _, option := range options {
+		optionObject, ok := option.(map[string]interface{})
+		if !ok {
+			return nil, "", false, false
+		}
+		constValue, ok := optionObject["const"]
+		if !ok {
+			return nil, "", false, false
+		}
+		if description, _ := optionObject["description"].(string); description != "" {
+			described = true
+		}
+
+		var valueType string
+		switch v := constValue.(type) {
+		case string:
+			valueType = string(TypeString)
+		case bool:
+			valueType = string(TypeBoolean)
+		case float64:
+			valueType = string(TypeNumber)
+			if v == float64(int64(v)) {
+				valueType = string(TypeInteger)
+			}
+		default:
+			return nil, "", false, false
+		}
+		switch {
+		case enumType == "":
+			enumType = valueType
+		case enumType == valueType:
+		case enumType == string(TypeInteger) && valueType == string(TypeNumber),
+			enumType == string(TypeNumber) && valueType == string(TypeInteger):
+			enumType = string(TypeNumber)
+		default:
+			return nil, "", false, false
+		}
+		enum = append(enum, constValue)
+	}
+	return enum, enumType, described, len(enum) > 0
+}
+
+// constText returns the JSON text of a decoded number or boolean const.
+func constText(constValue interface{}) string {
+	switch v := constValue.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(constValue)
+}
+
+// dropUnsupported removes every keyword the provider does not support from node, recording a Loss.
+func dropUnsupported(node interface{}, pointer string, supported map[string]bool, report *LossReport) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for keyword, child := range value {
+			if !supported[keyword] {
+				delete(value, keyword)
+				report.Losses = append(report.Losses, Loss{Pointer: pointer, Keyword: keyword, Detail: "not supported"})
+				continue
+			}
+
+			childPointer := pointer + "/" + escapePointerToken(keyword)
+			switch keyword {
+			case "properties":
+				if properties, ok := child.(map[string]interface{}); ok {
+					for name, property := range properties {
+						dropUnsupported(property, childPointer+"/"+escapePointerToken(name), supported, report)
+					}
+				}
+			case "const", "enum", "required":
+				// Values, not schemas
+			default:
+				dropUnsupported(child, childPointer, supported, report)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			dropUnsupported(item, fmt.Sprintf("%s/%d", pointer, i), supported, report)
+		}
+	}
+}
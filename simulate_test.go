@@ -0,0 +1,94 @@
+package jobj
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newSimulationTestSchema() *Schema {
+	return &Schema{
+		Name: "Simulation",
+		Fields: []*Field{
+			Text("headline").Required(),
+			AnyOf("sentiment", []ConstDescription{
+				{Const: "positive", Description: "Good news"},
+				{Const: "negative"},
+			}).Required(),
+			Bytes("logo"),
+			Map("counts", TypeInteger),
+		},
+	}
+}
+
+func lossKeys(report LossReport) []string {
+	keys := make([]string, 0, len(report.Losses))
+	for _, loss := range report.Losses {
+		keys = append(keys, loss.Pointer+" "+loss.Keyword)
+	}
+	return keys
+}
+
+func TestProviderApplyGemini(t *testing.T) {
+	document, report, err := ProviderGemini.Apply(newSimulationTestSchema())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	sentiment := document["properties"].(map[string]interface{})["sentiment"].(map[string]interface{})
+	if sentiment["type"] != "string" || !reflect.DeepEqual(sentiment["enum"], []interface{}{"positive", "negative"}) {
+		t.Errorf("Expected sentiment to be flattened to a string enum, got %v", sentiment)
+	}
+	if _, ok := sentiment["anyOf"]; ok {
+		t.Errorf("Expected anyOf to be removed, got %v", sentiment)
+	}
+	if _, ok := document["additionalProperties"]; ok {
+		t.Errorf("Expected additionalProperties to be removed, got %v", document)
+	}
+
+	expected := []string{
+		" additionalProperties",
+		"/properties/counts additionalProperties",
+		"/properties/logo contentEncoding",
+		"/properties/sentiment anyOf",
+	}
+	if keys := lossKeys(report); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected losses %v, got %v", expected, keys)
+	}
+	if report.Losses[3].Detail != "flattened to enum; option descriptions dropped" {
+		t.Errorf("Unexpected anyOf loss detail: %q", report.Losses[3].Detail)
+	}
+}
+
+func TestProviderApplyOpenAIStrict(t *testing.T) {
+	document, report, err := ProviderOpenAIStrict.Apply(newSimulationTestSchema())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	counts := document["properties"].(map[string]interface{})["counts"].(map[string]interface{})
+	if counts["additionalProperties"] != false {
+		t.Errorf("Expected counts to become a closed object, got %v", counts)
+	}
+
+	expected := []string{
+		"/properties/counts additionalProperties",
+		"/properties/logo contentEncoding",
+	}
+	if keys := lossKeys(report); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected losses %v, got %v", expected, keys)
+	}
+}
+
+func TestProviderApplyAnthropic(t *testing.T) {
+	_, report, err := ProviderAnthropic.Apply(newSimulationTestSchema())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(report.Losses) != 0 {
+		t.Errorf("Expected no losses, got %v", report.Losses)
+	}
+
+	if _, _, err := Provider("unknown").Apply(newSimulationTestSchema()); err == nil {
+		t.Error("Expected error for unknown provider")
+	}
+}
@@ -0,0 +1,254 @@
+package jobj
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// snapshotFormat is the version of the document written by Registry.Export.
+const snapshotFormat = 1
+
+// registrySnapshot is the document written by Registry.Export and read by Registry.Import.
+type registrySnapshot struct {
+	Format    int                `json:"format"`
+	Schemas   []snapshotSchema   `json:"schemas"`
+	Overrides []snapshotOverride `json:"overrides,omitempty"`
+}
+
+// snapshotSchema is one schema in a snapshot. Name, ID and Version repeat the schema's own values
+// so a catalog can be inspected without decoding the schemas.
+type snapshotSchema struct {
+	Name        string  `json:"name"`
+	ID          string  `json:"id,omitempty"`
+	Version     string  `json:"version,omitempty"`
+	Fingerprint string  `json:"fingerprint"`
+	Schema      *Schema `json:"schema"`
+}
+
+type snapshotOverride struct {
+	Profile string          `json:"profile"`
+	Name    string          `json:"name"`
+	Patch   json.RawMessage `json:"patch"`
+}
+
+// Fingerprint returns a hex-encoded SHA-256 digest of the schema's complete definition: fields,
+// descriptions, identifiers and serialization options. Two schemas with the same fingerprint
+// produce the same output everywhere, so workers can compare fingerprints to check they share a
+// toolset.
+func (r *Schema) Fingerprint() (string, error) {
+	encoded, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode schema %q: %w", r.Name, err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Export writes the registry's full catalog to w as JSON: every schema with its identifier,
+// version and Fingerprint, and every profile override. Registered schemas that have not been
+// built yet are built first, so Export fails if any of them does. Schemas are written in name
+// order, so exporting the same catalog always produces the same bytes.
+//
+// A control plane can Export its registry once and distribute the result to agent workers, which
+// load it with Import. Schemas in namespaces are exported under their qualified names; use
+// Namespace.Export to export a single namespace.
+func (r *Registry) Export(w io.Writer) error {
+	return r.export(w, "")
+}
+
+// export implements Export for the schemas and overrides in namespace, written under their names
+// within it, or for the whole registry if namespace is empty.
+func (r *Registry) export(w io.Writer, namespace string) error {
+	snapshot, err := r.snapshot(namespace)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshot); err != nil {
+		return fmt.Errorf("failed to write registry snapshot: %w", err)
+	}
+	return nil
+}
+
+// snapshot returns the catalog of the schemas and overrides in namespace, named within it, or of
+// the whole registry if namespace is empty.
+func (r *Registry) snapshot(namespace string) (*registrySnapshot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.creators)+len(r.built))
+	for name := range r.creators {
+		if _, ok := r.built[name]; !ok && inNamespace(name, namespace) {
+			names = append(names, name)
+		}
+	}
+	for name := range r.built {
+		if inNamespace(name, namespace) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	snapshot := registrySnapshot{Format: snapshotFormat, Schemas: make([]snapshotSchema, 0, len(names))}
+	for _, name := range names {
+		schema, err := r.build(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export schema %q: %w", name, err)
+		}
+		fingerprint, err := schema.Fingerprint()
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Schemas = append(snapshot.Schemas, snapshotSchema{
+			Name:        withinNamespace(name, namespace),
+			ID:          schema.SchemaID,
+			Version:     schema.Version,
+			Fingerprint: fingerprint,
+			Schema:      schema,
+		})
+	}
+
+	for key, patch := range r.overrides {
+		if inNamespace(key.name, namespace) {
+			snapshot.Overrides = append(snapshot.Overrides, snapshotOverride{
+				Profile: key.profile,
+				Name:    withinNamespace(key.name, namespace),
+				Patch:   patch,
+			})
+		}
+	}
+	sort.Slice(snapshot.Overrides, func(i, j int) bool {
+		a, b := snapshot.Overrides[i], snapshot.Overrides[j]
+		if a.Profile != b.Profile {
+			return a.Profile < b.Profile
+		}
+		return a.Name < b.Name
+	})
+	return &snapshot, nil
+}
+
+// Import reads a catalog written by Export and adds its schemas and overrides to the registry.
+// Every schema is checked with Finalize and against its recorded fingerprint, so a snapshot that
+// was corrupted or edited by hand is rejected. Import is all or nothing: if any schema is
+// invalid or its name is already registered, nothing is added.
+func (r *Registry) Import(reader io.Reader) error {
+	return r.importSnapshot(reader, "")
+}
+
+// importSnapshot implements Import, adding the snapshot's schemas and overrides to namespace, or
+// under their names as written if namespace is empty.
+func (r *Registry) importSnapshot(reader io.Reader, namespace string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read registry snapshot: %w", err)
+	}
+	var signed struct {
+		Snapshot json.RawMessage `json:"snapshot"`
+	}
+	if json.Unmarshal(data, &signed) == nil && signed.Snapshot != nil {
+		return fmt.Errorf("registry snapshot is signed; load it with ImportSigned")
+	}
+	return r.importData(data, namespace)
+}
+
+// importData implements Import for the snapshot document in data.
+func (r *Registry) importData(data []byte, namespace string) error {
+	var snapshot registrySnapshot
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&snapshot); err != nil {
+		return fmt.Errorf("failed to parse registry snapshot: %w", err)
+	}
+	if snapshot.Format != snapshotFormat {
+		return fmt.Errorf("unsupported registry snapshot format %d", snapshot.Format)
+	}
+
+	names := make([]string, 0, len(snapshot.Schemas))
+	schemas := make([]*Schema, 0, len(snapshot.Schemas))
+	for _, entry := range snapshot.Schemas {
+		schema := entry.Schema
+		if schema == nil || schema.Name == "" || (schema.Name != entry.Name && schema.Name != localName(entry.Name)) {
+			return fmt.Errorf("snapshot entry %q does not hold a schema of that name", entry.Name)
+		}
+		name, err := qualifiedName(namespace, entry.Name)
+		if err != nil {
+			return err
+		}
+		snapshotConsts(schema)
+		if err := schema.Finalize(); err != nil {
+			return err
+		}
+		fingerprint, err := schema.Fingerprint()
+		if err != nil {
+			return err
+		}
+		if fingerprint != entry.Fingerprint {
+			return fmt.Errorf("schema %q does not match its fingerprint", entry.Name)
+		}
+		names = append(names, name)
+		schemas = append(schemas, schema)
+	}
+	overrideNames := make([]string, 0, len(snapshot.Overrides))
+	for _, override := range snapshot.Overrides {
+		name, err := qualifiedName(namespace, override.Name)
+		if err != nil {
+			return err
+		}
+		overrideNames = append(overrideNames, name)
+		if override.Profile == "" {
+			return fmt.Errorf("override for schema %q: profile must not be empty", override.Name)
+		}
+		if _, err := decodeDocument(override.Patch); err != nil {
+			return fmt.Errorf("override for schema %q in profile %q: invalid merge patch: %w", override.Name, override.Profile, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range names {
+		if r.registered(name) {
+			return fmt.Errorf("schema %q is already registered", name)
+		}
+	}
+	for i, schema := range schemas {
+		r.built[names[i]] = schema
+	}
+	for i, override := range snapshot.Overrides {
+		key := profileKey{profile: override.Profile, name: overrideNames[i]}
+		r.overrides[key] = append([]byte(nil), override.Patch...)
+		delete(r.resolved, key)
+	}
+	return nil
+}
+
+// snapshotConsts converts the json.Number consts of a decoded schema back to ints and float64s,
+// as documentConst does for patched schemas.
+func snapshotConsts(schema *Schema) {
+	var convert func(field *Field)
+	convert = func(field *Field) {
+		if field == nil {
+			return
+		}
+		for i := range field.ValueAnyOf {
+			field.ValueAnyOf[i].Const = documentConst(field.ValueAnyOf[i].Const)
+		}
+		for _, subField := range field.SubFields {
+			convert(subField)
+		}
+		convert(field.ArrayItemField)
+		convert(field.AdditionalPropertiesField)
+	}
+
+	for _, field := range schema.Fields {
+		convert(field)
+	}
+	convert(schema.RootField)
+	convert(schema.AdditionalPropertiesField)
+}
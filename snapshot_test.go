@@ -0,0 +1,99 @@
+package jobj
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegistryExportImport(t *testing.T) {
+	source := NewRegistry()
+	_ = source.Register("Counting", &countingSchema{})
+	_ = source.RegisterSchema(&Schema{
+		Name:        "Headlines",
+		Description: "Headline extraction",
+		SchemaID:    "https://example.com/headlines.json",
+		Version:     "1.2.0",
+		Fields: []*Field{
+			Text("headline").Desc("The headline").Required(),
+			AnyOf("priority", []ConstDescription{{Const: 1, Description: "Low"}, {Const: 2.5}, {Const: "high"}}),
+			Array("quotes", []*Field{Text("speaker").AsXMLAttribute(), Text("text").Deprecated()}),
+			MapOf("counts", Object("", []*Field{Int("n")})),
+		},
+	})
+	_ = source.Override("staging", "Headlines", []byte(`{"description": "Staging headlines"}`))
+
+	var exported bytes.Buffer
+	if err := source.Export(&exported); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	var again bytes.Buffer
+	_ = source.Export(&again)
+	if exported.String() != again.String() {
+		t.Errorf("Expected Export to be deterministic")
+	}
+
+	target := NewRegistry()
+	if err := target.Import(bytes.NewReader(exported.Bytes())); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if got := strings.Join(target.Names(), ","); got != "Counting,Headlines" {
+		t.Errorf("Expected both schemas, got %s", got)
+	}
+
+	for _, name := range []string{"Counting", "Headlines"} {
+		original, _ := source.Build(name)
+		imported, err := target.Build(name)
+		if err != nil {
+			t.Fatalf("Build %s failed: %v", name, err)
+		}
+		if original.GetSchemaString() != imported.GetSchemaString() {
+			t.Errorf("%s: schema changed on import:\n%s\n%s", name, original.GetSchemaString(), imported.GetSchemaString())
+		}
+		originalFingerprint, _ := original.Fingerprint()
+		importedFingerprint, _ := imported.Fingerprint()
+		if originalFingerprint != importedFingerprint {
+			t.Errorf("%s: fingerprint changed on import", name)
+		}
+	}
+
+	imported, _ := target.Build("Headlines")
+	if _, ok := imported.Fields[1].ValueAnyOf[0].Const.(int); !ok {
+		t.Errorf("Expected integer consts to stay ints, got %T", imported.Fields[1].ValueAnyOf[0].Const)
+	}
+	if !imported.Fields[2].SubFields[0].ValueXMLAttribute || !imported.Fields[2].SubFields[1].ValueDeprecated {
+		t.Errorf("Expected field modifiers to survive import")
+	}
+
+	staging, err := target.BuildProfile("staging", "Headlines")
+	if err != nil || staging.Description != "Staging headlines" {
+		t.Errorf("Expected the staging override to be imported, got %v, %v", staging, err)
+	}
+
+	if err := target.Import(bytes.NewReader(exported.Bytes())); err == nil {
+		t.Errorf("Expected importing the same names twice to fail")
+	}
+}
+
+func TestRegistryImportRejectsTamperedSnapshot(t *testing.T) {
+	source := NewRegistry()
+	_ = source.RegisterSchema(&Schema{Name: "Headlines", Fields: []*Field{Text("headline").Desc("The headline")}})
+	_ = source.RegisterSchema(&Schema{Name: "Other", Fields: []*Field{Text("a")}})
+
+	var exported bytes.Buffer
+	_ = source.Export(&exported)
+	tampered := strings.Replace(exported.String(), "The headline", "Edited", 1)
+
+	target := NewRegistry()
+	if err := target.Import(strings.NewReader(tampered)); err == nil || !strings.Contains(err.Error(), "fingerprint") {
+		t.Errorf("Expected a fingerprint error, got %v", err)
+	}
+	if len(target.Names()) != 0 {
+		t.Errorf("Expected a failed Import to add nothing, got %v", target.Names())
+	}
+
+	if err := target.Import(strings.NewReader(`{"format": 99, "schemas": []}`)); err == nil {
+		t.Errorf("Expected an unknown format to be rejected")
+	}
+}
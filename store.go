@@ -0,0 +1,173 @@
+package jobj
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// schemaFileExtensions are the files a Store loads from its directory.
+var schemaFileExtensions = map[string]bool{
+	".json": true,
+	".yaml": true,
+	".yml":  true,
+	".jobj": true,
+}
+
+// Store serves schemas loaded from a directory of schema files (see LoadSchemaFile) and reloads
+// them when the files change, so long-running servers can tune extraction schemas without a restart.
+//
+// Every reload builds a fresh Registry and swaps it in atomically: readers see either the old
+// schemas or the new ones, never a mix. A reload that fails, including a schema that no longer
+// validates against its bound struct, leaves the current Registry in place.
+type Store struct {
+	dir      string
+	registry atomic.Pointer[Registry]
+
+	mu       sync.Mutex
+	bindings map[string]interface{}
+	loaded   string // fingerprint of the files behind the current Registry
+}
+
+// NewStore returns a Store for the schema files in dir. Call Load before use.
+func NewStore(dir string) *Store {
+	s := &Store{
+		dir:      dir,
+		bindings: make(map[string]interface{}),
+	}
+	s.registry.Store(NewRegistry())
+	return s
+}
+
+// Bind associates the schema named name with the struct its responses are decoded into.
+// structPtr must be a pointer to a struct; on every load the schema is checked with Validate.
+func (s *Store) Bind(name string, structPtr interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bindings[name] = structPtr
+}
+
+// Registry returns the current Registry. The returned Registry is never modified by later reloads.
+func (s *Store) Registry() *Registry {
+	return s.registry.Load()
+}
+
+// Get returns the current schema named name.
+func (s *Store) Get(name string) (*Schema, error) {
+	return s.Registry().Build(name)
+}
+
+// Load reads every schema file in the directory, validates them against their bound structs and
+// swaps in the result. On error the previously loaded schemas stay in use.
+func (s *Store) Load() error {
+	fingerprint, err := s.fingerprint()
+	if err != nil {
+		return err
+	}
+	paths, err := s.schemaFiles()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	registry := NewRegistry()
+	for _, path := range paths {
+		schema, err := LoadSchemaFile(path)
+		if err != nil {
+			return err
+		}
+		if err := registry.RegisterSchema(schema); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	for name, structPtr := range s.bindings {
+		schema, err := registry.Build(name)
+		if err != nil {
+			return fmt.Errorf("bound schema %q: %w", name, err)
+		}
+		if err := schema.Validate(structPtr); err != nil {
+			return fmt.Errorf("bound schema %q: %w", name, err)
+		}
+	}
+
+	s.registry.Store(registry)
+	s.loaded = fingerprint
+	return nil
+}
+
+// Watch polls the directory every interval and reloads when a schema file is added, removed or
+// modified. Reload errors are logged and the previous schemas are kept. Watch blocks until ctx is done.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.mu.Lock()
+	last := s.loaded
+	s.mu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := s.fingerprint()
+		if err != nil {
+			logError("Error reading schema directory", "dir", s.dir, "err", err)
+			continue
+		}
+		if current == last {
+			continue
+		}
+		last = current
+
+		if err := s.Load(); err != nil {
+			logError("Error reloading schemas, keeping previous version", "dir", s.dir, "err", err)
+		}
+	}
+}
+
+func (s *Store) schemaFiles() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema directory: %w", err)
+	}
+
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !schemaFileExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		paths = append(paths, filepath.Join(s.dir, entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// fingerprint summarises the names, sizes and modification times of the schema files.
+func (s *Store) fingerprint() (string, error) {
+	paths, err := s.schemaFiles()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,91 @@
+package jobj
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type storeTestResponse struct {
+	Headline string `json:"headline"`
+}
+
+func TestStoreLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Headlines.jobj")
+	if err := os.WriteFile(path, []byte(`headline: string! "The headline"`), 0o600); err != nil {
+		t.Fatalf("Failed to write schema file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600); err != nil {
+		t.Fatalf("Failed to write notes file: %v", err)
+	}
+
+	store := NewStore(dir)
+	store.Bind("Headlines", &storeTestResponse{})
+
+	if err := store.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	schema, err := store.Get("Headlines")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(schema.Fields) != 1 || schema.Fields[0].ValueDescription != "The headline" {
+		t.Errorf("Unexpected schema: %+v", schema)
+	}
+	if names := store.Registry().Names(); len(names) != 1 {
+		t.Errorf("Expected only the schema file to be loaded, got %v", names)
+	}
+
+	// A schema that no longer matches its bound struct is rejected and the old one kept
+	if err := os.WriteFile(path, []byte(`title: string!`), 0o600); err != nil {
+		t.Fatalf("Failed to write schema file: %v", err)
+	}
+	if err := store.Load(); err == nil {
+		t.Error("Expected Load to fail validation against bound struct")
+	}
+	if kept, _ := store.Get("Headlines"); kept != schema {
+		t.Error("Expected previous schema to be kept after failed reload")
+	}
+}
+
+func TestStoreWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Headlines.jobj")
+	if err := os.WriteFile(path, []byte(`headline: string`), 0o600); err != nil {
+		t.Fatalf("Failed to write schema file: %v", err)
+	}
+
+	store := NewStore(dir)
+	if err := store.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		store.Watch(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if err := os.WriteFile(path, []byte(`headline: string! "Updated description"`), 0o600); err != nil {
+		t.Fatalf("Failed to write schema file: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		schema, err := store.Get("Headlines")
+		if err == nil && schema.Fields[0].ValueDescription == "Updated description" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Expected store to reload the changed schema file")
+}
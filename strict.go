@@ -0,0 +1,136 @@
+package jobj
+
+// Strict returns the schema as a JSON Schema object compatible with OpenAI strict structured outputs.
+//
+// OpenAI strict mode requires every property to be listed in "required" and every object to set
+// "additionalProperties": false. To preserve optionality, fields that are not marked Required are
+// made nullable instead. Map fields, which strict mode cannot express, lose their value schema and
+// become closed objects, and keywords strict mode rejects, such as contentEncoding, are dropped.
+// For the same reason the root object stays closed even after AllowAdditionalProperties.
+func (r *Schema) Strict() map[string]interface{} {
+	var document map[string]interface{}
+	if r.RootField != nil {
+		document = strictField(r.RootField, !r.RootField.ValueNullable)
+	} else {
+		document = strictObject(r.Fields, r.Description)
+	}
+	r.orderLists(document)
+	return document
+}
+
+func strictObject(fields []*Field, description string) map[string]interface{} {
+	properties := make(map[string]interface{}, len(fields))
+	required := make([]string, 0, len(fields))
+	for _, field := range fields {
+		properties[field.ValueName] = strictField(field, field.ValueRequired && !field.ValueNullable)
+		required = append(required, field.ValueName)
+	}
+
+	object := map[string]interface{}{
+		"type":                 string(TypeObject),
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+	if description != "" {
+		object["description"] = description
+	}
+	return object
+}
+
+func strictField(field *Field, required bool) map[string]interface{} {
+	if field.ValueAnyOf != nil {
+		anyOf := make([]interface{}, 0, len(field.ValueAnyOf)+1)
+		for _, enum := range field.ValueAnyOf {
+			anyOf = append(anyOf, map[string]interface{}{
+				"const":       enum.Const,
+				"description": enum.Description,
+			})
+		}
+		if !required {
+			anyOf = append(anyOf, map[string]interface{}{"type": "null"})
+		}
+
+		schema := map[string]interface{}{
+			"anyOf": anyOf,
+		}
+		if field.ValueDescription != "" {
+			schema["description"] = field.ValueDescription
+		}
+		return schema
+	}
+
+	if field.ValueVariants != nil {
+		anyOf := make([]interface{}, 0, len(field.ValueVariants)+1)
+		for _, variant := range field.ValueVariants {
+			anyOf = append(anyOf, strictField(variant, true))
+		}
+		if !required {
+			anyOf = append(anyOf, map[string]interface{}{"type": "null"})
+		}
+
+		schema := map[string]interface{}{
+			"anyOf": anyOf,
+		}
+		if field.ValueDescription != "" {
+			schema["description"] = field.ValueDescription
+		}
+		return schema
+	}
+
+	var schema map[string]interface{}
+	switch field.ValueType {
+	case "":
+		// Any value, null included
+		schema = map[string]interface{}{}
+		if field.ValueDescription != "" {
+			schema["description"] = field.ValueDescription
+		}
+		return schema
+	case TypeArray:
+		schema = map[string]interface{}{
+			"type": string(TypeArray),
+		}
+		if field.ArrayItemField != nil {
+			schema["items"] = strictField(field.ArrayItemField, true)
+		} else if field.ArrayItemType != "" {
+			schema["items"] = map[string]interface{}{
+				"type": string(field.ArrayItemType),
+			}
+		} else {
+			schema["items"] = strictObject(field.SubFields, "")
+		}
+		addItemsDescription(field, schema)
+		if field.ValueMinItems != nil {
+			schema["minItems"] = *field.ValueMinItems
+		}
+		if field.ValueMaxItems != nil {
+			schema["maxItems"] = *field.ValueMaxItems
+		}
+	case TypeObject:
+		// Map fields (additionalProperties schemas) are not supported in strict mode,
+		// so they collapse to an object with their declared SubFields, if any.
+		schema = strictObject(field.SubFields, "")
+	default:
+		schema = map[string]interface{}{
+			"type": string(field.ValueType),
+		}
+		if field.ValueFormat != "" {
+			schema["format"] = field.ValueFormat
+		}
+		if field.ValueMinimum != nil {
+			schema["minimum"] = *field.ValueMinimum
+		}
+		if field.ValueMaximum != nil {
+			schema["maximum"] = *field.ValueMaximum
+		}
+	}
+
+	if field.ValueDescription != "" {
+		schema["description"] = field.ValueDescription
+	}
+	if !required {
+		schema["type"] = []string{schema["type"].(string), "null"}
+	}
+	return schema
+}
@@ -0,0 +1,60 @@
+package jobj
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+	s := &Schema{
+		Name:        "StrictTest",
+		Description: "A strict test schema",
+		Fields: []*Field{
+			Text("name").Desc("The name field").Required(),
+			Int("count").Desc("The count field"),
+			AnyOf("kind", []ConstDescription{{Const: "a", Description: "first"}}),
+			Object("meta", []*Field{
+				Text("source").Required(),
+				Bool("verified"),
+			}).Required(),
+			Array("items", []*Field{Float("score")}).Required(),
+		},
+	}
+
+	got, err := json.Marshal(s.Strict())
+	if err != nil {
+		t.Fatalf("Failed to marshal strict schema: %v", err)
+	}
+
+	want := `{"additionalProperties":false,"description":"A strict test schema","properties":{` +
+		`"count":{"description":"The count field","type":["integer","null"]},` +
+		`"items":{"items":{"additionalProperties":false,"properties":{"score":{"type":["number","null"]}},"required":["score"],"type":"object"},"type":"array"},` +
+		`"kind":{"anyOf":[{"const":"a","description":"first"},{"type":"null"}]},` +
+		`"meta":{"additionalProperties":false,"properties":{"source":{"type":"string"},"verified":{"type":["boolean","null"]}},"required":["source","verified"],"type":"object"},` +
+		`"name":{"description":"The name field","type":"string"}},` +
+		`"required":["name","count","kind","meta","items"],"type":"object"}`
+
+	if string(got) != want {
+		t.Errorf("Unexpected strict schema.\nwant: %s\ngot:  %s", want, got)
+	}
+}
+
+func TestStrictMapField(t *testing.T) {
+	s := &Schema{
+		Name: "StrictMap",
+		Fields: []*Field{
+			{
+				ValueName:                "labels",
+				ValueType:                TypeObject,
+				ValueRequired:            true,
+				AdditionalProperties:     true,
+				AdditionalPropertiesType: TypeString,
+			},
+		},
+	}
+
+	labels := s.Strict()["properties"].(map[string]interface{})["labels"].(map[string]interface{})
+	if labels["additionalProperties"] != false {
+		t.Errorf("Expected map field to be closed in strict mode, got %v", labels["additionalProperties"])
+	}
+}
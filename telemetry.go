@@ -0,0 +1,237 @@
+package jobj
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Collector tracks which schema fields a model actually populates. Feed it instances that have
+// already passed ValidateJSON and read the results with Stats; fields with a low fill rate are
+// candidates for pruning, and shifting enum distributions point at drift. A Collector is safe for
+// concurrent use.
+type Collector struct {
+	schema *Schema
+
+	mu        sync.Mutex
+	instances int
+	fields    map[string]*fieldCounter
+}
+
+type fieldCounter struct {
+	seen      int
+	populated int
+	enums     map[string]int
+}
+
+// CollectorStats is a snapshot of a Collector.
+type CollectorStats struct {
+	Schema    string       `json:"schema"`
+	Instances int          `json:"instances"`
+	Fields    []FieldStats `json:"fields"`
+}
+
+// FieldStats describes how often one field was populated.
+//
+// Pointer locates the field like a JSON Pointer, with "*" standing for every array item, e.g.
+// "/quotes/*/speaker". Seen counts the objects the field could have appeared in (instances for
+// top-level fields, array items or parent objects for nested ones) and Populated those where it
+// was present with a non-null, non-empty value. EnumCounts holds the value distribution of AnyOf fields.
+type FieldStats struct {
+	Pointer    string         `json:"pointer"`
+	Seen       int            `json:"seen"`
+	Populated  int            `json:"populated"`
+	FillRate   float64        `json:"fill_rate"`
+	EnumCounts map[string]int `json:"enum_counts,omitempty"`
+}
+
+// NewCollector returns a Collector for instances of schema.
+func NewCollector(schema *Schema) *Collector {
+	return &Collector{
+		schema: schema,
+		fields: make(map[string]*fieldCounter),
+	}
+}
+
+// Observe records one JSON instance. It returns an error if data is not a JSON object.
+func (c *Collector) Observe(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected a JSON object, got %s", describeValue(value))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.instances++
+	c.observeObject(c.schema.Fields, object, "")
+	return nil
+}
+
+// Stats returns the collected statistics, ordered by pointer.
+func (c *Collector) Stats() CollectorStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := CollectorStats{
+		Schema:    c.schema.Name,
+		Instances: c.instances,
+		Fields:    make([]FieldStats, 0, len(c.fields)),
+	}
+	for pointer, counter := range c.fields {
+		field := FieldStats{
+			Pointer:   pointer,
+			Seen:      counter.seen,
+			Populated: counter.populated,
+		}
+		if counter.seen > 0 {
+			field.FillRate = float64(counter.populated) / float64(counter.seen)
+		}
+		if counter.enums != nil {
+			field.EnumCounts = make(map[string]int, len(counter.enums))
+			for value, count := range counter.enums {
+				field.EnumCounts[value] = count
+			}
+		}
+		stats.Fields = append(stats.Fields, field)
+	}
+	sort.Slice(stats.Fields, func(i, j int) bool {
+		return stats.Fields[i].Pointer < stats.Fields[j].Pointer
+	})
+	return stats
+}
+
+// CleanupReport lists fields that schema owners can act on, based on what a Collector observed.
+type CleanupReport struct {
+	Schema    string `json:"schema"`
+	Instances int    `json:"instances"`
+	// DeprecatedInUse are deprecated fields that models still populate, so consumers may still rely on them.
+	DeprecatedInUse []FieldStats `json:"deprecated_in_use"`
+	// DeprecatedUnused are deprecated fields that are never populated and can be removed.
+	DeprecatedUnused []FieldStats `json:"deprecated_unused"`
+	// Unused are other fields that are never populated, candidates for deprecation.
+	Unused []FieldStats `json:"unused"`
+}
+
+// CleanupReport compares the collected statistics with the fields marked Deprecated in the schema.
+// Fields that never had a chance to appear (for example, the items of an array that was always
+// empty) are left out.
+func (c *Collector) CleanupReport() CleanupReport {
+	stats := c.Stats()
+	deprecated := make(map[string]bool)
+	collectDeprecated(c.schema.Fields, "", deprecated)
+
+	report := CleanupReport{
+		Schema:           stats.Schema,
+		Instances:        stats.Instances,
+		DeprecatedInUse:  make([]FieldStats, 0),
+		DeprecatedUnused: make([]FieldStats, 0),
+		Unused:           make([]FieldStats, 0),
+	}
+	for _, field := range stats.Fields {
+		if field.Seen == 0 {
+			continue
+		}
+		switch {
+		case deprecated[field.Pointer] && field.Populated > 0:
+			report.DeprecatedInUse = append(report.DeprecatedInUse, field)
+		case deprecated[field.Pointer]:
+			report.DeprecatedUnused = append(report.DeprecatedUnused, field)
+		case field.Populated == 0:
+			report.Unused = append(report.Unused, field)
+		}
+	}
+	return report
+}
+
+// collectDeprecated records the pointers, in Collector form, of deprecated fields.
+func collectDeprecated(fields []*Field, pointer string, deprecated map[string]bool) {
+	for _, field := range fields {
+		fieldPointer := pointer + "/" + escapePointerToken(field.ValueName)
+		if field.ValueDeprecated {
+			deprecated[fieldPointer] = true
+		}
+		switch {
+		case field.ValueType == TypeObject && !field.AdditionalProperties:
+			collectDeprecated(field.SubFields, fieldPointer, deprecated)
+		case field.ValueType == TypeArray && field.SubFields != nil:
+			collectDeprecated(field.SubFields, fieldPointer+"/*", deprecated)
+		}
+	}
+}
+
+// MarshalJSON encodes the current Stats, so a Collector can be dumped directly.
+func (c *Collector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Stats())
+}
+
+// observeObject records the fields of one object. The caller must hold c.mu.
+func (c *Collector) observeObject(fields []*Field, object map[string]interface{}, pointer string) {
+	for _, field := range fields {
+		fieldPointer := pointer + "/" + escapePointerToken(field.ValueName)
+		counter := c.counter(fieldPointer)
+		counter.seen++
+
+		value, exists := object[field.ValueName]
+		if !exists || !isPopulated(value) {
+			continue
+		}
+		counter.populated++
+
+		if field.ValueAnyOf != nil {
+			if counter.enums == nil {
+				counter.enums = make(map[string]int)
+			}
+			counter.enums[fmt.Sprintf("%v", value)]++
+			continue
+		}
+
+		switch {
+		case field.ValueType == TypeObject && !field.AdditionalProperties:
+			if nested, ok := value.(map[string]interface{}); ok {
+				c.observeObject(field.SubFields, nested, fieldPointer)
+			}
+		case field.ValueType == TypeArray && field.SubFields != nil:
+			if items, ok := value.([]interface{}); ok {
+				for _, item := range items {
+					if nested, ok := item.(map[string]interface{}); ok {
+						c.observeObject(field.SubFields, nested, fieldPointer+"/*")
+					}
+				}
+			}
+		}
+	}
+}
+
+func (c *Collector) counter(pointer string) *fieldCounter {
+	counter, ok := c.fields[pointer]
+	if !ok {
+		counter = &fieldCounter{}
+		c.fields[pointer] = counter
+	}
+	return counter
+}
+
+func isPopulated(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
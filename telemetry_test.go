@@ -0,0 +1,146 @@
+package jobj
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	s := &Schema{
+		Name: "Telemetry",
+		Fields: []*Field{
+			Text("headline").Required(),
+			Text("summary"),
+			AnyOf("sentiment", []ConstDescription{
+				{Const: "positive"},
+				{Const: "negative"},
+			}),
+			Array("quotes", []*Field{
+				Text("speaker"),
+				Text("text"),
+			}),
+		},
+	}
+
+	collector := NewCollector(s)
+	instances := []string{
+		`{"headline":"a","summary":"","sentiment":"positive","quotes":[{"speaker":"x","text":"t"},{"text":"u"}]}`,
+		`{"headline":"b","sentiment":"positive"}`,
+		`{"headline":"c","summary":"s","sentiment":"negative","quotes":[]}`,
+	}
+	for _, instance := range instances {
+		if err := collector.Observe([]byte(instance)); err != nil {
+			t.Fatalf("Observe failed: %v", err)
+		}
+	}
+
+	if err := collector.Observe([]byte(`[]`)); err == nil {
+		t.Error("Expected error for non-object instance")
+	}
+
+	stats := collector.Stats()
+	if stats.Schema != "Telemetry" || stats.Instances != 3 {
+		t.Errorf("Unexpected stats header: %+v", stats)
+	}
+
+	byPointer := make(map[string]FieldStats)
+	for _, field := range stats.Fields {
+		byPointer[field.Pointer] = field
+	}
+
+	tests := []struct {
+		pointer   string
+		seen      int
+		populated int
+	}{
+		{"/headline", 3, 3},
+		{"/summary", 3, 1},
+		{"/quotes", 3, 1},
+		{"/quotes/*/speaker", 2, 1},
+		{"/quotes/*/text", 2, 2},
+	}
+	for _, tt := range tests {
+		field := byPointer[tt.pointer]
+		if field.Seen != tt.seen || field.Populated != tt.populated {
+			t.Errorf("%s: expected %d/%d, got %d/%d", tt.pointer, tt.populated, tt.seen, field.Populated, field.Seen)
+		}
+	}
+
+	sentiment := byPointer["/sentiment"]
+	if sentiment.EnumCounts["positive"] != 2 || sentiment.EnumCounts["negative"] != 1 {
+		t.Errorf("Unexpected enum counts: %v", sentiment.EnumCounts)
+	}
+	if rate := byPointer["/summary"].FillRate; rate < 0.33 || rate > 0.34 {
+		t.Errorf("Expected summary fill rate of 1/3, got %v", rate)
+	}
+
+	dump, err := json.Marshal(collector)
+	if err != nil {
+		t.Fatalf("Failed to marshal collector: %v", err)
+	}
+	var decoded CollectorStats
+	if err := json.Unmarshal(dump, &decoded); err != nil {
+		t.Fatalf("Failed to decode dump: %v", err)
+	}
+	if decoded.Instances != 3 || len(decoded.Fields) != len(stats.Fields) {
+		t.Errorf("Unexpected JSON dump: %s", dump)
+	}
+}
+
+func TestCollectorCleanupReport(t *testing.T) {
+	s := &Schema{
+		Name: "Cleanup",
+		Fields: []*Field{
+			Text("headline").Required(),
+			Text("title").Deprecated(),
+			Text("subtitle").Deprecated(),
+			Text("notes"),
+			Array("quotes", []*Field{
+				Text("speaker"),
+				Text("role").Deprecated(),
+			}),
+		},
+	}
+
+	collector := NewCollector(s)
+	for _, instance := range []string{
+		`{"headline":"a","title":"legacy"}`,
+		`{"headline":"b","quotes":[{"speaker":"x"}]}`,
+	} {
+		if err := collector.Observe([]byte(instance)); err != nil {
+			t.Fatalf("Observe failed: %v", err)
+		}
+	}
+
+	report := collector.CleanupReport()
+
+	pointers := func(fields []FieldStats) []string {
+		result := make([]string, 0, len(fields))
+		for _, field := range fields {
+			result = append(result, field.Pointer)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"DeprecatedInUse", pointers(report.DeprecatedInUse), []string{"/title"}},
+		{"DeprecatedUnused", pointers(report.DeprecatedUnused), []string{"/quotes/*/role", "/subtitle"}},
+		{"Unused", pointers(report.Unused), []string{"/notes"}},
+	}
+	for _, tt := range tests {
+		if len(tt.got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, tt.got)
+			continue
+		}
+		for i := range tt.want {
+			if tt.got[i] != tt.want[i] {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.want, tt.got)
+				break
+			}
+		}
+	}
+}
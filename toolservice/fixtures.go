@@ -0,0 +1,185 @@
+package toolservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrNoFixture is returned by a replayed tool called with input no fixture was recorded for.
+var ErrNoFixture = errors.New("no fixture recorded")
+
+// Fixture is one recorded call of a tool. Input holds the decoded parameters the handler received
+// and Output its result, both encoded as JSON; a call that failed with a ToolError, e.g. a
+// timeout, records the error instead of an output.
+type Fixture struct {
+	Tool   string          `json:"tool"`
+	Input  json.RawMessage `json:"input"`
+	Output json.RawMessage `json:"output,omitempty"`
+	Error  *ToolError      `json:"error,omitempty"`
+}
+
+// Recorder records the calls of tools into fixtures, so agent tests can later replay them with
+// Replay instead of calling live backends.
+//
+// Example:
+//
+//	recorder := toolservice.NewRecorder()
+//	service, err := toolservice.NewService(nil, recorder.Record(searchTool))
+//	// ... run the agent against service ...
+//	err = toolservice.WriteFixtures(file, recorder.Fixtures())
+//
+// and in the test:
+//
+//	fixtures, err := toolservice.ReadFixtures(file)
+//	service, err := toolservice.NewService(nil, toolservice.Replay(searchTool, fixtures))
+type Recorder struct {
+	mu       sync.Mutex
+	fixtures []Fixture
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Record returns a copy of tool whose calls are recorded. Only validated JSON is recorded: the
+// input has passed the input schema by the time the handler runs, and a result that does not
+// match the output schema fails the call with an internal error instead of being recorded.
+// Calls failing with other errors than a ToolError are not recorded.
+func (r *Recorder) Record(tool Tool) Tool {
+	call := tool.call
+	recorded := tool
+	recorded.handler = func(ctx context.Context, params interface{}) (interface{}, error) {
+		input, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode parameters of tool %q: %w", tool.Name, err)
+		}
+
+		result, err := call(ctx, params)
+		if err != nil {
+			if toolErr := toolError(ctx, tool.Name, err); toolErr != nil {
+				r.add(Fixture{Tool: tool.Name, Input: input, Error: toolErr})
+			}
+			return nil, err
+		}
+
+		output, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode result of tool %q: %w", tool.Name, err)
+		}
+		if err := validateOutput(tool, output); err != nil {
+			return nil, err
+		}
+		r.add(Fixture{Tool: tool.Name, Input: input, Output: output})
+		return result, nil
+	}
+	return recorded
+}
+
+func (r *Recorder) add(fixture Fixture) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixtures = append(r.fixtures, fixture)
+}
+
+// Fixtures returns the calls recorded so far, in the order they finished.
+func (r *Recorder) Fixtures() []Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fixtures := make([]Fixture, len(r.fixtures))
+	copy(fixtures, r.fixtures)
+	return fixtures
+}
+
+// WriteFixtures writes fixtures to w as an indented JSON array, suitable for checking in as
+// test data.
+func WriteFixtures(w io.Writer, fixtures []Fixture) error {
+	if fixtures == nil {
+		fixtures = []Fixture{}
+	}
+	encoded, err := json.MarshalIndent(fixtures, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(encoded, '\n'))
+	return err
+}
+
+// ReadFixtures reads fixtures written by WriteFixtures.
+func ReadFixtures(r io.Reader) ([]Fixture, error) {
+	var fixtures []Fixture
+	if err := json.NewDecoder(r).Decode(&fixtures); err != nil {
+		return nil, fmt.Errorf("failed to decode fixtures: %w", err)
+	}
+	return fixtures, nil
+}
+
+// Replay returns a copy of tool that answers from the fixtures recorded for it instead of
+// calling its handler. A call is answered by the fixtures whose input equals the call's decoded
+// parameters; repeated calls with the same input replay their fixtures in recorded order, and
+// the last one again once all were used. Calls without a fixture fail with an error wrapping
+// ErrNoFixture. Validation, limits and truncation apply to replayed calls as to live ones.
+func Replay(tool Tool, fixtures []Fixture) Tool {
+	byInput := make(map[string][]Fixture)
+	for _, fixture := range fixtures {
+		if fixture.Tool != tool.Name {
+			continue
+		}
+		key, err := compactJSON(fixture.Input)
+		if err != nil {
+			continue
+		}
+		byInput[key] = append(byInput[key], fixture)
+	}
+
+	var mu sync.Mutex
+	used := make(map[string]int)
+	replayed := tool
+	replayed.handler = func(ctx context.Context, params interface{}) (interface{}, error) {
+		input, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode parameters of tool %q: %w", tool.Name, err)
+		}
+		key, err := compactJSON(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode parameters of tool %q: %w", tool.Name, err)
+		}
+		recorded := byInput[key]
+		if len(recorded) == 0 {
+			return nil, fmt.Errorf("%w for tool %q with input %s", ErrNoFixture, tool.Name, input)
+		}
+
+		mu.Lock()
+		index := used[key]
+		if index < len(recorded)-1 {
+			used[key] = index + 1
+		}
+		mu.Unlock()
+
+		fixture := recorded[index]
+		if fixture.Error != nil {
+			toolErr := *fixture.Error
+			return nil, &toolErr
+		}
+		return fixture.Output, nil
+	}
+	return replayed
+}
+
+// compactJSON returns data without insignificant whitespace and with sorted object keys, so
+// fixtures edited by hand still match the encoding of the parameters.
+func compactJSON(data []byte) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
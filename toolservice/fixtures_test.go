@@ -0,0 +1,92 @@
+package toolservice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	live, err := NewTool("search", "", search)
+	assert.NoError(t, err)
+	waiting, err := NewTool("wait", "", wait)
+	assert.NoError(t, err)
+
+	recorder := NewRecorder()
+	service, err := NewService(nil, recorder.Record(live), recorder.Record(waiting))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	recorded, err := service.CallTool(ctx, &CallToolRequest{Name: "search", Input: `{"query": "jobj", "limit": 3}`})
+	assert.NoError(t, err)
+	_, err = service.CallTool(ctx, &CallToolRequest{Name: "search", Input: `{"query": "fail"}`})
+	assert.Error(t, err)
+	_, err = service.CallTool(ctx, &CallToolRequest{Name: "wait", Input: `{"wait": "quota"}`})
+	assert.NoError(t, err)
+
+	fixtures := recorder.Fixtures()
+	assert.Len(t, fixtures, 2, "calls failing with plain errors are not recorded")
+	assert.JSONEq(t, `{"query": "jobj", "limit": 3}`, string(fixtures[0].Input))
+	assert.Equal(t, "quota_exceeded", fixtures[1].Error.Code)
+
+	var file bytes.Buffer
+	assert.NoError(t, WriteFixtures(&file, fixtures))
+	read, err := ReadFixtures(strings.NewReader(file.String()))
+	assert.NoError(t, err)
+
+	// The replayed tools never reach their handlers
+	stub := live
+	stub.handler = func(ctx context.Context, params interface{}) (interface{}, error) {
+		t.Fatal("handler called during replay")
+		return nil, nil
+	}
+	replaying, err := NewService(nil, Replay(stub, read), Replay(waiting, read))
+	assert.NoError(t, err)
+
+	replayed, err := replaying.CallTool(ctx, &CallToolRequest{Name: "search", Input: `{"limit": 3, "query": "jobj"}`})
+	assert.NoError(t, err)
+	assert.JSONEq(t, recorded.Output, replayed.Output)
+
+	replayed, err = replaying.CallTool(ctx, &CallToolRequest{Name: "wait", Input: `{"wait": "quota"}`})
+	assert.NoError(t, err)
+	assert.Equal(t, &ToolError{Code: "quota_exceeded", Message: "daily quota used up"}, replayed.Error)
+
+	_, err = replaying.CallTool(ctx, &CallToolRequest{Name: "search", Input: `{"query": "other"}`})
+	assert.True(t, errors.Is(err, ErrNoFixture))
+}
+
+func TestReplaySequence(t *testing.T) {
+	tool, err := NewTool("search", "", search)
+	assert.NoError(t, err)
+	fixtures := []Fixture{
+		{Tool: "search", Input: []byte(`{"query": "jobj", "limit": 0}`), Output: []byte(`{"titles": ["first"]}`)},
+		{Tool: "other", Input: []byte(`{"query": "jobj", "limit": 0}`), Output: []byte(`{"titles": ["other"]}`)},
+		{Tool: "search", Input: []byte(`{"query": "jobj", "limit": 0}`), Output: []byte(`{"titles": ["second"]}`)},
+	}
+	service, err := NewService(nil, Replay(tool, fixtures))
+	assert.NoError(t, err)
+
+	for _, expected := range []string{"first", "second", "second"} {
+		response, err := service.CallTool(context.Background(), &CallToolRequest{Name: "search", Input: `{"query": "jobj"}`})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"titles": ["`+expected+`"]}`, response.Output)
+	}
+}
+
+func TestRecordRejectsInvalidOutput(t *testing.T) {
+	tool, err := NewTool("report", "", writeReport)
+	assert.NoError(t, err)
+	recorder := NewRecorder()
+	service, err := NewService(nil, recorder.Record(tool))
+	assert.NoError(t, err)
+
+	_, err = service.CallTool(context.Background(), &CallToolRequest{Name: "report", Input: `{"topic": "empty"}`})
+	var serviceErr *Error
+	assert.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, CodeInternal, serviceErr.Code)
+	assert.Empty(t, recorder.Fixtures())
+}
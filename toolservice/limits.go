@@ -0,0 +1,86 @@
+package toolservice
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Limits is the operational policy of a tool. Zero values mean no limit. Limits are advertised in
+// the tool's input schema as the x-max-calls-per-minute and x-max-payload-bytes keywords, so
+// callers can read them alongside the parameters, and enforced by CallTool, which rejects calls
+// beyond them with resource_exhausted.
+type Limits struct {
+	// MaxCallsPerMinute limits the rate of calls to the tool across all callers. Calls may come
+	// in bursts of up to MaxCallsPerMinute, after which they are admitted at the average rate.
+	MaxCallsPerMinute int
+
+	// MaxPayloadBytes limits the size of the JSON input of a call.
+	MaxPayloadBytes int
+}
+
+// extensions returns the schema keywords advertising the limits.
+func (l Limits) extensions() map[string]interface{} {
+	extensions := make(map[string]interface{}, 2)
+	if l.MaxCallsPerMinute > 0 {
+		extensions["x-max-calls-per-minute"] = l.MaxCallsPerMinute
+	}
+	if l.MaxPayloadBytes > 0 {
+		extensions["x-max-payload-bytes"] = l.MaxPayloadBytes
+	}
+	return extensions
+}
+
+func (l Limits) check(name string) error {
+	if l.MaxCallsPerMinute < 0 || l.MaxPayloadBytes < 0 {
+		return fmt.Errorf("tool %q: limits must not be negative", name)
+	}
+	return nil
+}
+
+// rateLimiter is a token bucket holding up to capacity calls, refilled at capacity per minute.
+type rateLimiter struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	updated  time.Time
+}
+
+func newRateLimiter(callsPerMinute int) *rateLimiter {
+	return &rateLimiter{
+		capacity: float64(callsPerMinute),
+		tokens:   float64(callsPerMinute),
+		updated:  time.Now(),
+	}
+}
+
+// allow takes a token if one is available at now.
+func (l *rateLimiter) allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elapsed := now.Sub(l.updated)
+	if elapsed > 0 {
+		l.tokens = min(l.capacity, l.tokens+elapsed.Minutes()*l.capacity)
+		l.updated = now
+	}
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// enforceLimits rejects a call to tool whose input exceeds MaxPayloadBytes or that exceeds
+// MaxCallsPerMinute.
+func (s *Service) enforceLimits(tool Tool, input string) error {
+	if tool.Limits.MaxPayloadBytes > 0 && len(input) > tool.Limits.MaxPayloadBytes {
+		return &Error{Code: CodeResourceExhausted, Err: fmt.Errorf("input of tool %q is %d bytes, limit is %d",
+			tool.Name, len(input), tool.Limits.MaxPayloadBytes)}
+	}
+	if limiter, ok := s.limiters[tool.Name]; ok && !limiter.allow(time.Now()) {
+		return &Error{Code: CodeResourceExhausted, Err: fmt.Errorf("tool %q is limited to %d calls per minute",
+			tool.Name, tool.Limits.MaxCallsPerMinute)}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package toolservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolLimits(t *testing.T) {
+	tool, err := NewTool("search", "", search)
+	assert.NoError(t, err)
+	tool.Limits = Limits{MaxCallsPerMinute: 2, MaxPayloadBytes: 32}
+
+	service, err := NewService(nil, tool)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	tools, err := service.ListTools(ctx)
+	assert.NoError(t, err)
+	var input map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(tools.Tools[0].InputSchema), &input))
+	assert.Equal(t, float64(2), input["x-max-calls-per-minute"])
+	assert.Equal(t, float64(32), input["x-max-payload-bytes"])
+	assert.NotContains(t, tool.Schemas.InputProperties, "x-max-payload-bytes")
+
+	var serviceErr *Error
+	_, err = service.CallTool(ctx, &CallToolRequest{Name: "search", Input: `{"query": "a very long query that exceeds the limit"}`})
+	assert.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, CodeResourceExhausted, serviceErr.Code)
+
+	for i := 0; i < 2; i++ {
+		_, err = service.CallTool(ctx, &CallToolRequest{Name: "search", Input: `{"query": "jobj"}`})
+		assert.NoError(t, err)
+	}
+	_, err = service.CallTool(ctx, &CallToolRequest{Name: "search", Input: `{"query": "jobj"}`})
+	assert.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, CodeResourceExhausted, serviceErr.Code)
+
+	tool.Limits = Limits{MaxPayloadBytes: -1}
+	_, err = NewService(nil, tool)
+	assert.Error(t, err)
+}
+
+func TestRateLimiterRefills(t *testing.T) {
+	limiter := newRateLimiter(60)
+	start := limiter.updated
+	for i := 0; i < 60; i++ {
+		assert.True(t, limiter.allow(start))
+	}
+	assert.False(t, limiter.allow(start))
+	assert.True(t, limiter.allow(start.Add(time.Second)))
+	assert.False(t, limiter.allow(start.Add(time.Second)))
+	for i := 0; i < 60; i++ {
+		assert.True(t, limiter.allow(start.Add(time.Hour)))
+	}
+	assert.False(t, limiter.allow(start.Add(time.Hour)))
+}
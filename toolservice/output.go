@@ -0,0 +1,87 @@
+package toolservice
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mhpenta/jobj"
+)
+
+// validateOutput checks the encoded result of a tool against its output schema. A Go value is
+// encoded with null for its nil pointers, slices and maps; those are accepted for optional fields,
+// which the schema leaves out of "required", and reported for required ones. A null result is
+// accepted for non-struct results.
+func validateOutput(tool Tool, output []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(output, &value); err != nil {
+		return fmt.Errorf("failed to decode result of tool %q: %w", tool.Name, err)
+	}
+
+	schema := tool.Schemas.Output
+	if schema.RootField != nil {
+		if value == nil {
+			// A nil slice or map result
+			return nil
+		}
+		pruneOptionalNulls(schema.RootField, value)
+	} else if object, ok := value.(map[string]interface{}); ok {
+		pruneObjectNulls(schema.Fields, object)
+	}
+
+	pruned, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode result of tool %q: %w", tool.Name, err)
+	}
+	problems, err := schema.ValidateJSON(pruned)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return &Error{Code: CodeInternal, Err: fmt.Errorf("tool %q returned a result that does not match its output schema: %w",
+			tool.Name, jobj.ValidationErrors(problems))}
+	}
+	return nil
+}
+
+// pruneObjectNulls removes the null members of object that belong to optional fields, and
+// prunes the values of the other fields.
+func pruneObjectNulls(fields []*jobj.Field, object map[string]interface{}) {
+	for _, field := range fields {
+		value, ok := object[field.ValueName]
+		if !ok {
+			continue
+		}
+		if value == nil && !field.ValueRequired {
+			delete(object, field.ValueName)
+			continue
+		}
+		pruneOptionalNulls(field, value)
+	}
+}
+
+// pruneOptionalNulls prunes the objects nested in value, which field describes.
+func pruneOptionalNulls(field *jobj.Field, value interface{}) {
+	switch value := value.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if field.ArrayItemField != nil {
+				pruneOptionalNulls(field.ArrayItemField, item)
+			} else if object, ok := item.(map[string]interface{}); ok && field.SubFields != nil {
+				pruneObjectNulls(field.SubFields, object)
+			}
+		}
+	case map[string]interface{}:
+		if field.AdditionalProperties {
+			if field.AdditionalPropertiesField == nil || field.AdditionalPropertiesField.SubFields == nil {
+				return
+			}
+			for _, item := range value {
+				if object, ok := item.(map[string]interface{}); ok {
+					pruneObjectNulls(field.AdditionalPropertiesField.SubFields, object)
+				}
+			}
+			return
+		}
+		pruneObjectNulls(field.SubFields, value)
+	}
+}
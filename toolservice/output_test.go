@@ -0,0 +1,64 @@
+package toolservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reportParams struct {
+	Topic string `json:"topic" desc:"Report topic" required:"true"`
+}
+
+type report struct {
+	Sections []string `json:"sections" desc:"Report sections" required:"true"`
+	Summary  *string  `json:"summary" desc:"Optional summary"`
+	Tags     []string `json:"tags" desc:"Optional tags"`
+}
+
+func writeReport(ctx context.Context, params reportParams) (report, error) {
+	if params.Topic == "empty" {
+		return report{}, nil
+	}
+	return report{Sections: []string{"Introduction"}}, nil
+}
+
+func TestCallToolValidateOutput(t *testing.T) {
+	tool, err := NewTool("report", "", writeReport)
+	assert.NoError(t, err)
+	unvalidated := tool
+	unvalidated.Name = "unvalidated_report"
+	tool.ValidateOutput = true
+
+	service, err := NewService(nil, tool, unvalidated)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	// Nil optional fields are encoded as null and accepted
+	response, err := service.CallTool(ctx, &CallToolRequest{Name: "report", Input: `{"topic": "jobj"}`})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sections": ["Introduction"], "summary": null, "tags": null}`, response.Output)
+
+	// A nil required slice breaks the output schema
+	_, err = service.CallTool(ctx, &CallToolRequest{Name: "report", Input: `{"topic": "empty"}`})
+	var serviceErr *Error
+	assert.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, CodeInternal, serviceErr.Code)
+	assert.Contains(t, err.Error(), "sections")
+
+	// Results are not validated by default
+	_, err = service.CallTool(ctx, &CallToolRequest{Name: "unvalidated_report", Input: `{"topic": "empty"}`})
+	assert.NoError(t, err)
+}
+
+func TestValidateOutputNullRoot(t *testing.T) {
+	tool, err := NewTool("list", "", func(ctx context.Context, params reportParams) ([]report, error) {
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, validateOutput(tool, []byte(`null`)))
+	assert.NoError(t, validateOutput(tool, []byte(`[{"sections": [], "summary": null}]`)))
+	assert.Error(t, validateOutput(tool, []byte(`[{"summary": "no sections"}]`)))
+}
@@ -0,0 +1,457 @@
+// Package toolservice serves jobj-backed tools and schemas over the network, so orchestrators
+// written in other languages can discover and invoke them.
+//
+// The service is defined in toolservice.proto as jobj.toolservice.v1.ToolService with three
+// methods: ListTools, GetSchema and CallTool. Service implements it with the Connect protocol's
+// unary JSON encoding on top of net/http, so it needs no generated code and any Connect client,
+// or a plain HTTP POST, can call it. gRPC clients, which require protobuf over HTTP/2, are not
+// supported.
+//
+// Example:
+//
+//	tool, err := toolservice.NewTool("search", "Search the knowledge base", searchTool.Search)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	service, err := toolservice.NewService(registry, tool)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	mux := http.NewServeMux()
+//	mux.Handle(toolservice.ServicePath, service)
+package toolservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mhpenta/jobj"
+	"github.com/mhpenta/jobj/funcschema"
+)
+
+// ServicePath is the path prefix of the service's methods, e.g. ServicePath + "ListTools".
+const ServicePath = "/jobj.toolservice.v1.ToolService/"
+
+// maxRequestBytes limits the size of request bodies.
+const maxRequestBytes = 4 << 20
+
+// Tool is a function exposed by CallTool: a funcschema.Tool with the settings of the service.
+// Create one with NewTool, or from a funcschema.Tool, e.g. one returned by
+// funcschema.ToolsFromStruct, as Tool{Tool: tool}.
+//
+// Version is optional. When set, callers may send a schema_version property with their input:
+// input for an older version is upgraded by Migrations before validation, and input for a version
+// no migration leads from is rejected with a VersionError.
+//
+// ValidateOutput checks every result against the tool's output schema before it is returned, so
+// a handler breaking its contract, e.g. leaving a required slice nil, fails the call with an
+// internal error instead of handing the model a result its tool definition does not describe.
+//
+// Truncation trims results over a size limit before they are returned; see Truncation.
+//
+// Timeout, when set, bounds each call to the tool. A handler that runs past it, or whose call is
+// cancelled, is reported to the model as a retryable ToolError instead of a failed call; handlers
+// must watch their context for this to take effect.
+type Tool struct {
+	funcschema.Tool
+	Version        string
+	Migrations     []Migration
+	Limits         Limits
+	ValidateOutput bool
+	Truncation     Truncation
+	Timeout        time.Duration
+
+	handler func(ctx context.Context, params interface{}) (interface{}, error) // Replaces the handler; set by Record and Replay
+}
+
+// NewTool wraps a handler with the signature func(context.Context, T) (R, error) as a Tool, using
+// funcschema.NewTool with opts.
+func NewTool[T any, R any](name string, description string, function func(context.Context, T) (R, error), opts ...funcschema.Option) (Tool, error) {
+	tool, err := funcschema.NewTool(name, description, function, opts...)
+	if err != nil {
+		return Tool{}, err
+	}
+	return Tool{Tool: tool}, nil
+}
+
+// call calls the tool's handler with params, or the replacement installed by Record or Replay.
+func (t Tool) call(ctx context.Context, params interface{}) (interface{}, error) {
+	if t.handler != nil {
+		return t.handler(ctx, params)
+	}
+	return t.Call(ctx, params)
+}
+
+// Service implements ToolService for a fixed set of tools, collected in a funcschema.Registry,
+// and, for GetSchema, a jobj.Registry. It is an http.Handler; mount it at ServicePath.
+type Service struct {
+	registry *jobj.Registry
+	toolset  *funcschema.Registry
+	tools    map[string]Tool // The service settings of the tools in toolset
+	limiters map[string]*rateLimiter
+}
+
+// NewService returns a Service for the given tools. registry serves GetSchema and may be nil,
+// in which case GetSchema always reports not found. Tool names must be unique.
+func NewService(registry *jobj.Registry, tools ...Tool) (*Service, error) {
+	s := &Service{
+		registry: registry,
+		toolset:  funcschema.NewRegistry(),
+		tools:    make(map[string]Tool, len(tools)),
+		limiters: make(map[string]*rateLimiter),
+	}
+	for _, tool := range tools {
+		if err := checkMigrations(tool); err != nil {
+			return nil, err
+		}
+		if err := tool.Limits.check(tool.Name); err != nil {
+			return nil, err
+		}
+		if err := tool.Truncation.check(tool.Name); err != nil {
+			return nil, err
+		}
+		if tool.Timeout < 0 {
+			return nil, fmt.Errorf("tool %q: timeout must not be negative", tool.Name)
+		}
+		if tool.Limits.MaxCallsPerMinute > 0 {
+			s.limiters[tool.Name] = newRateLimiter(tool.Limits.MaxCallsPerMinute)
+		}
+		if err := s.toolset.Register(tool.Tool); err != nil {
+			return nil, err
+		}
+		s.tools[tool.Name] = tool
+	}
+	return s, nil
+}
+
+// ToolInfo describes a tool in a ListToolsResponse.
+type ToolInfo struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	Version      string `json:"version,omitempty"`
+	InputSchema  string `json:"inputSchema"`
+	OutputSchema string `json:"outputSchema,omitempty"`
+}
+
+// ListToolsResponse is the result of ListTools.
+type ListToolsResponse struct {
+	Tools []ToolInfo `json:"tools"`
+}
+
+// GetSchemaRequest is the input of GetSchema.
+type GetSchemaRequest struct {
+	Name    string `json:"name"`
+	Profile string `json:"profile,omitempty"`
+}
+
+// GetSchemaResponse is the result of GetSchema.
+type GetSchemaResponse struct {
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	Schema      string `json:"schema"`
+}
+
+// CallToolRequest is the input of CallTool. Input holds the tool's parameters as a JSON object.
+// DryRun checks the input without calling the tool; see CallTool.
+type CallToolRequest struct {
+	Name   string `json:"name"`
+	Input  string `json:"input"`
+	DryRun bool   `json:"dryRun,omitempty"`
+}
+
+// CallToolResponse is the result of CallTool. Output holds the tool's result encoded as JSON.
+// Truncated reports that Output was trimmed to the tool's Truncation. A tool that failed in a way
+// the model can act on, e.g. by timing out, leaves Output empty and reports the failure in Error.
+// For dry runs Output is empty and Parameters holds the parameters the tool would have received,
+// encoded as JSON.
+type CallToolResponse struct {
+	Output     string     `json:"output,omitempty"`
+	Truncated  bool       `json:"truncated,omitempty"`
+	Error      *ToolError `json:"error,omitempty"`
+	Parameters string     `json:"parameters,omitempty"`
+	DryRun     bool       `json:"dryRun,omitempty"`
+}
+
+// ListTools returns every tool, sorted by name. Tool limits are included in the input schema, and
+// the truncation marker of tools with a Truncation in the output schema.
+func (s *Service) ListTools(ctx context.Context) (*ListToolsResponse, error) {
+	registered := s.toolset.List()
+	response := &ListToolsResponse{Tools: make([]ToolInfo, 0, len(registered))}
+	for _, listed := range registered {
+		tool := s.tools[listed.Name]
+		inputProperties := tool.Schemas.InputProperties
+		if extensions := tool.Limits.extensions(); len(extensions) > 0 {
+			// The properties are shared with funcschema's cache, so extend a copy
+			inputProperties = make(map[string]interface{}, len(tool.Schemas.InputProperties)+len(extensions))
+			for key, value := range tool.Schemas.InputProperties {
+				inputProperties[key] = value
+			}
+			for key, value := range extensions {
+				inputProperties[key] = value
+			}
+		}
+		input, err := json.Marshal(inputProperties)
+		if err != nil {
+			return nil, err
+		}
+		output, err := json.Marshal(tool.Truncation.outputProperties(tool.Schemas.OutputProperties))
+		if err != nil {
+			return nil, err
+		}
+		response.Tools = append(response.Tools, ToolInfo{
+			Name:         tool.Name,
+			Description:  tool.Description,
+			Version:      tool.Version,
+			InputSchema:  string(input),
+			OutputSchema: string(output),
+		})
+	}
+	return response, nil
+}
+
+// GetSchema returns a schema from the registry, built for request.Profile when one is given.
+func (s *Service) GetSchema(ctx context.Context, request *GetSchemaRequest) (*GetSchemaResponse, error) {
+	if request.Name == "" {
+		return nil, invalidArgument(fmt.Errorf("schema name must not be empty"))
+	}
+	if s.registry == nil {
+		return nil, notFound(fmt.Errorf("schema %q is not registered", request.Name))
+	}
+
+	var schema *jobj.Schema
+	var err error
+	if request.Profile != "" {
+		schema, err = s.registry.BuildProfile(request.Profile, request.Name)
+	} else {
+		schema, err = s.registry.Build(request.Name)
+	}
+	if err != nil {
+		if !registered(s.registry, request.Name) {
+			return nil, notFound(err)
+		}
+		return nil, err
+	}
+
+	fingerprint, err := schema.Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	return &GetSchemaResponse{
+		Name:        schema.Name,
+		Version:     schema.Version,
+		Fingerprint: fingerprint,
+		Schema:      schema.GetSchemaString(),
+	}, nil
+}
+
+// CallTool decodes request.Input with funcschema.Tool.Decode, which repairs it, fills in defaults
+// and validates it against the tool's input schema, and calls the tool. Invalid input is reported
+// as invalid_argument with every validation problem in the message. Input with a
+// schema_version is migrated to the tool's current version first; an unsupported version is
+// reported as failed_precondition. Calls beyond the tool's Limits are reported as
+// resource_exhausted. For tools with ValidateOutput set, a result that does not match the output
+// schema is reported as internal. Results over the tool's Truncation are trimmed. Timeouts,
+// cancellations and ToolErrors returned by the tool are reported in CallToolResponse.Error.
+//
+// With request.DryRun set, CallTool stops short of calling the tool: it validates, migrates and
+// decodes the input as usual and returns the decoded parameters, with omitted fields at their
+// defaults, so agents can preview a plan and prompt changes can be tested against production
+// tool definitions without side effects. Dry runs count towards the tool's rate limit.
+func (s *Service) CallTool(ctx context.Context, request *CallToolRequest) (*CallToolResponse, error) {
+	tool, ok := s.tools[request.Name]
+	if !ok {
+		return nil, notFound(fmt.Errorf("tool %q is not defined", request.Name))
+	}
+	if err := s.enforceLimits(tool, request.Input); err != nil {
+		return nil, err
+	}
+
+	input, err := negotiateVersion(tool, []byte(request.Input))
+	if err != nil {
+		return nil, err
+	}
+	params, err := tool.Decode(input)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	if request.DryRun {
+		parameters, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode parameters of tool %q: %w", request.Name, err)
+		}
+		return &CallToolResponse{Parameters: string(parameters), DryRun: true}, nil
+	}
+
+	if tool.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, tool.Timeout)
+		defer cancel()
+	}
+	result, err := tool.call(ctx, params)
+	if err != nil {
+		if toolErr := toolError(ctx, request.Name, err); toolErr != nil {
+			return &CallToolResponse{Error: toolErr}, nil
+		}
+		return nil, err
+	}
+	output, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result of tool %q: %w", request.Name, err)
+	}
+	if tool.ValidateOutput {
+		if err := validateOutput(tool, output); err != nil {
+			return nil, err
+		}
+	}
+	truncated := false
+	if tool.Truncation.enabled() {
+		output, truncated, err = truncateOutput(tool, output)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &CallToolResponse{Output: string(output), Truncated: truncated}, nil
+}
+
+// ServeHTTP implements the Connect unary protocol with the JSON codec: every method is a POST to
+// ServicePath plus the method name, with the request and response messages as JSON bodies.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method, ok := strings.CutPrefix(r.URL.Path, ServicePath)
+	if !ok {
+		writeError(w, notFound(fmt.Errorf("unknown path %s", r.URL.Path)))
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "" && !strings.HasPrefix(contentType, "application/json") {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// One byte past the limit is read to tell a body of exactly maxRequestBytes from a larger one
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes+1))
+	if err != nil {
+		writeError(w, invalidArgument(err))
+		return
+	}
+	if len(body) > maxRequestBytes {
+		writeError(w, &Error{Code: CodeResourceExhausted, Err: fmt.Errorf("request body exceeds %d bytes", maxRequestBytes)})
+		return
+	}
+
+	var response interface{}
+	switch method {
+	case "ListTools":
+		response, err = s.ListTools(r.Context())
+	case "GetSchema":
+		var request GetSchemaRequest
+		if err = decodeRequest(body, &request); err == nil {
+			response, err = s.GetSchema(r.Context(), &request)
+		}
+	case "CallTool":
+		var request CallToolRequest
+		if err = decodeRequest(body, &request); err == nil {
+			response, err = s.CallTool(r.Context(), &request)
+		}
+	default:
+		err = &Error{Code: CodeUnimplemented, Err: fmt.Errorf("unknown method %q", method)}
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// registered reports whether registry knows name, to tell missing schemas from failing ones.
+func registered(registry *jobj.Registry, name string) bool {
+	for _, registeredName := range registry.Names() {
+		if registeredName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeRequest(body []byte, request interface{}) error {
+	if len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, request); err != nil {
+		return invalidArgument(fmt.Errorf("failed to parse request: %w", err))
+	}
+	return nil
+}
+
+// Error codes used by Service, as defined by the Connect protocol.
+const (
+	CodeInvalidArgument    = "invalid_argument"
+	CodeNotFound           = "not_found"
+	CodeFailedPrecondition = "failed_precondition"
+	CodeResourceExhausted  = "resource_exhausted"
+	CodeUnimplemented      = "unimplemented"
+	CodeInternal           = "internal"
+)
+
+// Error is an error with a Connect error code. Errors returned by tools without a code are
+// reported as internal.
+type Error struct {
+	Code string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Code, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func invalidArgument(err error) error {
+	return &Error{Code: CodeInvalidArgument, Err: err}
+}
+
+func notFound(err error) error {
+	return &Error{Code: CodeNotFound, Err: err}
+}
+
+// httpStatus maps Connect error codes to HTTP status codes.
+var httpStatus = map[string]int{
+	CodeInvalidArgument:    http.StatusBadRequest,
+	CodeNotFound:           http.StatusNotFound,
+	CodeFailedPrecondition: http.StatusBadRequest,
+	CodeResourceExhausted:  http.StatusTooManyRequests,
+	CodeUnimplemented:      http.StatusNotImplemented,
+	CodeInternal:           http.StatusInternalServerError,
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) {
+		serviceErr = &Error{Code: CodeInternal, Err: err}
+	}
+	status, ok := httpStatus[serviceErr.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{Code: serviceErr.Code, Message: serviceErr.Err.Error()})
+}
@@ -0,0 +1,207 @@
+package toolservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mhpenta/jobj"
+	"github.com/mhpenta/jobj/funcschema"
+	"github.com/stretchr/testify/assert"
+)
+
+type searchParams struct {
+	Query string `json:"query" desc:"Search query" required:"true"`
+	Limit int    `json:"limit" desc:"Maximum number of results"`
+}
+
+type searchResult struct {
+	Titles []string `json:"titles" desc:"Matching titles"`
+}
+
+func search(ctx context.Context, params searchParams) (searchResult, error) {
+	if params.Query == "fail" {
+		return searchResult{}, fmt.Errorf("backend unavailable")
+	}
+	return searchResult{Titles: []string{"Result for " + params.Query}}, nil
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	tool, err := NewTool("search", "Search the knowledge base", search)
+	assert.NoError(t, err)
+
+	registry := jobj.NewRegistry()
+	assert.NoError(t, registry.RegisterSchema(&jobj.Schema{
+		Name:    "Headlines",
+		Version: "1.0.0",
+		Fields:  []*jobj.Field{jobj.Text("headline").Required()},
+	}))
+	assert.NoError(t, registry.Override("staging", "Headlines", []byte(`{"description": "Staging"}`)))
+
+	service, err := NewService(registry, tool)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.Handle(ServicePath, service)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func post(t *testing.T, server *httptest.Server, method string, body string) (int, map[string]interface{}) {
+	response, err := http.Post(server.URL+ServicePath+method, "application/json", strings.NewReader(body))
+	assert.NoError(t, err)
+	defer response.Body.Close()
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&decoded))
+	return response.StatusCode, decoded
+}
+
+func TestListTools(t *testing.T) {
+	server := newTestServer(t)
+
+	status, body := post(t, server, "ListTools", `{}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	tools := body["tools"].([]interface{})
+	assert.Len(t, tools, 1)
+	tool := tools[0].(map[string]interface{})
+	assert.Equal(t, "search", tool["name"])
+	assert.Equal(t, "Search the knowledge base", tool["description"])
+	assert.Contains(t, tool["inputSchema"], `"query"`)
+	assert.Contains(t, tool["outputSchema"], `"titles"`)
+}
+
+func TestGetSchema(t *testing.T) {
+	server := newTestServer(t)
+
+	status, body := post(t, server, "GetSchema", `{"name": "Headlines"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "1.0.0", body["version"])
+	assert.Len(t, body["fingerprint"], 64)
+	assert.Contains(t, body["schema"], `"headline"`)
+
+	status, body = post(t, server, "GetSchema", `{"name": "Headlines", "profile": "staging"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body["schema"], `"Staging"`)
+
+	status, body = post(t, server, "GetSchema", `{"name": "Missing"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, CodeNotFound, body["code"])
+}
+
+func TestCallTool(t *testing.T) {
+	server := newTestServer(t)
+
+	status, body := post(t, server, "CallTool", `{"name": "search", "input": "{\"query\": \"jobj\"}"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"titles": ["Result for jobj"]}`, body["output"].(string))
+
+	// Input is repaired as by funcschema.Tool.Execute
+	status, body = post(t, server, "CallTool", `{"name": "search", "input": "{\"query\": \"jobj\",}"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"titles": ["Result for jobj"]}`, body["output"].(string))
+
+	status, body = post(t, server, "CallTool", `{"name": "search", "input": "{\"limit\": \"ten\"}"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, CodeInvalidArgument, body["code"])
+	assert.Contains(t, body["message"], "/query")
+	assert.Contains(t, body["message"], "/limit")
+
+	status, body = post(t, server, "CallTool", `{"name": "search", "input": "{\"query\": \"fail\"}"}`)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, CodeInternal, body["code"])
+	assert.Contains(t, body["message"], "backend unavailable")
+
+	status, body = post(t, server, "CallTool", `{"name": "missing", "input": "{}"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, CodeNotFound, body["code"])
+}
+
+func TestServeHTTPErrors(t *testing.T) {
+	server := newTestServer(t)
+
+	status, body := post(t, server, "DeleteTool", `{}`)
+	assert.Equal(t, http.StatusNotImplemented, status)
+	assert.Equal(t, CodeUnimplemented, body["code"])
+
+	oversized := `{"name": "search", "input": "` + strings.Repeat("x", maxRequestBytes) + `"}`
+	status, body = post(t, server, "CallTool", oversized)
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, CodeResourceExhausted, body["code"])
+
+	status, body = post(t, server, "GetSchema", `{`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, CodeInvalidArgument, body["code"])
+
+	response, err := http.Get(server.URL + ServicePath + "ListTools")
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+}
+
+func TestNewServiceRejectsDuplicateTools(t *testing.T) {
+	tool, err := NewTool("search", "", search)
+	assert.NoError(t, err)
+
+	_, err = NewService(nil, tool, tool)
+	assert.Error(t, err)
+
+	_, err = NewService(nil, Tool{Tool: funcschema.Tool{Name: "bare"}})
+	assert.Error(t, err)
+
+	_, err = NewTool("", "", search)
+	assert.Error(t, err)
+}
+
+func TestCallToolDryRun(t *testing.T) {
+	calls := 0
+	tool, err := NewTool("search", "", func(ctx context.Context, params searchParams) (searchResult, error) {
+		calls++
+		return search(ctx, params)
+	})
+	assert.NoError(t, err)
+	service, err := NewService(nil, tool)
+	assert.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.Handle(ServicePath, service)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	status, body := post(t, server, "CallTool", `{"name": "search", "input": "{\"query\": \"jobj\"}", "dryRun": true}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, body["dryRun"])
+	assert.NotContains(t, body, "output")
+	assert.JSONEq(t, `{"query": "jobj", "limit": 0}`, body["parameters"].(string))
+	assert.Equal(t, 0, calls)
+
+	status, body = post(t, server, "CallTool", `{"name": "search", "input": "{\"limit\": 3}", "dryRun": true}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, CodeInvalidArgument, body["code"])
+	assert.Equal(t, 0, calls)
+
+	status, _ = post(t, server, "CallTool", `{"name": "search", "input": "{\"query\": \"jobj\"}"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1, calls)
+}
+
+func TestCallToolSnakeCaseNames(t *testing.T) {
+	type lookupParams struct {
+		UserID string `desc:"User to look up" required:"true"`
+	}
+	tool, err := NewTool("lookup", "", func(ctx context.Context, params lookupParams) (string, error) {
+		return "user " + params.UserID, nil
+	}, funcschema.WithSnakeCaseNames())
+	assert.NoError(t, err)
+	service, err := NewService(nil, tool)
+	assert.NoError(t, err)
+
+	response, err := service.CallTool(context.Background(), &CallToolRequest{Name: "lookup", Input: `{"user_id": "42"}`})
+	assert.NoError(t, err)
+	assert.Equal(t, `"user 42"`, response.Output)
+}
@@ -0,0 +1,52 @@
+package toolservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Tool error codes reported in ToolError.Code.
+const (
+	ToolErrorTimeout  = "timeout"
+	ToolErrorCanceled = "canceled"
+)
+
+// ToolError is a failure of a tool reported to the model as part of the CallTool response,
+// rather than as a failed call, so an agent can read it like any other tool result and decide
+// whether to try again. CallTool reports a handler that runs past the tool's Timeout with code
+// timeout and a cancelled call with code canceled, both retryable. Handlers may return a
+// ToolError themselves, possibly wrapped, to report other failures the model can act on.
+type ToolError struct {
+	Code      string `json:"code" desc:"Error code, e.g. timeout or canceled" required:"true"`
+	Message   string `json:"message" desc:"What went wrong" required:"true"`
+	Retryable bool   `json:"retryable" desc:"Whether calling the tool again with the same input may succeed" required:"true"`
+}
+
+func (e *ToolError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// toolError returns the ToolError reporting err, the failure of a call to the tool named name
+// with ctx, or nil if err is not reported to the model.
+func toolError(ctx context.Context, name string, err error) *ToolError {
+	var reported *ToolError
+	if errors.As(err, &reported) {
+		return reported
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &ToolError{
+			Code:      ToolErrorTimeout,
+			Message:   fmt.Sprintf("tool %q did not finish in time", name),
+			Retryable: true,
+		}
+	case errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled):
+		return &ToolError{
+			Code:      ToolErrorCanceled,
+			Message:   fmt.Sprintf("call to tool %q was cancelled", name),
+			Retryable: true,
+		}
+	}
+	return nil
+}
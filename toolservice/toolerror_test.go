@@ -0,0 +1,73 @@
+package toolservice
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type waitParams struct {
+	Wait string `json:"wait" desc:"How long to wait" required:"true"`
+}
+
+type waitResult struct {
+	Waited bool `json:"waited" desc:"Whether the wait finished"`
+}
+
+func wait(ctx context.Context, params waitParams) (waitResult, error) {
+	if params.Wait == "quota" {
+		return waitResult{}, fmt.Errorf("backend: %w", &ToolError{Code: "quota_exceeded", Message: "daily quota used up"})
+	}
+	duration, err := time.ParseDuration(params.Wait)
+	if err != nil {
+		return waitResult{}, err
+	}
+	select {
+	case <-time.After(duration):
+		return waitResult{Waited: true}, nil
+	case <-ctx.Done():
+		return waitResult{}, ctx.Err()
+	}
+}
+
+func TestCallToolTimeout(t *testing.T) {
+	tool, err := NewTool("wait", "", wait)
+	assert.NoError(t, err)
+	tool.Timeout = 20 * time.Millisecond
+
+	service, err := NewService(nil, tool)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	response, err := service.CallTool(ctx, &CallToolRequest{Name: "wait", Input: `{"wait": "1ms"}`})
+	assert.NoError(t, err)
+	assert.Nil(t, response.Error)
+	assert.JSONEq(t, `{"waited": true}`, response.Output)
+
+	response, err = service.CallTool(ctx, &CallToolRequest{Name: "wait", Input: `{"wait": "1h"}`})
+	assert.NoError(t, err)
+	assert.Empty(t, response.Output)
+	assert.Equal(t, &ToolError{Code: ToolErrorTimeout, Message: `tool "wait" did not finish in time`, Retryable: true}, response.Error)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	response, err = service.CallTool(cancelled, &CallToolRequest{Name: "wait", Input: `{"wait": "1h"}`})
+	assert.NoError(t, err)
+	assert.Equal(t, ToolErrorCanceled, response.Error.Code)
+	assert.True(t, response.Error.Retryable)
+
+	response, err = service.CallTool(ctx, &CallToolRequest{Name: "wait", Input: `{"wait": "quota"}`})
+	assert.NoError(t, err)
+	assert.Equal(t, &ToolError{Code: "quota_exceeded", Message: "daily quota used up"}, response.Error)
+
+	// Other errors still fail the call
+	_, err = service.CallTool(ctx, &CallToolRequest{Name: "wait", Input: `{"wait": "soon"}`})
+	assert.Error(t, err)
+
+	tool.Timeout = -time.Second
+	_, err = NewService(nil, tool)
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+package toolservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mhpenta/jobj"
+)
+
+// TruncatedKey is the property CallTool adds to an object result it has truncated.
+const TruncatedKey = "_truncated"
+
+// Truncation limits the size of a tool's results, so one oversized result cannot crowd the rest
+// of the conversation out of the model's context. Zero values mean no limit. A result over either
+// limit is trimmed by CallTool, guided by the tool's output schema:
+//
+//  1. Optional arrays are dropped, largest first, until the result fits.
+//  2. The remaining arrays, largest first, lose their trailing items until the result fits.
+//
+// Object results that were trimmed carry TruncatedKey set to true, which ListTools advertises in
+// the output schema, and CallToolResponse.Truncated is set for every trimmed result. A result
+// whose size lies outside its arrays, e.g. in one long string, may still exceed the limits after
+// trimming; it is returned as trimmed rather than failing the call.
+type Truncation struct {
+	// MaxBytes limits the size of the JSON result.
+	MaxBytes int
+
+	// MaxTokens limits the estimated number of tokens in the JSON result.
+	MaxTokens int
+
+	// EstimateTokens counts the tokens in a JSON result for MaxTokens. The default estimates one
+	// token per four bytes, which suits English text and JSON punctuation; set it to match the
+	// model's tokenizer when the estimate matters.
+	EstimateTokens func(output []byte) int
+}
+
+func (t Truncation) enabled() bool {
+	return t.MaxBytes > 0 || t.MaxTokens > 0
+}
+
+func (t Truncation) check(name string) error {
+	if t.MaxBytes < 0 || t.MaxTokens < 0 {
+		return fmt.Errorf("tool %q: truncation limits must not be negative", name)
+	}
+	return nil
+}
+
+func (t Truncation) tokens(output []byte) int {
+	if t.EstimateTokens != nil {
+		return t.EstimateTokens(output)
+	}
+	return (len(output) + 3) / 4
+}
+
+// excess returns how many bytes and tokens output must lose to fit. Both are 0 if it fits.
+func (t Truncation) excess(output []byte) (bytes, tokens int) {
+	if t.MaxBytes > 0 && len(output) > t.MaxBytes {
+		bytes = len(output) - t.MaxBytes
+	}
+	if t.MaxTokens > 0 {
+		if estimate := t.tokens(output); estimate > t.MaxTokens {
+			tokens = estimate - t.MaxTokens
+		}
+	}
+	return bytes, tokens
+}
+
+func (t Truncation) fits(output []byte) bool {
+	bytes, tokens := t.excess(output)
+	return bytes == 0 && tokens == 0
+}
+
+// outputProperties returns the output schema advertised for a tool, with the truncation marker
+// added to object results.
+func (t Truncation) outputProperties(properties map[string]interface{}) map[string]interface{} {
+	fields, ok := properties["properties"].(map[string]interface{})
+	if !t.enabled() || !ok {
+		return properties
+	}
+	// The properties are shared with funcschema's cache, so extend a copy
+	extended := make(map[string]interface{}, len(properties))
+	for key, value := range properties {
+		extended[key] = value
+	}
+	extendedFields := make(map[string]interface{}, len(fields)+1)
+	for key, value := range fields {
+		extendedFields[key] = value
+	}
+	extendedFields[TruncatedKey] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Set when the result was trimmed to fit the tool's size limits",
+	}
+	extended["properties"] = extendedFields
+	return extended
+}
+
+// sizedValue is a value of a result that trimming may remove or shorten. set replaces it in its
+// parent; a nil value removes it.
+type sizedValue struct {
+	value interface{}
+	size  int
+	set   func(value interface{})
+}
+
+// truncateOutput trims output to the tool's Truncation, reporting whether it was trimmed.
+func truncateOutput(tool Tool, output []byte) ([]byte, bool, error) {
+	policy := tool.Truncation
+	if policy.fits(output) {
+		return output, false, nil
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, false, fmt.Errorf("failed to decode result of tool %q: %w", tool.Name, err)
+	}
+	encode := func() ([]byte, error) {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode result of tool %q: %w", tool.Name, err)
+		}
+		return encoded, nil
+	}
+
+	object, isObject := result.(map[string]interface{})
+	if isObject {
+		object[TruncatedKey] = true
+		for _, optional := range largestFirst(optionalArrays(tool.Schemas.Output.Fields, object)) {
+			optional.set(nil)
+			encoded, err := encode()
+			if err != nil {
+				return nil, false, err
+			}
+			if policy.fits(encoded) {
+				return encoded, true, nil
+			}
+		}
+	}
+
+	for {
+		encoded, err := encode()
+		if err != nil {
+			return nil, false, err
+		}
+		excessBytes, excessTokens := policy.excess(encoded)
+		if excessBytes == 0 && excessTokens == 0 {
+			return encoded, true, nil
+		}
+		arrays := largestFirst(nonEmptyArrays(result, func(value interface{}) { result = value }))
+		if len(arrays) == 0 {
+			return encoded, true, nil
+		}
+		// Items are removed until their size covers the excess. Token estimates of single items
+		// only approximate what removing them saves; if too little was removed, the next round
+		// removes more.
+		items := arrays[0].value.([]interface{})
+		keep := len(items)
+		for removedBytes, removedTokens := 0, 0; keep > 0 && (removedBytes < excessBytes || removedTokens < excessTokens); keep-- {
+			item, err := json.Marshal(items[keep-1])
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to encode result of tool %q: %w", tool.Name, err)
+			}
+			removedBytes += len(item) + 1 // The item and its comma
+			removedTokens += policy.tokens(item)
+		}
+		arrays[0].set(items[:keep])
+	}
+}
+
+// optionalArrays returns the non-empty arrays of object, at any depth, that fields leave out of
+// "required".
+func optionalArrays(fields []*jobj.Field, object map[string]interface{}) []sizedValue {
+	var found []sizedValue
+	for _, field := range fields {
+		value, ok := object[field.ValueName]
+		if !ok {
+			continue
+		}
+		name := field.ValueName
+		if items, ok := value.([]interface{}); ok && len(items) > 0 && !field.ValueRequired {
+			found = append(found, sizedValue{value: value, set: func(interface{}) { delete(object, name) }})
+			continue
+		}
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if !field.AdditionalProperties {
+				found = append(found, optionalArrays(field.SubFields, value)...)
+			}
+		case []interface{}:
+			for _, item := range value {
+				if nested, ok := item.(map[string]interface{}); ok && field.SubFields != nil {
+					found = append(found, optionalArrays(field.SubFields, nested)...)
+				}
+			}
+		}
+	}
+	return found
+}
+
+// nonEmptyArrays returns every non-empty array in value, including value itself.
+func nonEmptyArrays(value interface{}, set func(value interface{})) []sizedValue {
+	var found []sizedValue
+	switch value := value.(type) {
+	case map[string]interface{}:
+		// Sorted so arrays of equal size are trimmed in a stable order
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			found = append(found, nonEmptyArrays(value[key], func(member interface{}) { value[key] = member })...)
+		}
+	case []interface{}:
+		if len(value) > 0 {
+			found = append(found, sizedValue{value: value, set: set})
+		}
+		for i, item := range value {
+			found = append(found, nonEmptyArrays(item, func(item interface{}) { value[i] = item })...)
+		}
+	}
+	return found
+}
+
+// largestFirst sizes values by their JSON encoding and sorts them largest first.
+func largestFirst(values []sizedValue) []sizedValue {
+	for i := range values {
+		encoded, _ := json.Marshal(values[i].value)
+		values[i].size = len(encoded)
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].size > values[j].size
+	})
+	return values
+}
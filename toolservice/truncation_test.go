@@ -0,0 +1,96 @@
+package toolservice
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type digestParams struct {
+	Items int `json:"items" desc:"Number of items" required:"true"`
+}
+
+type digest struct {
+	Title   string   `json:"title" desc:"Digest title" required:"true"`
+	Items   []string `json:"items" desc:"Digest items" required:"true"`
+	Related []string `json:"related" desc:"Related topics"`
+}
+
+func buildDigest(ctx context.Context, params digestParams) (digest, error) {
+	result := digest{Title: "Digest"}
+	for i := 0; i < params.Items; i++ {
+		result.Items = append(result.Items, strings.Repeat("i", 20))
+		result.Related = append(result.Related, strings.Repeat("r", 20))
+	}
+	return result, nil
+}
+
+func TestCallToolTruncation(t *testing.T) {
+	tool, err := NewTool("digest", "", buildDigest)
+	assert.NoError(t, err)
+	tool.Truncation = Truncation{MaxBytes: 300}
+
+	service, err := NewService(nil, tool)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	// Small results are returned as is
+	response, err := service.CallTool(ctx, &CallToolRequest{Name: "digest", Input: `{"items": 2}`})
+	assert.NoError(t, err)
+	assert.False(t, response.Truncated)
+	assert.NotContains(t, response.Output, TruncatedKey)
+
+	// The optional array is dropped first
+	response, err = service.CallTool(ctx, &CallToolRequest{Name: "digest", Input: `{"items": 6}`})
+	assert.NoError(t, err)
+	assert.True(t, response.Truncated)
+	assert.LessOrEqual(t, len(response.Output), 300)
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(response.Output), &result))
+	assert.NotContains(t, result, "related")
+	assert.Len(t, result["items"], 6)
+	assert.Equal(t, true, result[TruncatedKey])
+
+	// Then required arrays lose their trailing items
+	response, err = service.CallTool(ctx, &CallToolRequest{Name: "digest", Input: `{"items": 50}`})
+	assert.NoError(t, err)
+	assert.True(t, response.Truncated)
+	assert.LessOrEqual(t, len(response.Output), 300)
+	result = nil
+	assert.NoError(t, json.Unmarshal([]byte(response.Output), &result))
+	assert.NotContains(t, result, "related")
+	assert.Greater(t, len(result["items"].([]interface{})), 6)
+
+	tools, err := service.ListTools(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, tools.Tools[0].OutputSchema, TruncatedKey)
+	assert.NotContains(t, tool.Schemas.OutputProperties["properties"], TruncatedKey)
+
+	tool.Truncation = Truncation{MaxTokens: -1}
+	_, err = NewService(nil, tool)
+	assert.Error(t, err)
+}
+
+func TestTruncateOutputTokens(t *testing.T) {
+	tool, err := NewTool("list", "", func(ctx context.Context, params digestParams) ([]string, error) {
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	tool.Truncation = Truncation{
+		MaxTokens:      5,
+		EstimateTokens: func(output []byte) int { return strings.Count(string(output), ",") + 1 },
+	}
+
+	output, truncated, err := truncateOutput(tool, []byte(`["a","b","c","d","e","f","g","h"]`))
+	assert.NoError(t, err)
+	assert.True(t, truncated)
+	assert.JSONEq(t, `["a","b","c","d","e"]`, string(output))
+
+	output, truncated, err = truncateOutput(tool, []byte(`["a","b"]`))
+	assert.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Equal(t, `["a","b"]`, string(output))
+}
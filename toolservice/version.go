@@ -0,0 +1,152 @@
+package toolservice
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mhpenta/jobj/safeunmarshal"
+)
+
+// SchemaVersionKey is the input property a caller may set to the tool version its payload was
+// written for. CallTool removes it before validation, so tools never see it.
+const SchemaVersionKey = "schema_version"
+
+// Migration upgrades a tool input written for version From to version To. Migrate receives the
+// decoded input object, without SchemaVersionKey and with numbers as json.Number, and returns
+// the upgraded one.
+type Migration struct {
+	From    string
+	To      string
+	Migrate func(input map[string]interface{}) (map[string]interface{}, error)
+}
+
+// VersionError is returned, with code failed_precondition, when a caller sends a schema_version
+// the tool cannot accept: neither its current version nor one its migrations lead from.
+type VersionError struct {
+	Tool      string
+	Requested string
+	Current   string
+	Supported []string
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("tool %q does not accept schema_version %q; current version is %q, supported versions are %s",
+		e.Tool, e.Requested, e.Current, strings.Join(e.Supported, ", "))
+}
+
+// checkMigrations reports migrations a versioned tool cannot use. Each version may be migrated
+// from at most once, so the path from any version to the current one is unambiguous.
+func checkMigrations(tool Tool) error {
+	if len(tool.Migrations) > 0 && tool.Version == "" {
+		return fmt.Errorf("tool %q has migrations but no version", tool.Name)
+	}
+	from := make(map[string]bool, len(tool.Migrations))
+	for _, migration := range tool.Migrations {
+		switch {
+		case migration.From == "" || migration.To == "":
+			return fmt.Errorf("tool %q: migration versions must not be empty", tool.Name)
+		case migration.From == migration.To:
+			return fmt.Errorf("tool %q: migration from %q leads to the same version", tool.Name, migration.From)
+		case migration.Migrate == nil:
+			return fmt.Errorf("tool %q: migration from %q to %q has no Migrate function", tool.Name, migration.From, migration.To)
+		case migration.From == tool.Version:
+			return fmt.Errorf("tool %q: migration from current version %q is not allowed", tool.Name, migration.From)
+		case from[migration.From]:
+			return fmt.Errorf("tool %q: version %q is migrated from more than once", tool.Name, migration.From)
+		}
+		from[migration.From] = true
+	}
+	return nil
+}
+
+// negotiateVersion removes schema_version from input and, when it names an older version,
+// applies the tool's migrations in turn until the input matches the current version. Input is
+// repaired first, as Decode does, so fenced or malformed arguments lose their schema_version
+// too. Input without a schema_version, or for a tool without a Version, is assumed to be
+// current.
+func negotiateVersion(tool Tool, input []byte) ([]byte, error) {
+	if tool.Version == "" {
+		return input, nil
+	}
+	repaired, err := safeunmarshal.Repair(input)
+	if err != nil {
+		// Left for input validation to report
+		return input, nil
+	}
+	var object map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(repaired))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil || object == nil {
+		return input, nil
+	}
+	raw, ok := object[SchemaVersionKey]
+	if !ok {
+		return input, nil
+	}
+	delete(object, SchemaVersionKey)
+
+	var version string
+	switch raw := raw.(type) {
+	case string:
+		version = raw
+	case json.Number:
+		version = raw.String()
+	default:
+		return nil, invalidArgument(fmt.Errorf("%s must be a string, got %v", SchemaVersionKey, raw))
+	}
+
+	// Bounded by the number of migrations, in case they form a cycle that never reaches the
+	// current version
+	for steps := 0; version != tool.Version; steps++ {
+		migration, ok := migrationFrom(tool.Migrations, version)
+		if !ok || steps == len(tool.Migrations) {
+			return nil, &Error{Code: CodeFailedPrecondition, Err: &VersionError{
+				Tool:      tool.Name,
+				Requested: version,
+				Current:   tool.Version,
+				Supported: supportedVersions(tool),
+			}}
+		}
+		migrated, err := migration.Migrate(object)
+		if err != nil {
+			return nil, invalidArgument(fmt.Errorf("failed to migrate input from version %q to %q: %w", migration.From, migration.To, err))
+		}
+		object, version = migrated, migration.To
+	}
+
+	return json.Marshal(object)
+}
+
+func migrationFrom(migrations []Migration, version string) (Migration, bool) {
+	for _, migration := range migrations {
+		if migration.From == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// supportedVersions returns the current version followed by every version whose migrations lead
+// to it, sorted.
+func supportedVersions(tool Tool) []string {
+	var older []string
+	for _, migration := range tool.Migrations {
+		version, seen := migration.From, map[string]bool{}
+		for version != tool.Version && !seen[version] {
+			seen[version] = true
+			next, ok := migrationFrom(tool.Migrations, version)
+			if !ok {
+				break
+			}
+			version = next.To
+		}
+		if version == tool.Version {
+			older = append(older, migration.From)
+		}
+	}
+	sort.Strings(older)
+	return append([]string{tool.Version}, older...)
+}
@@ -0,0 +1,110 @@
+package toolservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newVersionedService serves search at version 3. Version 1 called the query "q" and version 2
+// called the limit "max".
+func newVersionedService(t *testing.T) *Service {
+	tool, err := NewTool("search", "", search)
+	assert.NoError(t, err)
+	tool.Version = "3"
+	tool.Migrations = []Migration{
+		{From: "1", To: "2", Migrate: func(input map[string]interface{}) (map[string]interface{}, error) {
+			q, ok := input["q"]
+			if !ok {
+				return nil, fmt.Errorf("q is missing")
+			}
+			input["query"] = q
+			delete(input, "q")
+			return input, nil
+		}},
+		{From: "2", To: "3", Migrate: func(input map[string]interface{}) (map[string]interface{}, error) {
+			if limit, ok := input["max"]; ok {
+				input["limit"] = limit
+				delete(input, "max")
+			}
+			return input, nil
+		}},
+	}
+
+	service, err := NewService(nil, tool)
+	assert.NoError(t, err)
+	return service
+}
+
+func TestCallToolSchemaVersion(t *testing.T) {
+	service := newVersionedService(t)
+	ctx := context.Background()
+
+	inputs := []string{
+		`{"query": "jobj"}`,
+		`{"schema_version": "3", "query": "jobj"}`,
+		`{"schema_version": 3, "query": "jobj", "limit": 5}`,
+		`{"schema_version": "2", "query": "jobj", "max": 5}`,
+		`{"schema_version": "1", "q": "jobj", "max": 5}`,
+		"```json\n{\"schema_version\": \"1\", \"q\": \"jobj\", \"max\": 5}\n```",
+		`{"schema_version": "2", "query": "jobj", "max": 5,}`,
+	}
+	for _, input := range inputs {
+		response, err := service.CallTool(ctx, &CallToolRequest{Name: "search", Input: input})
+		if assert.NoError(t, err, input) {
+			assert.JSONEq(t, `{"titles": ["Result for jobj"]}`, response.Output)
+		}
+	}
+
+	_, err := service.CallTool(ctx, &CallToolRequest{Name: "search", Input: `{"schema_version": "0", "query": "jobj"}`})
+	var serviceErr *Error
+	var versionErr *VersionError
+	assert.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, CodeFailedPrecondition, serviceErr.Code)
+	assert.True(t, errors.As(err, &versionErr))
+	assert.Equal(t, "0", versionErr.Requested)
+	assert.Equal(t, "3", versionErr.Current)
+	assert.Equal(t, []string{"3", "1", "2"}, versionErr.Supported)
+
+	_, err = service.CallTool(ctx, &CallToolRequest{Name: "search", Input: `{"schema_version": "1", "query": "jobj"}`})
+	assert.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, CodeInvalidArgument, serviceErr.Code)
+	assert.Contains(t, err.Error(), "q is missing")
+
+	_, err = service.CallTool(ctx, &CallToolRequest{Name: "search", Input: `{"schema_version": true, "query": "jobj"}`})
+	assert.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, CodeInvalidArgument, serviceErr.Code)
+}
+
+func TestNegotiateVersionKeepsLargeIntegers(t *testing.T) {
+	input, err := negotiateVersion(Tool{Version: "3"}, []byte(`{"schema_version": "3", "id": 9007199254740993}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":9007199254740993}`, string(input))
+}
+
+func TestNewServiceRejectsInvalidMigrations(t *testing.T) {
+	noop := func(input map[string]interface{}) (map[string]interface{}, error) { return input, nil }
+	tests := map[string]struct {
+		version    string
+		migrations []Migration
+	}{
+		"no version":        {migrations: []Migration{{From: "1", To: "2", Migrate: noop}}},
+		"empty version":     {version: "2", migrations: []Migration{{From: "", To: "2", Migrate: noop}}},
+		"same version":      {version: "2", migrations: []Migration{{From: "1", To: "1", Migrate: noop}}},
+		"no function":       {version: "2", migrations: []Migration{{From: "1", To: "2"}}},
+		"from current":      {version: "2", migrations: []Migration{{From: "2", To: "3", Migrate: noop}}},
+		"ambiguous version": {version: "3", migrations: []Migration{{From: "1", To: "2", Migrate: noop}, {From: "1", To: "3", Migrate: noop}}},
+	}
+	for name, tt := range tests {
+		tool, err := NewTool("search", "", search)
+		assert.NoError(t, err)
+		tool.Version = tt.version
+		tool.Migrations = tt.migrations
+
+		_, err = NewService(nil, tool)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,473 @@
+package jobj
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError describes a single mismatch between a JSON document and a Schema.
+//
+// Pointer is an RFC 6901 JSON Pointer to the offending value ("" for the document root).
+// Expected describes what the schema requires at that location and Actual holds the
+// decoded value that was found (nil when the value is missing).
+type ValidationError struct {
+	Pointer  string
+	Expected string
+	Actual   interface{}
+	Message  string
+}
+
+func (e ValidationError) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s: %s", pointer, e.Message)
+}
+
+// ValidationErrors is a list of ValidationError values. It implements error so it can be
+// returned directly, and its Error output is one line per problem, which makes it suitable
+// for feeding back to an LLM as a correction prompt.
+type ValidationErrors []ValidationError
+
+func (v ValidationErrors) Error() string {
+	lines := make([]string, 0, len(v))
+	for _, e := range v {
+		lines = append(lines, e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ValidateJSON checks a JSON document against the schema and returns every mismatch found,
+// ordered by pointer. A nil slice means the document is valid. The error return is only used
+// when data is not well-formed JSON.
+func (r *Schema) ValidateJSON(data []byte) ([]ValidationError, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	errs := r.validateValue(value)
+	// Objects are decoded as maps, so errors are sorted for a stable order between runs
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Pointer < errs[j].Pointer
+	})
+	return errs, nil
+}
+
+// validateValue implements ValidateJSON for a decoded document.
+func (r *Schema) validateValue(value interface{}) []ValidationError {
+	var errs []ValidationError
+	if r.RootField != nil {
+		validateValue(r.RootField, value, "", &errs)
+		return errs
+	}
+
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		errs = append(errs, typeMismatch("", string(TypeObject), value))
+		return errs
+	}
+	validateObject(r.Fields, obj, "", r.AdditionalProperties, &errs)
+	if r.AdditionalPropertiesField != nil && r.AdditionalPropertiesField.SubFields != nil {
+		known := make(map[string]bool, len(r.Fields))
+		for _, field := range r.Fields {
+			known[field.ValueName] = true
+		}
+		for key, v := range obj {
+			if !known[key] {
+				validateValue(r.AdditionalPropertiesField, v, "/"+escapePointerToken(key), &errs)
+			}
+		}
+	}
+	return errs
+}
+
+func validateObject(fields []*Field, obj map[string]interface{}, pointer string, allowAdditional bool, errs *[]ValidationError) {
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[field.ValueName] = true
+		fieldPointer := pointer + "/" + escapePointerToken(field.ValueName)
+
+		value, exists := obj[field.ValueName]
+		if !exists {
+			if field.ValueRequired {
+				*errs = append(*errs, ValidationError{
+					Pointer:  fieldPointer,
+					Expected: expectedDescription(field),
+					Message:  fmt.Sprintf("required property %q is missing", field.ValueName),
+				})
+			}
+			continue
+		}
+		validateValue(field, value, fieldPointer, errs)
+	}
+
+	if allowAdditional {
+		return
+	}
+	for name, value := range obj {
+		if known[name] {
+			continue
+		}
+		*errs = append(*errs, ValidationError{
+			Pointer: pointer + "/" + escapePointerToken(name),
+			Actual:  value,
+			Message: fmt.Sprintf("property %q is not allowed", name),
+		})
+	}
+}
+
+func validateValue(field *Field, value interface{}, pointer string, errs *[]ValidationError) {
+	if value == nil && field.ValueNullable {
+		return
+	}
+	if field.ValueAnyOf != nil {
+		for _, enum := range field.ValueAnyOf {
+			if constMatches(enum.Const, value) {
+				return
+			}
+		}
+		*errs = append(*errs, ValidationError{
+			Pointer:  pointer,
+			Expected: expectedDescription(field),
+			Actual:   value,
+			Message:  fmt.Sprintf("expected %s, got %s", expectedDescription(field), describeValue(value)),
+		})
+		return
+	}
+
+	if field.ValueVariants != nil {
+		for _, variant := range field.ValueVariants {
+			var variantErrs []ValidationError
+			validateValue(variant, value, pointer, &variantErrs)
+			if len(variantErrs) == 0 {
+				return
+			}
+		}
+		*errs = append(*errs, ValidationError{
+			Pointer:  pointer,
+			Expected: expectedDescription(field),
+			Actual:   value,
+			Message:  fmt.Sprintf("expected %s, got %s", expectedDescription(field), describeValue(value)),
+		})
+		return
+	}
+
+	switch field.ValueType {
+	case TypeArray:
+		items, ok := value.([]interface{})
+		if !ok {
+			*errs = append(*errs, typeMismatch(pointer, string(TypeArray), value))
+			return
+		}
+		validateCardinality(field, items, pointer, errs)
+		for i, item := range items {
+			itemPointer := fmt.Sprintf("%s/%d", pointer, i)
+			if field.ArrayItemField != nil {
+				validateValue(field.ArrayItemField, item, itemPointer, errs)
+				continue
+			}
+			if field.ArrayItemType != "" {
+				validateValue(&Field{ValueType: field.ArrayItemType}, item, itemPointer, errs)
+				continue
+			}
+			if field.SubFields != nil {
+				itemObj, ok := item.(map[string]interface{})
+				if !ok {
+					*errs = append(*errs, typeMismatch(itemPointer, string(TypeObject), item))
+					continue
+				}
+				validateObject(field.SubFields, itemObj, itemPointer, true, errs)
+			}
+		}
+	case TypeObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, typeMismatch(pointer, string(TypeObject), value))
+			return
+		}
+		if field.AdditionalProperties && field.AdditionalPropertiesType != "" {
+			values := field.AdditionalPropertiesField
+			if values == nil {
+				values = &Field{ValueType: field.AdditionalPropertiesType}
+			}
+			for key, v := range obj {
+				validateValue(values, v, pointer+"/"+escapePointerToken(key), errs)
+			}
+			return
+		}
+		if field.AdditionalProperties && field.AdditionalPropertiesField != nil {
+			if field.AdditionalPropertiesField.SubFields == nil {
+				return
+			}
+			for key, v := range obj {
+				validateValue(field.AdditionalPropertiesField, v, pointer+"/"+escapePointerToken(key), errs)
+			}
+			return
+		}
+		validateObject(field.SubFields, obj, pointer, true, errs)
+	default:
+		if !matchesPrimitive(field.ValueType, value) {
+			*errs = append(*errs, typeMismatch(pointer, string(field.ValueType), value))
+			return
+		}
+		validateBounds(field, value, pointer, errs)
+		if field.ValueContentEncoding == "base64" {
+			if _, err := base64.StdEncoding.DecodeString(value.(string)); err != nil {
+				*errs = append(*errs, ValidationError{
+					Pointer:  pointer,
+					Expected: "base64-encoded string",
+					Actual:   value,
+					Message:  "expected base64-encoded string",
+				})
+			}
+		}
+	}
+}
+
+// validateBounds checks the length of a string against minLength and maxLength, and a number
+// against minimum and maximum.
+func validateBounds(field *Field, value interface{}, pointer string, errs *[]ValidationError) {
+	if !field.hasBounds() {
+		return
+	}
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if field.ValueMinLength != nil && length < *field.ValueMinLength {
+			*errs = append(*errs, ValidationError{
+				Pointer:  pointer,
+				Expected: fmt.Sprintf("at least %d characters", *field.ValueMinLength),
+				Actual:   value,
+				Message:  fmt.Sprintf("expected at least %d characters, got %d", *field.ValueMinLength, length),
+			})
+		}
+		if field.ValueMaxLength != nil && length > *field.ValueMaxLength {
+			*errs = append(*errs, ValidationError{
+				Pointer:  pointer,
+				Expected: fmt.Sprintf("at most %d characters", *field.ValueMaxLength),
+				Actual:   value,
+				Message:  fmt.Sprintf("expected at most %d characters, got %d", *field.ValueMaxLength, length),
+			})
+		}
+	case json.Number:
+		number, err := v.Float64()
+		if err != nil {
+			return
+		}
+		if field.ValueMinimum != nil && number < *field.ValueMinimum {
+			*errs = append(*errs, ValidationError{
+				Pointer:  pointer,
+				Expected: "at least " + formatBound(*field.ValueMinimum),
+				Actual:   value,
+				Message:  fmt.Sprintf("expected at least %s, got %s", formatBound(*field.ValueMinimum), v),
+			})
+		}
+		if field.ValueMaximum != nil && number > *field.ValueMaximum {
+			*errs = append(*errs, ValidationError{
+				Pointer:  pointer,
+				Expected: "at most " + formatBound(*field.ValueMaximum),
+				Actual:   value,
+				Message:  fmt.Sprintf("expected at most %s, got %s", formatBound(*field.ValueMaximum), v),
+			})
+		}
+	}
+}
+
+// formatBound formats a minimum or maximum without trailing zeros, e.g. 10 rather than 10.000000.
+func formatBound(bound float64) string {
+	return strconv.FormatFloat(bound, 'f', -1, 64)
+}
+
+// validateCardinality checks the number of items of an array against minItems and maxItems, and
+// their uniqueness against uniqueItems.
+func validateCardinality(field *Field, items []interface{}, pointer string, errs *[]ValidationError) {
+	if field.ValueMinItems != nil && len(items) < *field.ValueMinItems {
+		*errs = append(*errs, ValidationError{
+			Pointer:  pointer,
+			Expected: fmt.Sprintf("at least %d items", *field.ValueMinItems),
+			Actual:   items,
+			Message:  fmt.Sprintf("expected at least %d items, got %d", *field.ValueMinItems, len(items)),
+		})
+	}
+	if field.ValueMaxItems != nil && len(items) > *field.ValueMaxItems {
+		*errs = append(*errs, ValidationError{
+			Pointer:  pointer,
+			Expected: fmt.Sprintf("at most %d items", *field.ValueMaxItems),
+			Actual:   items,
+			Message:  fmt.Sprintf("expected at most %d items, got %d", *field.ValueMaxItems, len(items)),
+		})
+	}
+	if !field.ValueUniqueItems {
+		return
+	}
+	for i := range items {
+		for j := 0; j < i; j++ {
+			if instancesEqual(items[j], items[i]) {
+				*errs = append(*errs, ValidationError{
+					Pointer:  fmt.Sprintf("%s/%d", pointer, i),
+					Expected: "unique items",
+					Actual:   items[i],
+					Message:  fmt.Sprintf("item %d duplicates item %d", i, j),
+				})
+				break
+			}
+		}
+	}
+}
+
+// instancesEqual reports whether two decoded JSON values are equal, comparing numbers by value.
+func instancesEqual(a, b interface{}) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		return ok && numbersEqual(x, y)
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !instancesEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, ok := y[key]
+			if !ok || !instancesEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return jsonEqual(a, b)
+}
+
+func matchesPrimitive(dataType DataType, value interface{}) bool {
+	switch dataType {
+	case TypeString:
+		_, ok := value.(string)
+		return ok
+	case TypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case TypeNumber:
+		_, ok := value.(json.Number)
+		return ok
+	case TypeInteger:
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		// JSON Schema integers are numbers with a zero fractional part, so 1.0 and 1e3 qualify
+		number, _, err := big.ParseFloat(n.String(), 10, 256, big.ToNearestEven)
+		return err == nil && number.IsInt()
+	default:
+		// For unknown types, we're permissive
+		return true
+	}
+}
+
+// constMatches reports whether a decoded JSON value equals an AnyOf const. Numbers are compared
+// by value, so a const of 2 matches both 2 and 2.0 in the document.
+func constMatches(constValue interface{}, value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		c, ok := constValue.(string)
+		return ok && c == v
+	case bool:
+		c, ok := constValue.(bool)
+		return ok && c == v
+	case json.Number:
+		got, err := v.Float64()
+		if err != nil {
+			return false
+		}
+		switch c := reflect.ValueOf(constValue); c.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(c.Int()) == got
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return float64(c.Uint()) == got
+		case reflect.Float32, reflect.Float64:
+			return c.Float() == got
+		}
+	}
+	return false
+}
+
+func typeMismatch(pointer string, expected string, value interface{}) ValidationError {
+	return ValidationError{
+		Pointer:  pointer,
+		Expected: expected,
+		Actual:   value,
+		Message:  fmt.Sprintf("expected %s, got %s", expected, describeValue(value)),
+	}
+}
+
+func expectedDescription(field *Field) string {
+	if field.ValueVariants != nil {
+		variants := make([]string, 0, len(field.ValueVariants))
+		for _, variant := range field.ValueVariants {
+			variants = append(variants, expectedDescription(variant))
+		}
+		return strings.Join(variants, " or ")
+	}
+	if field.ValueAnyOf == nil {
+		if field.ValueType == "" {
+			return "any value"
+		}
+		return string(field.ValueType)
+	}
+	consts := make([]string, 0, len(field.ValueAnyOf))
+	for _, enum := range field.ValueAnyOf {
+		encoded, err := json.Marshal(enum.Const)
+		if err != nil {
+			encoded = []byte(fmt.Sprintf("%v", enum.Const))
+		}
+		consts = append(consts, string(encoded))
+	}
+	return "one of " + strings.Join(consts, ", ")
+}
+
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("boolean %t", v)
+	case json.Number:
+		return "number " + v.String()
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// escapePointerToken escapes a property name for use as a JSON Pointer reference token.
+func escapePointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
@@ -0,0 +1,184 @@
+package jobj
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func newValidationTestSchema() *Schema {
+	return &Schema{
+		Name: "ValidationTest",
+		Fields: []*Field{
+			Text("name").Required(),
+			Int("count").Required(),
+			AnyOf("kind", []ConstDescription{
+				{Const: "a", Description: "first"},
+				{Const: "b", Description: "second"},
+			}),
+			Array("items", []*Field{
+				Text("label").Required(),
+				Float("score"),
+			}),
+			ArrayOf("tags", TypeString),
+		},
+	}
+}
+
+func TestValidateJSONValid(t *testing.T) {
+	s := newValidationTestSchema()
+
+	errs, err := s.ValidateJSON([]byte(`{"name":"x","count":3,"kind":"a","items":[{"label":"l","score":1.5}],"tags":["t"]}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected no validation errors, got %v", errs)
+	}
+}
+
+func TestValidateJSONPointers(t *testing.T) {
+	s := newValidationTestSchema()
+
+	errs, err := s.ValidateJSON([]byte(`{"count":1.5,"kind":"c","items":[{"score":"high"}],"tags":["ok",2],"extra/key":true}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+
+	byPointer := make(map[string]ValidationError)
+	for _, e := range errs {
+		byPointer[e.Pointer] = e
+	}
+
+	tests := []struct {
+		pointer  string
+		expected string
+	}{
+		{"/name", "string"},
+		{"/count", "integer"},
+		{"/kind", `one of "a", "b"`},
+		{"/items/0/label", "string"},
+		{"/items/0/score", "number"},
+		{"/tags/1", "string"},
+		{"/extra~1key", ""},
+	}
+
+	if len(errs) != len(tests) {
+		t.Errorf("Expected %d errors, got %d: %v", len(tests), len(errs), errs)
+	}
+
+	for _, tt := range tests {
+		e, ok := byPointer[tt.pointer]
+		if !ok {
+			t.Errorf("Expected error at %s, got %v", tt.pointer, errs)
+			continue
+		}
+		if e.Expected != tt.expected {
+			t.Errorf("Error at %s: expected %q, got %q", tt.pointer, tt.expected, e.Expected)
+		}
+	}
+
+	if byPointer["/count"].Actual != json.Number("1.5") {
+		t.Errorf("Expected actual value 1.5 at /count, got %v", byPointer["/count"].Actual)
+	}
+	if byPointer["/name"].Actual != nil {
+		t.Errorf("Expected nil actual value for missing field, got %v", byPointer["/name"].Actual)
+	}
+}
+
+func TestValidateJSONInvalidDocument(t *testing.T) {
+	s := newValidationTestSchema()
+
+	if _, err := s.ValidateJSON([]byte(`{"name":`)); err == nil {
+		t.Error("Expected error for malformed JSON")
+	}
+
+	errs, err := s.ValidateJSON([]byte(`[]`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 1 || errs[0].Pointer != "" {
+		t.Errorf("Expected a single root error, got %v", errs)
+	}
+}
+
+func TestValidationErrorsMessage(t *testing.T) {
+	errs := ValidationErrors{
+		{Pointer: "", Message: "expected object, got array"},
+		{Pointer: "/name", Message: `required property "name" is missing`},
+	}
+
+	want := "/: expected object, got array\n/name: required property \"name\" is missing"
+	if errs.Error() != want {
+		t.Errorf("Expected %q, got %q", want, errs.Error())
+	}
+}
+
+func TestValidateJSONTypedConsts(t *testing.T) {
+	s := &Schema{
+		Name: "Severity",
+		Fields: []*Field{
+			AnyOf("severity", []ConstDescription{
+				{Const: 1, Description: "low"},
+				{Const: 2, Description: "medium"},
+			}).Required(),
+			AnyOf("flag", []ConstDescription{{Const: true, Description: "set"}}),
+		},
+	}
+
+	errs, err := s.ValidateJSON([]byte(`{"severity":2,"flag":true}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected no validation errors, got %v", errs)
+	}
+
+	errs, err = s.ValidateJSON([]byte(`{"severity":"2"}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 1 || errs[0].Expected != "one of 1, 2" {
+		t.Errorf("Expected string const to be rejected, got %v", errs)
+	}
+}
+
+func TestValidateJSONIntegralNumbers(t *testing.T) {
+	s := &Schema{Name: "Count", Fields: []*Field{Int("count").Required()}}
+
+	for _, doc := range []string{`{"count":1}`, `{"count":1.0}`, `{"count":1e3}`} {
+		errs, err := s.ValidateJSON([]byte(doc))
+		if err != nil {
+			t.Fatalf("ValidateJSON returned error: %v", err)
+		}
+		if len(errs) != 0 {
+			t.Errorf("Expected %s to be a valid integer, got %v", doc, errs)
+		}
+	}
+
+	errs, err := s.ValidateJSON([]byte(`{"count":1.5}`))
+	if err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if len(errs) != 1 || errs[0].Pointer != "/count" {
+		t.Errorf("Expected a single error at /count, got %v", errs)
+	}
+}
+
+func TestValidateJSONSortedErrors(t *testing.T) {
+	s := &Schema{Name: "Closed", Fields: []*Field{Text("name")}}
+
+	for i := 0; i < 10; i++ {
+		errs, err := s.ValidateJSON([]byte(`{"d":1,"b":2,"e":3,"a":4,"c":5}`))
+		if err != nil {
+			t.Fatalf("ValidateJSON returned error: %v", err)
+		}
+		if len(errs) != 5 {
+			t.Fatalf("Expected 5 additional property errors, got %v", errs)
+		}
+		for j, want := range []string{"/a", "/b", "/c", "/d", "/e"} {
+			if errs[j].Pointer != want {
+				t.Fatalf("Expected errors sorted by pointer, got %v", errs)
+			}
+		}
+	}
+}
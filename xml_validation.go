@@ -0,0 +1,258 @@
+package jobj
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// xmlNode is an element of a parsed XML document.
+type xmlNode struct {
+	name       string
+	attributes map[string]string
+	children   []*xmlNode
+	text       strings.Builder
+}
+
+// ValidateXML checks an XML document against the schema as rendered by GetXMLSchemaString and
+// returns every mismatch found, in the same form as ValidateJSON so the result can be fed back to
+// the model. Pointers name elements by field, with array items by index, e.g. "/quotes/0/speaker".
+//
+// It checks the root element's name, required elements and attributes, elements the schema does
+// not declare or that appear more than once, enum values and the lexical form of integers,
+// numbers, booleans, dates and date-times. Unlike a strict XSD validator it accepts elements in
+// any order, since decoders do not depend on it. A nil slice means the document is valid; the
+// error return is only used when data is not well-formed XML.
+func (r *Schema) ValidateXML(data []byte) ([]ValidationError, error) {
+	root, err := parseXML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []ValidationError
+	if root.name != r.Name {
+		errs = append(errs, ValidationError{
+			Expected: "<" + r.Name + ">",
+			Actual:   root.name,
+			Message:  fmt.Sprintf("expected root element <%s>, got <%s>", r.Name, root.name),
+		})
+	}
+
+	if r.RootField != nil {
+		validateXMLNode(r.RootField, root, "", &errs)
+		return errs, nil
+	}
+	rootField := Object(r.Name, r.Fields)
+	rootField.AdditionalProperties = r.AdditionalProperties
+	validateXMLObject(rootField, root, "", &errs)
+	return errs, nil
+}
+
+// parseXML returns the first element of data. HTML entities such as &nbsp; are accepted.
+func parseXML(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Entity = xml.HTMLEntity
+
+	var stack []*xmlNode
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("invalid XML: no element found")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML: %w", err)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: token.Name.Local, attributes: make(map[string]string, len(token.Attr))}
+			for _, attribute := range token.Attr {
+				node.attributes[attribute.Name.Local] = attribute.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return node, nil
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(token)
+			}
+		}
+	}
+}
+
+func validateXMLNode(field *Field, node *xmlNode, pointer string, errs *[]ValidationError) {
+	if xsdSimpleType(field) != "" {
+		validateXMLText(field, node, pointer, errs)
+		return
+	}
+
+	switch {
+	case field.ValueType == TypeArray:
+		_, items := xmlContent(field)
+		index := 0
+		for _, child := range node.children {
+			if child.name != xsdItemElement {
+				*errs = append(*errs, unexpectedXMLElement(pointer, child))
+				continue
+			}
+			validateXMLNode(items[0], child, fmt.Sprintf("%s/%d", pointer, index), errs)
+			index++
+		}
+	case field.AdditionalProperties:
+		var value *Field
+		switch {
+		case field.AdditionalPropertiesField != nil && field.AdditionalPropertiesField.SubFields != nil:
+			value = field.AdditionalPropertiesField
+		case field.AdditionalPropertiesType != "":
+			value = &Field{ValueType: field.AdditionalPropertiesType}
+		default:
+			return
+		}
+		for _, child := range node.children {
+			validateXMLNode(value, child, pointer+"/"+escapePointerToken(child.name), errs)
+		}
+	default:
+		validateXMLObject(field, node, pointer, errs)
+	}
+}
+
+// validateXMLObject checks the attributes and child elements of an object element. Undeclared
+// children are allowed when the object allows additional properties.
+func validateXMLObject(field *Field, node *xmlNode, pointer string, errs *[]ValidationError) {
+	attributes, elements := xmlContent(field)
+	for _, attribute := range attributes {
+		attributePointer := pointer + "/" + escapePointerToken(attribute.ValueName)
+		value, ok := node.attributes[attribute.ValueName]
+		if !ok {
+			if attribute.ValueRequired {
+				*errs = append(*errs, ValidationError{
+					Pointer:  attributePointer,
+					Expected: expectedDescription(attribute),
+					Message:  fmt.Sprintf("required attribute %q is missing", attribute.ValueName),
+				})
+			}
+			continue
+		}
+		validateXMLValue(attribute, value, attributePointer, errs)
+	}
+
+	byName := make(map[string][]*xmlNode, len(node.children))
+	for _, child := range node.children {
+		byName[child.name] = append(byName[child.name], child)
+	}
+
+	known := make(map[string]bool, len(elements))
+	for _, element := range elements {
+		known[element.ValueName] = true
+		elementPointer := pointer + "/" + escapePointerToken(element.ValueName)
+
+		children := byName[element.ValueName]
+		switch {
+		case len(children) == 0 && element.ValueRequired:
+			*errs = append(*errs, ValidationError{
+				Pointer:  elementPointer,
+				Expected: expectedDescription(element),
+				Message:  fmt.Sprintf("required element <%s> is missing", element.ValueName),
+			})
+		case len(children) > 1:
+			*errs = append(*errs, ValidationError{
+				Pointer: elementPointer,
+				Message: fmt.Sprintf("element <%s> appears %d times, expected at most once", element.ValueName, len(children)),
+			})
+		}
+		if len(children) > 0 {
+			validateXMLNode(element, children[0], elementPointer, errs)
+		}
+	}
+
+	if field.AdditionalProperties {
+		return
+	}
+	for _, child := range node.children {
+		if !known[child.name] {
+			*errs = append(*errs, unexpectedXMLElement(pointer, child))
+		}
+	}
+}
+
+func validateXMLText(field *Field, node *xmlNode, pointer string, errs *[]ValidationError) {
+	if len(node.children) > 0 {
+		*errs = append(*errs, ValidationError{
+			Pointer:  pointer,
+			Expected: expectedDescription(field),
+			Actual:   node.children[0].name,
+			Message:  fmt.Sprintf("expected text content, got element <%s>", node.children[0].name),
+		})
+		return
+	}
+	validateXMLValue(field, node.text.String(), pointer, errs)
+}
+
+// validateXMLValue checks the text of an element or attribute against the lexical space of its
+// XSD type.
+func validateXMLValue(field *Field, raw string, pointer string, errs *[]ValidationError) {
+	value := strings.TrimSpace(raw)
+
+	if field.ValueAnyOf != nil {
+		for _, option := range field.ValueAnyOf {
+			if option.Const != nil && fmt.Sprint(option.Const) == value {
+				return
+			}
+		}
+		*errs = append(*errs, ValidationError{
+			Pointer:  pointer,
+			Expected: expectedDescription(field),
+			Actual:   value,
+			Message:  fmt.Sprintf("expected %s, got %q", expectedDescription(field), value),
+		})
+		return
+	}
+
+	var err error
+	switch xsdSimpleType(field) {
+	case "xs:integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "xs:decimal":
+		_, err = strconv.ParseFloat(value, 64)
+	case "xs:boolean":
+		switch value {
+		case "true", "false", "1", "0":
+		default:
+			err = fmt.Errorf("not a boolean")
+		}
+	case "xs:date":
+		_, err = time.Parse(time.DateOnly, value)
+	case "xs:dateTime":
+		_, err = time.Parse(time.RFC3339, value)
+	}
+	if err != nil {
+		expected := strings.TrimPrefix(xsdSimpleType(field), "xs:")
+		*errs = append(*errs, ValidationError{
+			Pointer:  pointer,
+			Expected: expected,
+			Actual:   value,
+			Message:  fmt.Sprintf("expected %s, got %q", expected, value),
+		})
+	}
+}
+
+func unexpectedXMLElement(pointer string, node *xmlNode) ValidationError {
+	return ValidationError{
+		Pointer: pointer + "/" + escapePointerToken(node.name),
+		Actual:  node.name,
+		Message: fmt.Sprintf("element <%s> is not allowed", node.name),
+	}
+}
@@ -0,0 +1,100 @@
+package jobj
+
+import (
+	"strings"
+	"testing"
+)
+
+func xmlValidationSchema() *Schema {
+	return &Schema{
+		Name: "PressRelease",
+		Fields: []*Field{
+			Text("headline").Required(),
+			Int("id").AsXMLAttribute().Required(),
+			AnyOf("sentiment", []ConstDescription{{Const: "positive"}, {Const: "negative"}}),
+			Float("score"),
+			Bool("verified"),
+			Text("published").Format("date-time"),
+			ArrayOf("tags", TypeString),
+			Array("quotes", []*Field{Text("speaker").Required(), Int("year")}),
+			Map("counts", TypeInteger),
+		},
+	}
+}
+
+func TestValidateXML_Example(t *testing.T) {
+	s := xmlValidationSchema()
+	errs, err := s.ValidateXML([]byte(s.ToXMLExample()))
+	if err != nil || len(errs) != 0 {
+		t.Errorf("Expected the XML example to validate, got %v %v\n%s", errs, err, s.ToXMLExample())
+	}
+}
+
+func TestValidateXML(t *testing.T) {
+	s := xmlValidationSchema()
+	document := `<PressRelease id="seven">
+  <sentiment>neutral</sentiment>
+  <score>high</score>
+  <verified>yes</verified>
+  <published>2024-01-15</published>
+  <tags><item>a</item><tag>b</tag></tags>
+  <quotes>
+    <item><year>2024</year></item>
+    <item><speaker>Ann</speaker><year>last year</year></item>
+  </quotes>
+  <counts><views>10</views><shares>many</shares></counts>
+  <summary>Not in the schema</summary>
+  <score>1.5</score>
+</PressRelease>`
+
+	errs, err := s.ValidateXML([]byte(document))
+	if err != nil {
+		t.Fatalf("ValidateXML failed: %v", err)
+	}
+
+	expected := []string{
+		`/id: expected integer, got "seven"`,
+		`/headline: required element <headline> is missing`,
+		`/sentiment: expected one of "positive", "negative", got "neutral"`,
+		`/score: element <score> appears 2 times, expected at most once`,
+		`/score: expected decimal, got "high"`,
+		`/verified: expected boolean, got "yes"`,
+		`/published: expected dateTime, got "2024-01-15"`,
+		`/tags/tag: element <tag> is not allowed`,
+		`/quotes/0/speaker: required element <speaker> is missing`,
+		`/quotes/1/year: expected integer, got "last year"`,
+		`/counts/shares: expected integer, got "many"`,
+		`/summary: element <summary> is not allowed`,
+	}
+	got := ValidationErrors(errs).Error()
+	for _, want := range expected {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+	if len(errs) != len(expected) {
+		t.Errorf("Expected %d errors, got %d:\n%s", len(expected), len(errs), got)
+	}
+}
+
+func TestValidateXML_Root(t *testing.T) {
+	s := &Schema{Name: "Labels", RootField: ArrayOf("result", TypeString)}
+	if errs, err := s.ValidateXML([]byte(`<Labels><item>a</item></Labels>`)); err != nil || len(errs) != 0 {
+		t.Errorf("Expected valid document, got %v %v", errs, err)
+	}
+
+	errs, err := s.ValidateXML([]byte(`<Tags><item><b>a</b></item></Tags>`))
+	if err != nil {
+		t.Fatalf("ValidateXML failed: %v", err)
+	}
+	if len(errs) != 2 || errs[0].Pointer != "" || errs[1].Pointer != "/0" {
+		t.Errorf("Expected root name and item content errors, got %v", errs)
+	}
+
+	if _, err := s.ValidateXML([]byte(`<Labels><item>`)); err == nil {
+		t.Errorf("Expected malformed XML to return an error")
+	}
+	if _, err := s.ValidateXML([]byte(`no xml here`)); err == nil {
+		t.Errorf("Expected input without elements to return an error")
+	}
+}
@@ -0,0 +1,278 @@
+package jobj
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// xsdItemElement names the repeated child element of arrays, e.g. <tags><item>a</item></tags>.
+const xsdItemElement = "item"
+
+// GetXMLSchemaString returns the schema as an XML Schema (XSD) document, for prompts that ask the
+// model to answer in XML. The root element is named after the schema.
+//
+// Objects become nested xs:complexType sequences. Arrays become a wrapper element holding
+// repeated "item" elements (maxOccurs="unbounded"). Maps, whose keys become element names, accept
+// any child elements. Optional fields have minOccurs="0" and descriptions become xs:documentation
+// annotations. Fields marked with AsXMLAttribute become xs:attribute declarations of their parent,
+// and AnyOf fields restrict their values with xs:enumeration.
+func (r *Schema) GetXMLSchemaString() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" elementFormDefault="qualified">` + "\n")
+
+	root := r.RootField
+	if root == nil {
+		root = Object(r.Name, r.Fields)
+	}
+	root = root.renamed(r.Name)
+	if root.ValueDescription == "" {
+		root.ValueDescription = r.Description
+	}
+	writeXSDElement(&b, root, true, false, 1)
+
+	b.WriteString("</xs:schema>\n")
+	return b.String()
+}
+
+// renamed returns a shallow copy of the field with a different name.
+func (f *Field) renamed(name string) *Field {
+	copied := *f
+	copied.ValueName = name
+	return &copied
+}
+
+func writeXSDElement(b *strings.Builder, field *Field, required bool, repeated bool, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	b.WriteString(indent)
+	b.WriteString(`<xs:element name="`)
+	b.WriteString(xmlEscape(field.ValueName))
+	b.WriteString(`"`)
+	if !required {
+		b.WriteString(` minOccurs="0"`)
+	}
+	if repeated {
+		b.WriteString(` maxOccurs="unbounded"`)
+	}
+
+	if field.ValueAnyOf != nil {
+		b.WriteString(">\n")
+		writeXSDDocumentation(b, field.ValueDescription, depth+1)
+		writeXSDEnumeration(b, field.ValueAnyOf, depth+1)
+		b.WriteString(indent + "</xs:element>\n")
+		return
+	}
+
+	simpleType := xsdSimpleType(field)
+	if simpleType != "" && field.ValueDescription == "" {
+		b.WriteString(` type="` + simpleType + `"/>` + "\n")
+		return
+	}
+	if simpleType != "" {
+		b.WriteString(` type="` + simpleType + `">` + "\n")
+		writeXSDDocumentation(b, field.ValueDescription, depth+1)
+		b.WriteString(indent + "</xs:element>\n")
+		return
+	}
+
+	b.WriteString(">\n")
+	writeXSDDocumentation(b, field.ValueDescription, depth+1)
+	b.WriteString(indent + "  <xs:complexType>\n")
+	var attributes []*Field
+	switch {
+	case field.ValueType == TypeArray:
+		_, items := xmlContent(field)
+		b.WriteString(indent + "    <xs:sequence>\n")
+		writeXSDElement(b, items[0], false, true, depth+3)
+		b.WriteString(indent + "    </xs:sequence>\n")
+	case field.AdditionalProperties:
+		b.WriteString(indent + "    <xs:sequence>\n")
+		b.WriteString(indent + `      <xs:any processContents="lax" minOccurs="0" maxOccurs="unbounded"/>` + "\n")
+		b.WriteString(indent + "    </xs:sequence>\n")
+	default:
+		var elements []*Field
+		attributes, elements = xmlContent(field)
+		if len(elements) > 0 || len(attributes) == 0 {
+			b.WriteString(indent + "    <xs:sequence>\n")
+			for _, element := range elements {
+				writeXSDElement(b, element, element.ValueRequired, false, depth+3)
+			}
+			b.WriteString(indent + "    </xs:sequence>\n")
+		}
+	}
+	for _, attribute := range attributes {
+		writeXSDAttribute(b, attribute, depth+2)
+	}
+	b.WriteString(indent + "  </xs:complexType>\n")
+	b.WriteString(indent + "</xs:element>\n")
+}
+
+// xmlContent returns the attributes and child elements of a field with complex content in an XML
+// instance: array items are "item" elements, a map has a single "key" entry and objects split
+// their fields into attributes and elements.
+func xmlContent(field *Field) (attributes []*Field, children []*Field) {
+	switch {
+	case field.ValueType == TypeArray:
+		items := field.ArrayItemField
+		switch {
+		case items != nil:
+			items = items.renamed(xsdItemElement)
+		case field.ArrayItemType != "":
+			items = &Field{ValueName: xsdItemElement, ValueType: field.ArrayItemType}
+		default:
+			items = Object(xsdItemElement, field.SubFields)
+		}
+		return nil, []*Field{items}
+	case field.AdditionalProperties:
+		switch {
+		case field.AdditionalPropertiesField != nil && field.AdditionalPropertiesField.SubFields != nil:
+			return nil, []*Field{Object("key", field.AdditionalPropertiesField.SubFields)}
+		case field.AdditionalPropertiesType != "":
+			return nil, []*Field{{ValueName: "key", ValueType: field.AdditionalPropertiesType}}
+		}
+		return nil, []*Field{{ValueName: "key", ValueType: TypeString, Value: "value"}}
+	}
+
+	for _, subField := range field.SubFields {
+		if isXMLAttribute(subField) {
+			attributes = append(attributes, subField)
+		} else {
+			children = append(children, subField)
+		}
+	}
+	return attributes, children
+}
+
+// isXMLAttribute reports whether field is emitted as an xs:attribute: it must be marked with
+// AsXMLAttribute and have simple content.
+func isXMLAttribute(field *Field) bool {
+	return field.ValueXMLAttribute && xsdSimpleType(field) != ""
+}
+
+func writeXSDAttribute(b *strings.Builder, field *Field, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	b.WriteString(indent)
+	b.WriteString(`<xs:attribute name="`)
+	b.WriteString(xmlEscape(field.ValueName))
+	b.WriteString(`"`)
+	if field.ValueAnyOf == nil {
+		b.WriteString(` type="` + xsdSimpleType(field) + `"`)
+	}
+	if field.ValueRequired {
+		b.WriteString(` use="required"`)
+	}
+
+	if field.ValueAnyOf != nil {
+		b.WriteString(">\n")
+		writeXSDDocumentation(b, field.ValueDescription, depth+1)
+		writeXSDEnumeration(b, field.ValueAnyOf, depth+1)
+		b.WriteString(indent + "</xs:attribute>\n")
+		return
+	}
+	if field.ValueDescription == "" {
+		b.WriteString("/>\n")
+		return
+	}
+	b.WriteString(">\n")
+	writeXSDDocumentation(b, field.ValueDescription, depth+1)
+	b.WriteString(indent + "</xs:attribute>\n")
+}
+
+// writeXSDEnumeration writes an anonymous xs:simpleType restricting the values to the given
+// options, each documented with its description. Null options have no XML representation and are
+// left out.
+func writeXSDEnumeration(b *strings.Builder, options []ConstDescription, depth int) {
+	indent := strings.Repeat("  ", depth)
+	b.WriteString(indent + "<xs:simpleType>\n")
+	b.WriteString(indent + `  <xs:restriction base="` + xsdEnumerationBase(options) + `">` + "\n")
+	for _, option := range options {
+		if option.Const == nil {
+			continue
+		}
+		value := xmlEscape(fmt.Sprint(option.Const))
+		if option.Description == "" {
+			b.WriteString(indent + `    <xs:enumeration value="` + value + `"/>` + "\n")
+			continue
+		}
+		b.WriteString(indent + `    <xs:enumeration value="` + value + `">` + "\n")
+		writeXSDDocumentation(b, option.Description, depth+3)
+		b.WriteString(indent + "    </xs:enumeration>\n")
+	}
+	b.WriteString(indent + "  </xs:restriction>\n")
+	b.WriteString(indent + "</xs:simpleType>\n")
+}
+
+// xsdEnumerationBase returns the XSD type shared by all non-null options, falling back to
+// xs:string when they mix types.
+func xsdEnumerationBase(options []ConstDescription) string {
+	base := ""
+	for _, option := range options {
+		var optionType string
+		switch option.Const.(type) {
+		case nil:
+			continue
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			optionType = "xs:integer"
+		case float32, float64:
+			optionType = "xs:decimal"
+		case bool:
+			optionType = "xs:boolean"
+		default:
+			optionType = "xs:string"
+		}
+		if base != "" && base != optionType {
+			return "xs:string"
+		}
+		base = optionType
+	}
+	if base == "" {
+		return "xs:string"
+	}
+	return base
+}
+
+// xsdSimpleType returns the built-in XSD type for fields with simple content, or "" for fields
+// that need a complex type. Strings with the date and date-time formats map to xs:date and
+// xs:dateTime, whose lexical forms match JSON Schema's. AnyOf fields report xs:string here; their elements and attributes
+// carry an xs:restriction instead, see writeXSDEnumeration.
+func xsdSimpleType(field *Field) string {
+	if field.ValueAnyOf != nil {
+		return "xs:string"
+	}
+	switch field.ValueType {
+	case TypeString:
+		switch field.ValueFormat {
+		case "date":
+			return "xs:date"
+		case "date-time":
+			return "xs:dateTime"
+		}
+		return "xs:string"
+	case TypeInteger:
+		return "xs:integer"
+	case TypeNumber:
+		return "xs:decimal"
+	case TypeBoolean:
+		return "xs:boolean"
+	}
+	return ""
+}
+
+func writeXSDDocumentation(b *strings.Builder, description string, depth int) {
+	if description == "" {
+		return
+	}
+	indent := strings.Repeat("  ", depth)
+	b.WriteString(indent + "<xs:annotation>\n")
+	b.WriteString(indent + "  <xs:documentation>" + xmlEscape(description) + "</xs:documentation>\n")
+	b.WriteString(indent + "</xs:annotation>\n")
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,310 @@
+package jobj
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGetXMLSchemaString(t *testing.T) {
+	s := &Schema{
+		Name:        "PressRelease",
+		Description: "Press release <extraction>",
+		Fields: []*Field{
+			Text("headline").Desc("The exact headline").Required(),
+			Int("word_count"),
+			ArrayOf("tags", TypeString),
+			Array("quotes", []*Field{
+				Text("speaker").Required(),
+				Float("confidence"),
+			}).Required(),
+			Object("source", []*Field{Bool("verified")}),
+			Map("counts", TypeInteger),
+		},
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" elementFormDefault="qualified">
+  <xs:element name="PressRelease">
+    <xs:annotation>
+      <xs:documentation>Press release &lt;extraction&gt;</xs:documentation>
+    </xs:annotation>
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="headline" type="xs:string">
+          <xs:annotation>
+            <xs:documentation>The exact headline</xs:documentation>
+          </xs:annotation>
+        </xs:element>
+        <xs:element name="word_count" minOccurs="0" type="xs:integer"/>
+        <xs:element name="tags" minOccurs="0">
+          <xs:complexType>
+            <xs:sequence>
+              <xs:element name="item" minOccurs="0" maxOccurs="unbounded" type="xs:string"/>
+            </xs:sequence>
+          </xs:complexType>
+        </xs:element>
+        <xs:element name="quotes">
+          <xs:complexType>
+            <xs:sequence>
+              <xs:element name="item" minOccurs="0" maxOccurs="unbounded">
+                <xs:complexType>
+                  <xs:sequence>
+                    <xs:element name="speaker" type="xs:string"/>
+                    <xs:element name="confidence" minOccurs="0" type="xs:decimal"/>
+                  </xs:sequence>
+                </xs:complexType>
+              </xs:element>
+            </xs:sequence>
+          </xs:complexType>
+        </xs:element>
+        <xs:element name="source" minOccurs="0">
+          <xs:complexType>
+            <xs:sequence>
+              <xs:element name="verified" minOccurs="0" type="xs:boolean"/>
+            </xs:sequence>
+          </xs:complexType>
+        </xs:element>
+        <xs:element name="counts" minOccurs="0">
+          <xs:complexType>
+            <xs:sequence>
+              <xs:any processContents="lax" minOccurs="0" maxOccurs="unbounded"/>
+            </xs:sequence>
+          </xs:complexType>
+        </xs:element>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>
+`
+	got := s.GetXMLSchemaString()
+	if got != expected {
+		t.Errorf("Unexpected XSD.\nwant:\n%s\ngot:\n%s", expected, got)
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(got))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("XSD is not well-formed XML: %v", err)
+		}
+	}
+}
+
+func TestGetXMLSchemaStringNestedArrays(t *testing.T) {
+	s := &Schema{
+		Name:   "Matrix",
+		Fields: []*Field{ArrayOfField("rows", ArrayOf("", TypeNumber)).Required()},
+	}
+
+	got := s.GetXMLSchemaString()
+	if strings.Count(got, `maxOccurs="unbounded"`) != 2 || !strings.Contains(got, `type="xs:decimal"`) {
+		t.Errorf("Expected two levels of repeated items, got %s", got)
+	}
+}
+
+func TestGetXMLSchemaStringAttributes(t *testing.T) {
+	s := &Schema{
+		Name: "Quotes",
+		Fields: []*Field{
+			Array("quotes", []*Field{
+				Int("id").AsXMLAttribute().Required(),
+				Text("lang").AsXMLAttribute().Desc("ISO 639-1 code"),
+				Text("text").Required(),
+			}),
+			Object("meta", []*Field{Text("source").AsXMLAttribute()}),
+			ArrayOf("tags", TypeString).AsXMLAttribute(),
+		},
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" elementFormDefault="qualified">
+  <xs:element name="Quotes">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="quotes" minOccurs="0">
+          <xs:complexType>
+            <xs:sequence>
+              <xs:element name="item" minOccurs="0" maxOccurs="unbounded">
+                <xs:complexType>
+                  <xs:sequence>
+                    <xs:element name="text" type="xs:string"/>
+                  </xs:sequence>
+                  <xs:attribute name="id" type="xs:integer" use="required"/>
+                  <xs:attribute name="lang" type="xs:string">
+                    <xs:annotation>
+                      <xs:documentation>ISO 639-1 code</xs:documentation>
+                    </xs:annotation>
+                  </xs:attribute>
+                </xs:complexType>
+              </xs:element>
+            </xs:sequence>
+          </xs:complexType>
+        </xs:element>
+        <xs:element name="meta" minOccurs="0">
+          <xs:complexType>
+            <xs:attribute name="source" type="xs:string"/>
+          </xs:complexType>
+        </xs:element>
+        <xs:element name="tags" minOccurs="0">
+          <xs:complexType>
+            <xs:sequence>
+              <xs:element name="item" minOccurs="0" maxOccurs="unbounded" type="xs:string"/>
+            </xs:sequence>
+          </xs:complexType>
+        </xs:element>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>
+`
+	got := s.GetXMLSchemaString()
+	if got != expected {
+		t.Errorf("Unexpected XSD.\nwant:\n%s\ngot:\n%s", expected, got)
+	}
+	if strings.Contains(s.GetSchemaString(), "attribute") {
+		t.Errorf("AsXMLAttribute should not affect the JSON schema")
+	}
+}
+
+func TestGetXMLSchemaStringEnumerations(t *testing.T) {
+	s := &Schema{
+		Name: "Review",
+		Fields: []*Field{
+			AnyOf("sentiment", []ConstDescription{
+				{Const: "positive", Description: "Favourable & upbeat"},
+				{Const: "negative"},
+				{Const: nil, Description: "Unknown"},
+			}).Desc("Overall tone").Required(),
+			AnyOf("stars", []ConstDescription{{Const: 1}, {Const: 5}}).AsXMLAttribute(),
+			AnyOf("mixed", []ConstDescription{{Const: 1}, {Const: "two"}}),
+		},
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" elementFormDefault="qualified">
+  <xs:element name="Review">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="sentiment">
+          <xs:annotation>
+            <xs:documentation>Overall tone</xs:documentation>
+          </xs:annotation>
+          <xs:simpleType>
+            <xs:restriction base="xs:string">
+              <xs:enumeration value="positive">
+                <xs:annotation>
+                  <xs:documentation>Favourable &amp; upbeat</xs:documentation>
+                </xs:annotation>
+              </xs:enumeration>
+              <xs:enumeration value="negative"/>
+            </xs:restriction>
+          </xs:simpleType>
+        </xs:element>
+        <xs:element name="mixed" minOccurs="0">
+          <xs:simpleType>
+            <xs:restriction base="xs:string">
+              <xs:enumeration value="1"/>
+              <xs:enumeration value="two"/>
+            </xs:restriction>
+          </xs:simpleType>
+        </xs:element>
+      </xs:sequence>
+      <xs:attribute name="stars">
+        <xs:simpleType>
+          <xs:restriction base="xs:integer">
+            <xs:enumeration value="1"/>
+            <xs:enumeration value="5"/>
+          </xs:restriction>
+        </xs:simpleType>
+      </xs:attribute>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>
+`
+	got := s.GetXMLSchemaString()
+	if got != expected {
+		t.Errorf("Unexpected XSD.\nwant:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestXSDSimpleTypes(t *testing.T) {
+	tests := []struct {
+		field    *Field
+		expected string
+	}{
+		{Text("name"), "xs:string"},
+		{Int("count"), "xs:integer"},
+		{Float("score"), "xs:decimal"},
+		{Bool("ok"), "xs:boolean"},
+		{Text("day").Format("date"), "xs:date"},
+		{Date("loose"), "xs:string"}, // Date accepts JsonDateTime layouts beyond xs:date
+		{DateTime("at"), "xs:dateTime"},
+		{Email("email"), "xs:string"},
+		{AnyOf("level", []ConstDescription{{Const: 1}}), "xs:string"},
+		{Object("nested", []*Field{Text("a")}), ""},
+		{ArrayOf("tags", TypeString), ""},
+	}
+	for _, test := range tests {
+		if got := xsdSimpleType(test.field); got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.field.ValueName, test.expected, got)
+		}
+	}
+
+	s := &Schema{Name: "Event", Fields: []*Field{ArrayOfField("days", Text("").Format("date"))}}
+	if !strings.Contains(s.GetXMLSchemaString(), `<xs:element name="item" minOccurs="0" maxOccurs="unbounded" type="xs:date"/>`) {
+		t.Errorf("Expected date array items to use xs:date, got %s", s.GetXMLSchemaString())
+	}
+}
+
+func TestToXMLExample(t *testing.T) {
+	s := &Schema{
+		Name: "PressRelease",
+		Fields: []*Field{
+			Text("headline").Required(),
+			Text("status").SetValue("draft & final"),
+			Int("id").AsXMLAttribute(),
+			AnyOf("sentiment", []ConstDescription{{Const: nil}, {Const: "positive"}}),
+			DateTime("published"),
+			ArrayOf("tags", TypeString),
+			Array("quotes", []*Field{Text("lang").AsXMLAttribute(), Text("text")}),
+			Map("counts", TypeInteger),
+			Object("source", []*Field{Bool("verified").AsXMLAttribute()}),
+		},
+	}
+
+	expected := `<PressRelease id="1">
+  <headline>string</headline>
+  <status>draft &amp; final</status>
+  <sentiment>positive</sentiment>
+  <published>2024-01-15T09:30:00Z</published>
+  <tags>
+    <item>string</item>
+  </tags>
+  <quotes>
+    <item lang="string">
+      <text>string</text>
+    </item>
+  </quotes>
+  <counts>
+    <key>1</key>
+  </counts>
+  <source verified="true"/>
+</PressRelease>
+`
+	got := s.ToXMLExample()
+	if got != expected {
+		t.Errorf("Unexpected XML example.\nwant:\n%s\ngot:\n%s", expected, got)
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(got))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Example is not well-formed XML: %v", err)
+		}
+	}
+}